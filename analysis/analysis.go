@@ -0,0 +1,112 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// Parse parses the package of the pass with the default parser, the other analyzers share its result.
+var Parse = &analysis.Analyzer{
+	Name:       "parsergo",
+	Doc:        "parse the annotations, structs, interfaces and funcs of the package",
+	Run:        runParse,
+	ResultType: reflect.TypeOf(new(astx.AstSpec)),
+}
+
+// Analyzers are the linters of parsergo, e.g. for `multichecker.Main(analysis.Analyzers...)`.
+var Analyzers = []*analysis.Analyzer{
+	Annotations,
+	Tags,
+	Services,
+}
+
+func runParse(pass *analysis.Pass) (interface{}, error) {
+	return parser.Parse(packageOf(pass)), nil
+}
+
+// packageOf wraps the pass into a loader package, its type information included.
+func packageOf(pass *analysis.Pass) *loader.Package {
+	filenames := make([]string, 0, len(pass.Files))
+	for _, file := range pass.Files {
+		filenames = append(filenames, pass.Fset.File(file.Pos()).Name())
+	}
+
+	imports := make(map[string]*packages.Package, len(pass.Pkg.Imports()))
+	for _, imported := range pass.Pkg.Imports() {
+		imports[imported.Path()] = &packages.Package{
+			ID:      imported.Path(),
+			Name:    imported.Name(),
+			PkgPath: imported.Path(),
+			Types:   imported,
+		}
+	}
+
+	return loader.FromPackage(&packages.Package{
+		ID:              pass.Pkg.Path(),
+		Name:            pass.Pkg.Name(),
+		PkgPath:         pass.Pkg.Path(),
+		GoFiles:         filenames,
+		CompiledGoFiles: filenames,
+		Imports:         imports,
+		Fset:            pass.Fset,
+		Syntax:          pass.Files,
+		Types:           pass.Pkg,
+		TypesInfo:       pass.TypesInfo,
+	})
+}
+
+func specOf(pass *analysis.Pass) *astx.AstSpec {
+	return pass.ResultOf[Parse].(*astx.AstSpec)
+}
+
+// posOf maps a resolved position back to the file set of the pass.
+func posOf(pass *analysis.Pass, p astx.Position) token.Pos {
+	if tf := fileOf(pass, p.File); tf != nil && p.Offset <= tf.Size() {
+		return tf.Pos(p.Offset)
+	}
+
+	return token.NoPos
+}
+
+func fileOf(pass *analysis.Pass, filename string) *token.File {
+	for _, file := range pass.Files {
+		if tf := pass.Fset.File(file.Pos()); tf != nil && tf.Name() == filename {
+			return tf
+		}
+	}
+
+	return nil
+}
+
+func astFileOf(pass *analysis.Pass, filename string) *ast.File {
+	for _, file := range pass.Files {
+		if tf := pass.Fset.File(file.Pos()); tf != nil && tf.Name() == filename {
+			return file
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzers(t *testing.T) {
+	if err := Annotations.Flags.Set("known", "Fixture"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		analyzer *analysis.Analyzer
+		pkg      string
+	}{
+		{analyzer: Annotations, pkg: "annotations"},
+		{analyzer: Tags, pkg: "tags"},
+		{analyzer: Services, pkg: "services"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.analyzer.Name, func(t *testing.T) {
+			analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), tt.analyzer, tt.pkg)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := analysis.Validate(append([]*analysis.Analyzer{Parse}, Analyzers...)); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/parser"
+	"golang.org/x/tools/go/analysis"
+)
+
+var Annotations = &analysis.Analyzer{
+	Name:     "parsergoannotations",
+	Doc:      "report unknown annotations, annotations on the wrong target and conflicting package annotations",
+	Requires: []*analysis.Analyzer{Parse},
+	Run:      runAnnotations,
+}
+
+var known string
+
+func init() {
+	Annotations.Flags.StringVar(&known, "known", "", "comma-separated annotation names accepted on any target")
+}
+
+func runAnnotations(pass *analysis.Pass) (interface{}, error) {
+	ps := specOf(pass).Package
+	accepted := make(map[string]bool)
+	for _, name := range strings.Split(known, ",") {
+		if name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "@")); name != "" {
+			accepted[name] = true
+		}
+	}
+
+	check := func(target parser.Target, annotations []*astx.Annotation) {
+		for _, anno := range annotations {
+			checkAnnotation(pass, accepted, target, anno)
+		}
+	}
+	for _, fs := range ps.Files {
+		check(parser.TargetPackage, fs.Annotations)
+		for _, ss := range fs.Structs {
+			check(parser.TargetStruct, ss.Annotations)
+			for _, field := range ss.Fields {
+				check(parser.TargetField, field.Annotations)
+			}
+			for _, method := range ss.Methods {
+				check(parser.TargetMethod, method.Annotations)
+			}
+		}
+		for _, is := range fs.Interfaces {
+			check(parser.TargetInterface, is.Annotations)
+			for _, method := range is.Methods {
+				check(parser.TargetMethod, method.Annotations)
+			}
+		}
+		for _, fn := range fs.Funcs {
+			check(parser.TargetFunc, fn.Annotations)
+		}
+	}
+
+	for _, diagnostic := range ps.Diagnostics {
+		related := make([]analysis.RelatedInformation, 0, len(diagnostic.Related))
+		for _, position := range diagnostic.Related {
+			related = append(related, analysis.RelatedInformation{
+				Pos:     posOf(pass, position),
+				Message: "previous declaration",
+			})
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     posOf(pass, diagnostic.Position),
+			Message: diagnostic.Message,
+			Related: related,
+		})
+	}
+
+	return nil, nil
+}
+
+func checkAnnotation(pass *analysis.Pass, accepted map[string]bool, target parser.Target, anno *astx.Annotation) {
+	if accepted[anno.Name] {
+		return
+	}
+
+	def := parser.LookupMarker(anno.Name)
+	switch {
+	case def == nil && anno.Marker:
+		// third-party markers, e.g. kubebuilder ones, are only checked once registered
+	case def == nil:
+		diagnostic := analysis.Diagnostic{
+			Pos:     namePos(pass, anno),
+			Message: fmt.Sprintf("unknown annotation @%s", anno.Name),
+		}
+		if suggestion := closestMarker(anno.Name); suggestion != "" && diagnostic.Pos.IsValid() {
+			diagnostic.Message += fmt.Sprintf(", did you mean @%s?", suggestion)
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("Replace with @%s", suggestion),
+				TextEdits: []analysis.TextEdit{{
+					Pos:     diagnostic.Pos,
+					End:     diagnostic.Pos + token.Pos(len(anno.Name)),
+					NewText: []byte(suggestion),
+				}},
+			}}
+		}
+		pass.Report(diagnostic)
+	case !def.Allows(target):
+		targets := make([]string, 0, len(def.Targets))
+		for _, allowed := range def.Targets {
+			targets = append(targets, string(allowed))
+		}
+		diagnostic := analysis.Diagnostic{
+			Pos:     namePos(pass, anno),
+			Message: fmt.Sprintf("annotation %s is not allowed on a %s, only on: %s", annotationName(anno), target, strings.Join(targets, ", ")),
+		}
+		if edit, ok := removeLine(pass, anno); ok {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Remove %s", annotationName(anno)),
+				TextEdits: []analysis.TextEdit{edit},
+			}}
+		}
+		pass.Report(diagnostic)
+	}
+}
+
+func annotationName(anno *astx.Annotation) string {
+	if anno.Marker {
+		return "+" + anno.Name
+	}
+
+	return "@" + anno.Name
+}
+
+// namePos is the position of the annotation name within a `//` comment, or the comment itself.
+func namePos(pass *analysis.Pass, anno *astx.Annotation) token.Pos {
+	pos := posOf(pass, anno.Position)
+	if !pos.IsValid() || !strings.HasPrefix(anno.Anno, "//") {
+		return pos
+	}
+	if i := strings.Index(anno.Anno, annotationName(anno)); i >= 0 {
+		return pos + token.Pos(i+1)
+	}
+
+	return pos
+}
+
+// removeLine deletes the `//` comment line holding only the annotation, its indentation included.
+func removeLine(pass *analysis.Pass, anno *astx.Annotation) (analysis.TextEdit, bool) {
+	pos := posOf(pass, anno.Position)
+	if !pos.IsValid() || !strings.HasPrefix(anno.Anno, "//") {
+		return analysis.TextEdit{}, false
+	}
+	text := strings.TrimSpace(strings.TrimPrefix(anno.Anno, "//"))
+	if !strings.HasPrefix(text, annotationName(anno)) {
+		return analysis.TextEdit{}, false
+	}
+
+	tf := pass.Fset.File(pos)
+	start := pos - token.Pos(anno.Position.Column-1)
+	end := pos + token.Pos(len(anno.Anno))
+	if tf.Offset(end) < tf.Size() {
+		end++ // the newline
+	}
+
+	return analysis.TextEdit{Pos: start, End: end}, true
+}
+
+// closestMarker returns the registered name at most two edits away from name, if any.
+func closestMarker(name string) string {
+	best, bestDistance := "", 3
+	for _, def := range parser.Definitions() {
+		if distance := levenshtein(strings.ToLower(name), strings.ToLower(def.Name)); distance < bestDistance {
+			best, bestDistance = def.Name, distance
+		}
+	}
+
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minOf(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+func minOf(values ...int) int {
+	lowest := values[0]
+	for _, v := range values[1:] {
+		if v < lowest {
+			lowest = v
+		}
+	}
+
+	return lowest
+}
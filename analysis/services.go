@@ -0,0 +1,211 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/photowey/parsergo/astx"
+	"golang.org/x/tools/go/analysis"
+)
+
+// ServiceAnnotation declares a service and, with the `interface` arg, the interface it implements:
+// `@Service("helloService", interface=HelloApi)` or `@Service(interface=api.HelloApi)`.
+const ServiceAnnotation = "Service"
+
+var Services = &analysis.Analyzer{
+	Name:     "parsergoservices",
+	Doc:      "report @Service types not implementing the interface they declare",
+	Requires: []*analysis.Analyzer{Parse},
+	Run:      runServices,
+}
+
+func runServices(pass *analysis.Pass) (interface{}, error) {
+	for _, fs := range specOf(pass).Package.Files {
+		for _, ss := range fs.Structs {
+			for _, anno := range ss.Annotations {
+				if anno.Name != ServiceAnnotation || anno.Marker {
+					continue
+				}
+				if arg := anno.Arg("interface"); arg != nil {
+					checkService(pass, fs, ss, anno, arg.Value)
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func checkService(pass *analysis.Pass, fs *astx.FileSpec, ss *astx.StructSpec, anno *astx.Annotation, name string) {
+	report := func(format string, args ...interface{}) {
+		pass.Report(analysis.Diagnostic{
+			Pos:     namePos(pass, anno),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	obj, ok := pass.Pkg.Scope().Lookup(ss.Name).(*types.TypeName)
+	if !ok {
+		return
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return
+	}
+
+	ifaceObj := fs.LookupType(pass.Pkg, name)
+	if ifaceObj == nil {
+		report("@Service %s declares an unknown interface %s", ss.Name, name)
+		return
+	}
+	iface, ok := ifaceObj.Type().Underlying().(*types.Interface)
+	if !ok {
+		report("@Service %s declares %s, which is not an interface", ss.Name, name)
+		return
+	}
+
+	ptr := types.NewPointer(named)
+	if types.Implements(named, iface) || types.Implements(ptr, iface) {
+		return
+	}
+
+	missing := make([]*types.Func, 0)
+	mismatched := make([]string, 0)
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		found, _, _ := types.LookupFieldOrMethod(ptr, false, method.Pkg(), method.Name())
+		switch fn := found.(type) {
+		case nil:
+			missing = append(missing, method)
+		case *types.Func:
+			if !types.Identical(fn.Type(), method.Type()) {
+				mismatched = append(mismatched, method.Name())
+			}
+		default:
+			mismatched = append(mismatched, method.Name())
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for _, method := range missing {
+		names = append(names, method.Name())
+	}
+	diagnostic := analysis.Diagnostic{
+		Pos:     namePos(pass, anno),
+		Message: fmt.Sprintf("@Service %s does not implement %s", ss.Name, name),
+	}
+	if len(missing) > 0 {
+		diagnostic.Message += fmt.Sprintf(": missing %s", strings.Join(names, ", "))
+	}
+	if len(mismatched) > 0 {
+		diagnostic.Message += fmt.Sprintf(": wrong signature for %s", strings.Join(mismatched, ", "))
+	}
+	if stubs, ok := methodStubs(pass, fs, ss.Name, missing); ok && len(mismatched) == 0 {
+		if decl := declOf(pass, fs, ss); decl != nil {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("Add the missing methods of %s", name),
+				TextEdits: []analysis.TextEdit{{
+					Pos:     decl.End(),
+					End:     decl.End(),
+					NewText: stubs,
+				}},
+			}}
+		}
+	}
+	pass.Report(diagnostic)
+}
+
+// methodStubs renders pointer-receiver stubs of the methods, unless one of them needs a package the file doesn't import.
+func methodStubs(pass *analysis.Pass, fs *astx.FileSpec, typeName string, methods []*types.Func) ([]byte, bool) {
+	if len(methods) == 0 {
+		return nil, false
+	}
+
+	imported := true
+	qualifier := func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		if is := fs.ImportByPath(pkg.Path()); is != nil {
+			if is.Dot {
+				return ""
+			}
+			return is.Alias
+		}
+		imported = false
+		return pkg.Name()
+	}
+
+	receiver := string(unicode.ToLower([]rune(typeName)[0]))
+	var b bytes.Buffer
+	for _, method := range methods {
+		sig := method.Type().(*types.Signature)
+		params := make([]string, 0, sig.Params().Len())
+		for i := 0; i < sig.Params().Len(); i++ {
+			param := sig.Params().At(i)
+			typ := types.TypeString(param.Type(), qualifier)
+			if sig.Variadic() && i == sig.Params().Len()-1 {
+				typ = "..." + types.TypeString(param.Type().(*types.Slice).Elem(), qualifier)
+			}
+			paramName := param.Name()
+			if paramName == "" || paramName == receiver {
+				paramName = fmt.Sprintf("p%d", i)
+			}
+			params = append(params, paramName+" "+typ)
+		}
+		results := make([]string, 0, sig.Results().Len())
+		for i := 0; i < sig.Results().Len(); i++ {
+			results = append(results, types.TypeString(sig.Results().At(i).Type(), qualifier))
+		}
+
+		fmt.Fprintf(&b, "\n\nfunc (%s *%s) %s(%s)", receiver, typeName, method.Name(), strings.Join(params, ", "))
+		switch len(results) {
+		case 0:
+		case 1:
+			b.WriteString(" " + results[0])
+		default:
+			b.WriteString(" (" + strings.Join(results, ", ") + ")")
+		}
+		b.WriteString(" {\n\tpanic(\"not implemented\")\n}")
+	}
+
+	return b.Bytes(), imported
+}
+
+func declOf(pass *analysis.Pass, fs *astx.FileSpec, ss *astx.StructSpec) *ast.GenDecl {
+	file := astFileOf(pass, fs.Path)
+	if file == nil {
+		return nil
+	}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok {
+			for _, spec := range gen.Specs {
+				if spec == ss.Node {
+					return gen
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/parser"
+	"golang.org/x/tools/go/analysis"
+)
+
+var Tags = &analysis.Analyzer{
+	Name: "parsergotags",
+	Doc:  "report struct tags parsergo cannot parse, suggesting the canonical `key:\"value\"` form",
+	Run:  runTags,
+}
+
+func runTags(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			st, ok := node.(*ast.StructType)
+			if !ok || st.Fields == nil {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				if field.Tag != nil {
+					checkTag(pass, field.Tag)
+				}
+			}
+			return true
+		})
+	}
+
+	return nil, nil
+}
+
+func checkTag(pass *analysis.Pass, tag *ast.BasicLit) {
+	_, err := parser.ParseTag(tag.Value)
+	if err == nil {
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:     tag.Pos(),
+		End:     tag.End(),
+		Message: "malformed struct tag: " + err.Error(),
+	}
+	if canonical, ok := canonicalTag(tag.Value); ok {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Rewrite as " + canonical,
+			TextEdits: []analysis.TextEdit{{
+				Pos:     tag.Pos(),
+				End:     tag.End(),
+				NewText: []byte(canonical),
+			}},
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+// canonicalTag leniently parses `key: "value"`, `key:'value'`, `key=value` and friends,
+// and renders the canonical literal when every pair is recovered.
+func canonicalTag(literal string) (string, bool) {
+	tag, err := strconv.Unquote(literal)
+	if err != nil {
+		return "", false
+	}
+
+	pairs := make([]string, 0)
+	seen := make(map[string]bool)
+	for {
+		tag = strings.TrimLeft(tag, " \t")
+		if tag == "" {
+			break
+		}
+
+		i := strings.IndexAny(tag, ":= \t")
+		if i <= 0 || strings.ContainsAny(tag[:i], `"'`) {
+			return "", false
+		}
+		key := tag[:i]
+		tag = strings.TrimLeft(tag[i:], " \t")
+		if tag == "" || (tag[0] != ':' && tag[0] != '=') {
+			return "", false
+		}
+		tag = strings.TrimLeft(tag[1:], " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(tag, `"`):
+			end := 1
+			for end < len(tag) && tag[end] != '"' {
+				if tag[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(tag) {
+				return "", false
+			}
+			if value, err = strconv.Unquote(tag[:end+1]); err != nil {
+				return "", false
+			}
+			tag = tag[end+1:]
+		case strings.HasPrefix(tag, "'"):
+			end := strings.Index(tag[1:], "'")
+			if end < 0 {
+				return "", false
+			}
+			value = tag[1 : end+1]
+			tag = tag[end+2:]
+		default:
+			end := strings.IndexAny(tag, " \t")
+			if end < 0 {
+				end = len(tag)
+			}
+			value = tag[:end]
+			tag = tag[end:]
+		}
+
+		if seen[key] {
+			return "", false
+		}
+		seen[key] = true
+		pairs = append(pairs, key+":"+strconv.Quote(value))
+	}
+
+	canonical := strings.Join(pairs, " ")
+	if strings.Contains(canonical, "`") {
+		return strconv.Quote(canonical), true
+	}
+
+	return "`" + canonical + "`", true
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package annotations exercises the annotation linter.
+//
+// @Layer(domain)
+// @Module("annotations")
+// @Version("1")
+// @ComponentScan("annotations")
+package annotations
+
+// HelloService says hello.
+//
+// @Servce // want `unknown annotation @Servce, did you mean @Service\?`
+type HelloService struct {
+	// Name is the greeting name.
+	//
+	// @Layer(field) // want `annotation @Layer is not allowed on a field, only on: package`
+	Name string
+}
+
+// Fixture is accepted through the known flag.
+//
+// @Fixture
+// @Unheard // want `unknown annotation @Unheard`
+type Fixture struct{}
+
+// Bootstrap runs the application.
+//
+// +kubebuilder:object:root=true
+func Bootstrap() {}
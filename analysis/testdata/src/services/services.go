@@ -0,0 +1,58 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package services
+
+import (
+	"fmt"
+)
+
+type Greeter interface {
+	Greet(name string) string
+	Farewell(names ...string) (string, error)
+}
+
+// EnglishService greets in English.
+//
+// @Service("english", interface=Greeter) // want `@Service EnglishService does not implement Greeter: missing Farewell`
+type EnglishService struct{}
+
+func (s *EnglishService) Greet(name string) string {
+	return fmt.Sprintf("Hello %s", name)
+}
+
+// CompleteService implements Greeter.
+//
+// @Service(interface=Greeter)
+type CompleteService struct{}
+
+func (s CompleteService) Greet(name string) string {
+	return name
+}
+
+func (s CompleteService) Farewell(names ...string) (string, error) {
+	return "", nil
+}
+
+// NamedService misses String.
+//
+// @Service(interface=fmt.Stringer) // want `@Service NamedService does not implement fmt.Stringer: missing String`
+type NamedService struct{}
+
+// LostService declares an unknown interface.
+//
+// @Service(interface=Missing) // want `@Service LostService declares an unknown interface Missing`
+type LostService struct{}
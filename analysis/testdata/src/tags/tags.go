@@ -0,0 +1,25 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tags
+
+// User carries every kind of tag.
+type User struct {
+	ID    int    `json: "id"`              // want `malformed struct tag: bad syntax for struct tag pair`
+	Name  string `json:"name" db:'name'`   // want `malformed struct tag: bad syntax for struct tag pair`
+	Age   int    `json:"age" json:"years"` // want `malformed struct tag: duplicate struct tag key json`
+	Email string `json:"email" validate:"email"`
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arch
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/photowey/parsergo/loader"
+)
+
+func TestCheck(t *testing.T) {
+	conf, err := LoadConfig(filepath.Join("..", "tests", "archx", "arch.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/tests/archx/...")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		rules []*Rule
+		want  []string
+	}{
+		{
+			name:  "yaml",
+			rules: conf.Rules,
+			want:  []string{"domain-is-pure"},
+		},
+		{
+			name: "go",
+			rules: []*Rule{
+				{Name: "api-is-thin", From: Layer("api"), Deny: []Selector{Path("./tests/archx/domain")}},
+				{Name: "infra-is-leaf", From: Path("./tests/archx/infra/..."), Deny: []Selector{Path("./...")}},
+			},
+			want: []string{"api-is-thin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := Check(roots, tt.rules...)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if len(violations) != len(tt.want) {
+				t.Fatalf("Check() = %v, want %v", violations, tt.want)
+			}
+			for i, v := range violations {
+				if v.Rule.Name != tt.want[i] || !v.Position.IsValid() {
+					t.Errorf("Check()[%d] = %s, want rule %s", i, v, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: "rules:\n  - name: r\n    from: {layer: domain}\n    deny: [{layer: infra}]\n",
+		},
+		{
+			name:    "no deny",
+			data:    "rules:\n  - name: r\n    from: {layer: domain}\n",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			data:    "rules: [",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
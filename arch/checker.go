@@ -0,0 +1,137 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arch
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/depgraph"
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+)
+
+type Violation struct {
+	Rule     *Rule
+	From     string
+	To       string
+	Position astx.Position // the import spec
+}
+
+func (v *Violation) String() string {
+	msg := fmt.Sprintf("%s: [%s] %s must not import %s", v.Position, v.Rule.Name, v.From, v.To)
+	if v.Rule.Message != "" {
+		msg += ": " + v.Rule.Message
+	}
+
+	return msg
+}
+
+type packageInfo struct {
+	path  string
+	layer string
+}
+
+// Check reports every import of roots violating one of rules, ordered by position.
+func Check(roots []*loader.Package, rules ...*Rule) ([]*Violation, error) {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	g := depgraph.Build(roots)
+	infos := make(map[string]*packageInfo, len(g.Nodes))
+	info := func(path string) *packageInfo {
+		if infos[path] == nil {
+			infos[path] = &packageInfo{path: path}
+			if node := g.Nodes[path]; node != nil && node.Package != nil && g.IsLocal(path) {
+				infos[path].layer = layerOf(node.Package)
+			}
+		}
+		return infos[path]
+	}
+
+	violations := make([]*Violation, 0)
+	for _, root := range roots {
+		if root.IsTestMain() {
+			continue
+		}
+		from := info(root.PkgPath)
+		for _, rule := range rules {
+			if !rule.From.match(g.Module, from) {
+				continue
+			}
+			for _, path := range g.Imports(root.PkgPath) {
+				for _, deny := range rule.Deny {
+					if deny.match(g.Module, info(path)) {
+						violations = append(violations, violationsOf(root, rule, path)...)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		pi, pj := violations[i].Position, violations[j].Position
+		if pi.File != pj.File {
+			return pi.File < pj.File
+		}
+		return pi.Offset < pj.Offset
+	})
+
+	return violations, nil
+}
+
+func layerOf(pkg *loader.Package) string {
+	spec := parser.Parse(pkg)
+	for _, anno := range spec.Package.Annotations {
+		if anno.Name == LayerAnnotation {
+			return anno.Value()
+		}
+	}
+
+	return ""
+}
+
+// violationsOf reports one violation per file of pkg importing path.
+func violationsOf(pkg *loader.Package, rule *Rule, path string) []*Violation {
+	pkg.NeedSyntax()
+
+	out := make([]*Violation, 0, 1)
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			if importPath, err := strconv.Unquote(spec.Path.Value); err != nil || importPath != path {
+				continue
+			}
+			out = append(out, &Violation{
+				Rule:     rule,
+				From:     pkg.PkgPath,
+				To:       path,
+				Position: astx.NewPosition(pkg.Fset, spec.Pos(), spec.End()),
+			})
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, &Violation{Rule: rule, From: pkg.PkgPath, To: path})
+	}
+
+	return out
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LayerAnnotation is the package annotation naming the layer of a package, e.g. `// @Layer(domain)`.
+const LayerAnnotation = "Layer"
+
+// Selector matches packages by layer, by path pattern, or by both.
+// A path pattern starting with `./` is relative to the main module; a `/...` suffix also matches the subpackages.
+type Selector struct {
+	Layer string `yaml:"layer,omitempty"`
+	Path  string `yaml:"path,omitempty"`
+}
+
+func Layer(name string) Selector {
+	return Selector{Layer: name}
+}
+
+func Path(pattern string) Selector {
+	return Selector{Path: pattern}
+}
+
+func (s Selector) String() string {
+	switch {
+	case s.Layer != "" && s.Path != "":
+		return fmt.Sprintf("@Layer(%s) %s", s.Layer, s.Path)
+	case s.Layer != "":
+		return fmt.Sprintf("@Layer(%s)", s.Layer)
+	default:
+		return s.Path
+	}
+}
+
+func (s Selector) match(module string, pkg *packageInfo) bool {
+	if s.Layer != "" && s.Layer != pkg.layer {
+		return false
+	}
+	if s.Path != "" && !matchPath(module, s.Path, pkg.path) {
+		return false
+	}
+
+	return s.Layer != "" || s.Path != ""
+}
+
+// Rule forbids the packages selected by From to import any package selected by Deny.
+type Rule struct {
+	Name    string     `yaml:"name"`
+	From    Selector   `yaml:"from"`
+	Deny    []Selector `yaml:"deny"`
+	Message string     `yaml:"message,omitempty"`
+}
+
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("arch: rule without a name")
+	}
+	if r.From.Layer == "" && r.From.Path == "" {
+		return fmt.Errorf("arch: rule %s: empty from selector", r.Name)
+	}
+	if len(r.Deny) == 0 {
+		return fmt.Errorf("arch: rule %s: no deny selector", r.Name)
+	}
+	for _, deny := range r.Deny {
+		if deny.Layer == "" && deny.Path == "" {
+			return fmt.Errorf("arch: rule %s: empty deny selector", r.Name)
+		}
+	}
+
+	return nil
+}
+
+type Config struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+func ParseConfig(data []byte) (*Config, error) {
+	conf := &Config{}
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("arch: %w", err)
+	}
+	for _, rule := range conf.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return conf, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseConfig(data)
+}
+
+func matchPath(module, pattern, path string) bool {
+	if strings.HasPrefix(pattern, "./") {
+		pattern = strings.TrimSuffix(module+"/"+strings.TrimPrefix(pattern, "./"), "/")
+	}
+	if prefix := strings.TrimSuffix(pattern, "/..."); prefix != pattern {
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+
+	return path == pattern
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx
+
+func (anno *Annotation) Arg(name string) *AnnotationArg {
+	for _, arg := range anno.Args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+
+	return nil
+}
+
+// Value is the first positional arg, or empty.
+func (anno *Annotation) Value() string {
+	if arg := anno.Arg(""); arg != nil {
+		return arg.Value
+	}
+
+	return ""
+}
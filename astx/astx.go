@@ -17,17 +17,21 @@
 package astx
 
 import (
+	"go/token"
 	"path/filepath"
 
 	"github.com/photowey/parsergo/loader"
 )
 
+// NewAstx wraps the syntax of a file of the package.
+// Ast is nil when the file couldn't be read or parsed, the error being recorded in the package Errors.
 func NewAstx(path string, lpkg *loader.Package) *Astx {
 	pkg := lpkg.PkgPath
 	name := filepath.Base(path)
-	af, err := BuildAstFile(path)
-	if err != nil {
-		panic(err)
+	af := lpkg.SyntaxFor(path)
+	fset := lpkg.Fset
+	if fset == nil {
+		fset = token.NewFileSet()
 	}
 	astx := &Astx{
 		Package: lpkg,
@@ -35,6 +39,7 @@ func NewAstx(path string, lpkg *loader.Package) *Astx {
 		Name:    name,
 		Pkg:     pkg,
 		Ast:     af,
+		Fset:    fset,
 	}
 
 	return astx
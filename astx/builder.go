@@ -35,3 +35,12 @@ func BuildAstFile(path string) (*ast.File, error) {
 
 	return parser.ParseFile(fset, fileName, string(bytes), parser.ParseComments)
 }
+
+func BuildAstFileWithFset(fset *token.FileSet, path string) (*ast.File, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.ParseFile(fset, path, bytes, parser.ParseComments)
+}
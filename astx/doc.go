@@ -0,0 +1,76 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx
+
+// DocSpec is a parsed doc comment, with prose, directives, markers and annotations kept apart.
+type DocSpec struct {
+	Raw         []string
+	Summary     string
+	Body        []string // paragraphs after the summary sentence
+	Directives  []*Directive
+	Markers     []*Annotation
+	Annotations []*Annotation
+	Position    Position
+}
+
+// Directive is a `//tool:name args` comment line, e.g. `//go:generate` or `//parsergo:skip`.
+type Directive struct {
+	Tool     string
+	Name     string
+	Args     string
+	Text     string
+	Position Position
+}
+
+func (ds *DocSpec) Text() string {
+	if ds == nil || ds.Summary == "" {
+		return ""
+	}
+
+	text := ds.Summary
+	for _, paragraph := range ds.Body {
+		text += "\n\n" + paragraph
+	}
+
+	return text
+}
+
+func (ds *DocSpec) Directive(tool, name string) *Directive {
+	if ds == nil {
+		return nil
+	}
+	for _, directive := range ds.Directives {
+		if directive.Tool == tool && directive.Name == name {
+			return directive
+		}
+	}
+
+	return nil
+}
+
+func (ds *DocSpec) Annotation(name string) *Annotation {
+	if ds == nil {
+		return nil
+	}
+	for _, anno := range ds.Annotations {
+		if anno.Name == name {
+			return anno
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx
+
+import (
+	"sort"
+)
+
+// Extensions holds the custom data plugins attach to a spec.
+type Extensions struct {
+	values map[string]interface{}
+}
+
+// ExtensionKey is a typed key into Extensions.
+type ExtensionKey[T any] struct {
+	Name string
+}
+
+func NewExtensionKey[T any](name string) ExtensionKey[T] {
+	return ExtensionKey[T]{Name: name}
+}
+
+func (k ExtensionKey[T]) Get(ext *Extensions) (T, bool) {
+	var zero T
+	if ext == nil || ext.values == nil {
+		return zero, false
+	}
+	value, ok := ext.values[k.Name].(T)
+	if !ok {
+		return zero, false
+	}
+
+	return value, true
+}
+
+func (k ExtensionKey[T]) Set(ext *Extensions, value T) {
+	if ext.values == nil {
+		ext.values = make(map[string]interface{})
+	}
+	ext.values[k.Name] = value
+}
+
+func (ext *Extensions) Has(name string) bool {
+	_, ok := ext.values[name]
+
+	return ok
+}
+
+func (ext *Extensions) Names() []string {
+	names := make([]string, 0, len(ext.values))
+	for name := range ext.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// Position is a resolved source range of a spec.
+type Position struct {
+	File      string
+	Offset    int
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+}
+
+func NewPosition(fset *token.FileSet, pos, end token.Pos) Position {
+	if fset == nil || !pos.IsValid() {
+		return Position{}
+	}
+
+	start := fset.Position(pos)
+	p := Position{
+		File:   start.Filename,
+		Offset: start.Offset,
+		Line:   start.Line,
+		Column: start.Column,
+	}
+	if end.IsValid() {
+		stop := fset.Position(end)
+		p.EndLine = stop.Line
+		p.EndColumn = stop.Column
+	}
+
+	return p
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// LineDirective renders a `//line file:line:column` directive pointing at the position.
+func (p Position) LineDirective() string {
+	return fmt.Sprintf("//line %s:%d:%d", p.File, p.Line, p.Column)
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
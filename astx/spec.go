@@ -17,6 +17,7 @@
 package astx
 
 import (
+	"go/ast"
 	"go/token"
 )
 
@@ -24,15 +25,51 @@ type AstSpec struct {
 	ID      string
 	Name    string
 	PkgPath string
-	Pkgs    []*PackageSpec
+	Test    string // test variant: "", "test" or "xtest"
+	Package *PackageSpec
 }
 
+// PackageSpec aggregates the specs of every parsed file of a package.
 type PackageSpec struct {
-	Pkg        string
-	Alias      string
-	Structs    []*StructSpec
-	Interfaces []*InterfaceSpec
-	Funcs      []*FuncSpec
+	Pkg         string
+	Alias       string
+	Test        string
+	Doc         *DocSpec
+	Annotations []*Annotation // merged from the package doc comments of every file
+	Diagnostics []*Diagnostic
+	Files       []*FileSpec
+	Structs     []*StructSpec
+	Interfaces  []*InterfaceSpec
+	Funcs       []*FuncSpec
+	Extensions  Extensions
+}
+
+type FileSpec struct {
+	Pkg         string
+	Alias       string
+	Test        string
+	Path        string
+	Name        string
+	Constraints []string // build constraint expressions, e.g. `linux && amd64`
+	Imports     []*ImportSpec
+	Position    Position
+	Doc         *DocSpec // the package doc comment of the file
+	Annotations []*Annotation
+	Structs     []*StructSpec
+	Interfaces  []*InterfaceSpec
+	Funcs       []*FuncSpec
+	Extensions  Extensions
+}
+
+type ImportSpec struct {
+	Path  string
+	Name  string // the explicit name, if any
+	Alias string // the name the file refers to the package by
+	Dot   bool
+	Blank bool
+	// Names are the exported type names a dot import brings in scope, nil when the package was not loaded.
+	Names    []string
+	Position Position
 }
 
 type StructSpec struct {
@@ -40,44 +77,71 @@ type StructSpec struct {
 	Alias       string
 	Name        string
 	Type        token.Pos
+	Position    Position
 	Comments    []string
+	Doc         *DocSpec
 	Fields      []*FieldSpec
 	Methods     []*MethodSpec
 	Annotations []*Annotation
+	Node        ast.Node // the *ast.TypeSpec
+	Extensions  Extensions
 }
 
 type FieldSpec struct {
-	Struct string
-	Name   string
-	Type   string
-	Ptr    bool
-	Tags   []*TagSpec
+	Struct      string
+	Name        string
+	Type        string
+	TypeRef     *TypeRef
+	Ptr         bool
+	Embedded    bool
+	Position    Position
+	Doc         *DocSpec
+	Comment     *DocSpec // the trailing line comment
+	Tags        []*TagSpec
+	Annotations []*Annotation
+	Node        ast.Node // the *ast.Field
+	Extensions  Extensions
 }
 
 type InterfaceSpec struct {
 	Pkg         string
 	Name        string
 	Type        token.Pos
+	Position    Position
 	Comments    []string
+	Doc         *DocSpec
 	Methods     []*MethodSpec
+	Embeds      []*TypeRef // the embedded interfaces and constraint terms
 	Annotations []*Annotation
+	Node        ast.Node // the *ast.TypeSpec
+	Extensions  Extensions
 }
 
 type MethodSpec struct {
-	Pkg      string
-	Struct   string
-	Name     string
-	Comments []string
-	Params   []*ParamSpec
-	Returns  []*ReturnSpec
+	Pkg         string
+	Struct      string // the receiver, or the interface declaring the method
+	Name        string
+	Position    Position
+	Comments    []string
+	Doc         *DocSpec
+	Params      []*ParamSpec
+	Returns     []*ReturnSpec
+	Annotations []*Annotation
+	Node        ast.Node // the *ast.FuncDecl, or the *ast.Field of an interface method
+	Extensions  Extensions
 }
 
 type FuncSpec struct {
-	Pkg      string
-	Name     string
-	Comments []string
-	Params   []*ParamSpec
-	Returns  []*ReturnSpec
+	Pkg         string
+	Name        string
+	Position    Position
+	Comments    []string
+	Doc         *DocSpec
+	Params      []*ParamSpec
+	Returns     []*ReturnSpec
+	Annotations []*Annotation
+	Node        ast.Node // the *ast.FuncDecl
+	Extensions  Extensions
 }
 
 type ParamSpec struct {
@@ -86,6 +150,8 @@ type ParamSpec struct {
 	Name     string
 	Ptr      bool
 	Type     string
+	TypeRef  *TypeRef
+	Position Position
 }
 
 type ReturnSpec struct {
@@ -94,19 +160,32 @@ type ReturnSpec struct {
 	Name     string
 	Ptr      bool
 	Type     string
+	TypeRef  *TypeRef
+	Position Position
 }
 
 type Annotation struct {
-	Pkg    string
-	Anno   string
-	Alias  string
+	Pkg      string
+	Anno     string
+	Alias    string
+	Name     string
+	Values   string // maybe json ?
+	Marker   bool   // a `+name=values` marker rather than an `@Name(values)` annotation
+	Args     []*AnnotationArg
+	Position Position
+}
+
+// AnnotationArg is a parsed value of an annotation or marker; positional values have no name.
+type AnnotationArg struct {
 	Name   string
-	Values string // maybe json ?
+	Value  string
+	Values []string // the items of a `{a,b}` list
 }
 
 type TagSpec struct {
-	Field string
-	Tags  []*Tag
+	Field    string
+	Position Position
+	Tags     []*Tag
 }
 type Tag struct {
 	Name  string
@@ -0,0 +1,193 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx
+
+import (
+	"go/types"
+	"strings"
+)
+
+type TypeKind string
+
+const (
+	KindBasic     TypeKind = "basic"
+	KindNamed     TypeKind = "named"
+	KindPointer   TypeKind = "pointer"
+	KindSlice     TypeKind = "slice"
+	KindArray     TypeKind = "array"
+	KindMap       TypeKind = "map"
+	KindChan      TypeKind = "chan"
+	KindFunc      TypeKind = "func"
+	KindStruct    TypeKind = "struct"
+	KindInterface TypeKind = "interface"
+	KindEllipsis  TypeKind = "ellipsis"
+	KindTypeParam TypeKind = "typeparam"
+	KindUnknown   TypeKind = "unknown"
+)
+
+// TypeRef is a resolved type expression.
+// Named types carry the import path of their package, whatever alias the file used.
+type TypeRef struct {
+	Kind     TypeKind
+	Name     string
+	PkgPath  string
+	Alias    string
+	Len      string
+	Key      *TypeRef
+	Elem     *TypeRef
+	TypeArgs []*TypeRef
+	Expr     string
+}
+
+// Qualifier renders the package qualifier for an import path, e.g. `Imports.NeedImport`.
+// An empty result leaves the name unqualified.
+type Qualifier func(pkgPath string) string
+
+func (t *TypeRef) String() string {
+	return t.Expr
+}
+
+func (t *TypeRef) IsPtr() bool {
+	return t != nil && t.Kind == KindPointer
+}
+
+// Deref strips every pointer indirection.
+func (t *TypeRef) Deref() *TypeRef {
+	for t != nil && t.Kind == KindPointer {
+		t = t.Elem
+	}
+
+	return t
+}
+
+// PkgPaths collects the import paths of every named type referenced by t.
+func (t *TypeRef) PkgPaths() []string {
+	paths := make([]string, 0)
+	t.walk(func(ref *TypeRef) {
+		if ref.Kind == KindNamed && ref.PkgPath != "" {
+			paths = append(paths, ref.PkgPath)
+		}
+	})
+
+	return paths
+}
+
+// Render prints the type with package qualifiers computed by q,
+// so the type can be referenced from another package.
+func (t *TypeRef) Render(q Qualifier) string {
+	if t == nil {
+		return ""
+	}
+
+	switch t.Kind {
+	case KindBasic:
+		return t.Name
+	case KindNamed:
+		name := t.Name
+		if t.PkgPath != "" && q != nil {
+			if qualifier := q(t.PkgPath); qualifier != "" {
+				name = qualifier + "." + name
+			}
+		}
+		if len(t.TypeArgs) > 0 {
+			args := make([]string, 0, len(t.TypeArgs))
+			for _, arg := range t.TypeArgs {
+				args = append(args, arg.Render(q))
+			}
+			name += "[" + strings.Join(args, ", ") + "]"
+		}
+		return name
+	case KindPointer:
+		return "*" + t.Elem.Render(q)
+	case KindSlice:
+		return "[]" + t.Elem.Render(q)
+	case KindArray:
+		return "[" + t.Len + "]" + t.Elem.Render(q)
+	case KindMap:
+		return "map[" + t.Key.Render(q) + "]" + t.Elem.Render(q)
+	case KindEllipsis:
+		return "..." + t.Elem.Render(q)
+	case KindChan:
+		if strings.HasPrefix(t.Expr, "<-chan") {
+			return "<-chan " + t.Elem.Render(q)
+		}
+		if strings.HasPrefix(t.Expr, "chan<-") {
+			return "chan<- " + t.Elem.Render(q)
+		}
+		return "chan " + t.Elem.Render(q)
+	}
+
+	return t.Expr
+}
+
+func (t *TypeRef) walk(fn func(ref *TypeRef)) {
+	if t == nil {
+		return
+	}
+
+	fn(t)
+	t.Key.walk(fn)
+	t.Elem.walk(fn)
+	for _, arg := range t.TypeArgs {
+		arg.walk(fn)
+	}
+}
+
+func (fs *FileSpec) ImportByAlias(alias string) *ImportSpec {
+	for _, is := range fs.Imports {
+		if is.Alias == alias {
+			return is
+		}
+	}
+
+	return nil
+}
+
+func (fs *FileSpec) ImportByPath(path string) *ImportSpec {
+	for _, is := range fs.Imports {
+		if is.Path == path {
+			return is
+		}
+	}
+
+	return nil
+}
+
+// LookupType resolves `Name` in the scope of pkg and `alias.Name` through the imports of the file.
+func (fs *FileSpec) LookupType(pkg *types.Package, name string) *types.TypeName {
+	scope := pkg.Scope()
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		is := fs.ImportByAlias(name[:dot])
+		if is == nil {
+			return nil
+		}
+		scope = nil
+		for _, imported := range pkg.Imports() {
+			if imported.Path() == is.Path {
+				scope = imported.Scope()
+			}
+		}
+		if scope == nil {
+			return nil
+		}
+		name = name[dot+1:]
+	}
+
+	obj, _ := scope.Lookup(name).(*types.TypeName)
+
+	return obj
+}
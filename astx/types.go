@@ -18,6 +18,8 @@ package astx
 
 import (
 	"go/ast"
+	"go/token"
+	"go/types"
 
 	"github.com/photowey/parsergo/loader"
 )
@@ -28,4 +30,19 @@ type Astx struct {
 	Name string
 	Pkg  string
 	Ast  *ast.File
+	Fset *token.FileSet
+}
+
+// TypesInfo type-checks the package on first use.
+func (aw *Astx) TypesInfo() *types.Info {
+	if aw.Package == nil {
+		return nil
+	}
+	aw.Package.NeedTypesInfo()
+
+	return aw.Package.TypesInfo
+}
+
+func (aw *Astx) Position(node ast.Node) Position {
+	return NewPosition(aw.Fset, node.Pos(), node.End())
 }
@@ -0,0 +1,227 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// Cursor is the position of a Walk in the syntax tree of a file.
+type Cursor struct {
+	Node    ast.Node
+	Parents []ast.Node // the root *ast.File first
+	Decl    ast.Decl   // the enclosing top-level declaration
+	Astx    *Astx
+	File    *FileSpec
+
+	Struct *StructSpec // the enclosing specs, when the file spec is known
+	Method *MethodSpec
+	Func   *FuncSpec
+}
+
+// VisitFunc is called for every node; returning false skips the node's children.
+type VisitFunc func(c *Cursor) bool
+
+// Match is a node found by a walk, attributed to its enclosing specs.
+type Match struct {
+	Node     ast.Node
+	Position Position
+	Struct   *StructSpec
+	Method   *MethodSpec
+	Func     *FuncSpec
+}
+
+func (c *Cursor) Parent() ast.Node {
+	if len(c.Parents) == 0 {
+		return nil
+	}
+
+	return c.Parents[len(c.Parents)-1]
+}
+
+func (c *Cursor) Position() Position {
+	return c.Astx.Position(c.Node)
+}
+
+func (c *Cursor) Match() *Match {
+	return &Match{
+		Node:     c.Node,
+		Position: c.Position(),
+		Struct:   c.Struct,
+		Method:   c.Method,
+		Func:     c.Func,
+	}
+}
+
+// ObjectOf resolves the identifier through the type information of the package.
+func (c *Cursor) ObjectOf(ident *ast.Ident) types.Object {
+	if info := c.Astx.TypesInfo(); info != nil {
+		return info.ObjectOf(ident)
+	}
+
+	return nil
+}
+
+func (c *Cursor) TypeOf(expr ast.Expr) types.Type {
+	if info := c.Astx.TypesInfo(); info != nil {
+		return info.TypeOf(expr)
+	}
+
+	return nil
+}
+
+// Callee is the function or method called by call, or nil for conversions and builtins.
+func (c *Cursor) Callee(call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	fun := call.Fun
+	for {
+		paren, ok := fun.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		fun = paren.X
+	}
+
+	switch fn := fun.(type) {
+	case *ast.Ident:
+		ident = fn
+	case *ast.SelectorExpr:
+		ident = fn.Sel
+	case *ast.IndexExpr:
+		return c.Callee(&ast.CallExpr{Fun: fn.X})
+	case *ast.IndexListExpr:
+		return c.Callee(&ast.CallExpr{Fun: fn.X})
+	default:
+		return nil
+	}
+
+	callee, _ := c.ObjectOf(ident).(*types.Func)
+
+	return callee
+}
+
+// Walk walks the file depth-first, tracking parents, the enclosing declaration and,
+// when fs is not nil, the enclosing struct, method or func spec.
+func Walk(aw *Astx, fs *FileSpec, fn VisitFunc) {
+	w := &walker{
+		cursor: &Cursor{
+			Astx: aw,
+			File: fs,
+		},
+		fn: fn,
+	}
+
+	ast.Inspect(aw.Ast, w.visit)
+}
+
+// FindCalls lists the calls to pkgPath.name, e.g. `context.Background` or a `Register` method.
+// A method is matched by its name and the package of its receiver type.
+func FindCalls(aw *Astx, fs *FileSpec, pkgPath, name string) []*Match {
+	matches := make([]*Match, 0)
+	Walk(aw, fs, func(c *Cursor) bool {
+		call, ok := c.Node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		callee := c.Callee(call)
+		if callee != nil && callee.Name() == name && callee.Pkg() != nil && callee.Pkg().Path() == pkgPath {
+			matches = append(matches, c.Match())
+		}
+
+		return true
+	})
+
+	return matches
+}
+
+type walker struct {
+	cursor *Cursor
+	fn     VisitFunc
+}
+
+func (w *walker) visit(node ast.Node) bool {
+	c := w.cursor
+	if node == nil {
+		// leaving the node on top of the stack
+		left := c.Parents[len(c.Parents)-1]
+		c.Parents = c.Parents[:len(c.Parents)-1]
+		w.leave(left)
+		return false
+	}
+
+	w.enter(node)
+	c.Node = node
+	if !w.fn(c) {
+		w.leave(node)
+		return false
+	}
+	c.Parents = append(c.Parents, node)
+
+	return true
+}
+
+func (w *walker) enter(node ast.Node) {
+	c := w.cursor
+	if len(c.Parents) == 1 {
+		if decl, ok := node.(ast.Decl); ok {
+			c.Decl = decl
+		}
+	}
+	if c.File == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		for _, ss := range c.File.Structs {
+			for _, ms := range ss.Methods {
+				if ms.Node == n {
+					c.Struct, c.Method = ss, ms
+				}
+			}
+		}
+		for _, fns := range c.File.Funcs {
+			if fns.Node == n {
+				c.Func = fns
+			}
+		}
+	case *ast.TypeSpec:
+		for _, ss := range c.File.Structs {
+			if ss.Node == n {
+				c.Struct = ss
+			}
+		}
+	}
+}
+
+func (w *walker) leave(node ast.Node) {
+	c := w.cursor
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		c.Struct, c.Method, c.Func = nil, nil, nil
+	case *ast.TypeSpec:
+		// a local type of a method leaves the struct of its receiver
+		if c.Struct != nil && c.Struct.Node == n {
+			c.Struct = nil
+		}
+	}
+	if decl, ok := node.(ast.Decl); ok && decl == c.Decl {
+		c.Decl = nil
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package astx_test
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+)
+
+const visitorSource = `package visitor
+
+import (
+	"context"
+)
+
+// Registry a registry of handlers
+// @Component
+type Registry struct{}
+
+func (r *Registry) Register(name string) {}
+
+// Boot registers the handlers
+func (r *Registry) Boot() {
+	r.Register("a")
+	_ = context.Background()
+}
+
+// Init registers the default handlers
+func Init(r *Registry) {
+	r.Register("b")
+	r.Register("c")
+}
+
+// Reload registers the handlers again
+func (r *Registry) Reload() {
+	type entry struct{ name string }
+	r.Register(entry{name: "d"}.name)
+}
+`
+
+func TestFindCalls(t *testing.T) {
+	lpkg, err := loader.LoadSource("visitor.go", []byte(visitorSource), nil)
+	if err != nil {
+		t.Fatalf("LoadSource() error = %v", err)
+	}
+	aw := astx.NewAstx(lpkg.CompiledGoFiles[0], lpkg)
+	fs := parser.Default().ParseFileSpec(aw)
+
+	registers := astx.FindCalls(aw, fs, "visitor", "Register")
+	if len(registers) != 4 {
+		t.Fatalf("FindCalls(Register) = %v matches, want 4", len(registers))
+	}
+	if m := registers[0]; m.Method == nil || m.Method.Name != "Boot" || m.Struct == nil || m.Struct.Name != "Registry" {
+		t.Errorf("FindCalls(Register)[0] enclosing = %+v", m)
+	}
+	if m := registers[1]; m.Func == nil || m.Func.Name != "Init" || m.Position.Line != 21 {
+		t.Errorf("FindCalls(Register)[1] enclosing = %+v", m)
+	}
+	// after a local type of the method
+	if m := registers[3]; m.Method == nil || m.Method.Name != "Reload" || m.Struct == nil || m.Struct.Name != "Registry" {
+		t.Errorf("FindCalls(Register)[3] enclosing = %+v", m)
+	}
+
+	backgrounds := astx.FindCalls(aw, fs, "context", "Background")
+	if len(backgrounds) != 1 || backgrounds[0].Method == nil || backgrounds[0].Method.Name != "Boot" {
+		t.Errorf("FindCalls(context.Background) = %+v", backgrounds)
+	}
+}
+
+func TestWalk_Parents(t *testing.T) {
+	lpkg, err := loader.LoadSource("visitor.go", []byte(visitorSource), nil)
+	if err != nil {
+		t.Fatalf("LoadSource() error = %v", err)
+	}
+	aw := astx.NewAstx(lpkg.CompiledGoFiles[0], lpkg)
+
+	astx.Walk(aw, nil, func(c *astx.Cursor) bool {
+		if lit, ok := c.Node.(*ast.BasicLit); ok && lit.Value == `"a"` {
+			if _, ok := c.Parent().(*ast.CallExpr); !ok {
+				t.Errorf("parent of %s = %T, want *ast.CallExpr", lit.Value, c.Parent())
+			}
+			if decl, ok := c.Decl.(*ast.FuncDecl); !ok || decl.Name.Name != "Boot" {
+				t.Errorf("enclosing declaration of %s = %v", lit.Value, c.Decl)
+			}
+			if _, ok := c.Parents[0].(*ast.File); !ok {
+				t.Errorf("root of %s = %T, want *ast.File", lit.Value, c.Parents[0])
+			}
+		}
+		return true
+	})
+}
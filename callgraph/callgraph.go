@@ -0,0 +1,359 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callgraph
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+)
+
+type Mode string
+
+const (
+	// ModeStatic keeps calls through an interface on the abstract interface method.
+	ModeStatic Mode = "static"
+	// ModeCHA resolves calls through an interface to every loaded type implementing it.
+	ModeCHA Mode = "cha"
+)
+
+type Node struct {
+	ID          string // the full name, e.g. `(*example.com/pkg.Service).Method`
+	Pkg         string
+	Name        string
+	Recv        string
+	Abstract    bool // an interface method
+	Position    astx.Position
+	Method      *astx.MethodSpec
+	Func        *astx.FuncSpec
+	Annotations []*astx.Annotation
+	Object      *types.Func
+}
+
+type Edge struct {
+	Caller   *Node
+	Callee   *Node
+	Position astx.Position
+	Dynamic  bool // dispatched through an interface
+}
+
+type Graph struct {
+	Mode  Mode
+	Nodes map[string]*Node
+	Edges []*Edge
+
+	decls    map[*ast.FuncDecl]*Node
+	fset     *token.FileSet
+	pkgs     []*loader.Package
+	edgeKeys map[string]bool
+}
+
+// Build extracts the calls made by the functions and methods declared in roots.
+// The specs, if any, attach their annotated methods and funcs to the nodes.
+func Build(mode Mode, roots []*loader.Package, specs ...*astx.AstSpec) *Graph {
+	g := &Graph{
+		Mode:     mode,
+		Nodes:    make(map[string]*Node),
+		Edges:    make([]*Edge, 0),
+		decls:    make(map[*ast.FuncDecl]*Node),
+		edgeKeys: make(map[string]bool),
+	}
+
+	dynamic := make([]*Edge, 0)
+	for _, root := range roots {
+		root.NeedTypesInfo()
+		if g.fset == nil {
+			g.fset = root.Fset
+		}
+		for _, cf := range root.CompiledGoFiles {
+			if root.SyntaxFor(cf) == nil {
+				continue
+			}
+			aw := astx.NewAstx(cf, root)
+			dynamic = append(dynamic, g.walk(aw)...)
+		}
+	}
+
+	if mode == ModeCHA {
+		g.pkgs = typedPackages(roots)
+		for _, edge := range dynamic {
+			for _, impl := range g.implementations(edge.Callee.Object) {
+				g.addEdge(edge.Caller, g.node(impl), edge.Position, true)
+			}
+		}
+	}
+
+	for _, as := range specs {
+		g.annotate(as)
+	}
+
+	return g
+}
+
+func (g *Graph) NodeOf(decl *ast.FuncDecl) *Node {
+	return g.decls[decl]
+}
+
+func (g *Graph) MethodNode(ms *astx.MethodSpec) *Node {
+	if decl, ok := ms.Node.(*ast.FuncDecl); ok {
+		return g.decls[decl]
+	}
+
+	return nil
+}
+
+func (g *Graph) FuncNode(fns *astx.FuncSpec) *Node {
+	if decl, ok := fns.Node.(*ast.FuncDecl); ok {
+		return g.decls[decl]
+	}
+
+	return nil
+}
+
+func (g *Graph) Callees(n *Node) []*Node {
+	nodes := make([]*Node, 0)
+	for _, edge := range g.Edges {
+		if edge.Caller == n {
+			nodes = append(nodes, edge.Callee)
+		}
+	}
+
+	return uniqueNodes(nodes)
+}
+
+func (g *Graph) Callers(n *Node) []*Node {
+	nodes := make([]*Node, 0)
+	for _, edge := range g.Edges {
+		if edge.Callee == n {
+			nodes = append(nodes, edge.Caller)
+		}
+	}
+
+	return uniqueNodes(nodes)
+}
+
+// Annotated lists the nodes carrying the annotation, or any annotation when name is empty.
+func (g *Graph) Annotated(name string) []*Node {
+	nodes := make([]*Node, 0)
+	for _, n := range g.Nodes {
+		for _, anno := range n.Annotations {
+			if name == "" || anno.Name == name {
+				nodes = append(nodes, n)
+				break
+			}
+		}
+	}
+
+	return uniqueNodes(nodes)
+}
+
+func (g *Graph) SortedNodes() []*Node {
+	nodes := make([]*Node, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes = append(nodes, n)
+	}
+
+	return uniqueNodes(nodes)
+}
+
+func (g *Graph) walk(aw *astx.Astx) []*Edge {
+	dynamic := make([]*Edge, 0)
+	info := aw.TypesInfo()
+	astx.Walk(aw, nil, func(c *astx.Cursor) bool {
+		decl, ok := c.Decl.(*ast.FuncDecl)
+		if !ok {
+			return c.Decl == nil
+		}
+		fn, ok := info.Defs[decl.Name].(*types.Func)
+		if !ok {
+			return false
+		}
+		caller := g.node(fn)
+		g.decls[decl] = caller
+
+		call, ok := c.Node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee := c.Callee(call)
+		if callee == nil {
+			return true
+		}
+
+		edge := g.addEdge(caller, g.node(callee), c.Position(), isInterface(callee))
+		if edge != nil && edge.Dynamic {
+			dynamic = append(dynamic, edge)
+		}
+
+		return true
+	})
+
+	return dynamic
+}
+
+func (g *Graph) node(fn *types.Func) *Node {
+	fn = fn.Origin()
+	id := fn.FullName()
+	if n, ok := g.Nodes[id]; ok {
+		return n
+	}
+
+	n := &Node{
+		ID:       id,
+		Name:     fn.Name(),
+		Abstract: isInterface(fn),
+		Position: astx.NewPosition(g.fset, fn.Pos(), token.NoPos),
+		Object:   fn,
+	}
+	if fn.Pkg() != nil {
+		n.Pkg = fn.Pkg().Path()
+	}
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		n.Recv = types.TypeString(recv.Type(), func(pkg *types.Package) string {
+			return pkg.Name()
+		})
+	}
+	g.Nodes[id] = n
+
+	return n
+}
+
+func (g *Graph) addEdge(caller, callee *Node, position astx.Position, dynamic bool) *Edge {
+	key := caller.ID + "\x00" + callee.ID + "\x00" + position.String()
+	if g.edgeKeys[key] {
+		return nil
+	}
+	g.edgeKeys[key] = true
+
+	edge := &Edge{
+		Caller:   caller,
+		Callee:   callee,
+		Position: position,
+		Dynamic:  dynamic,
+	}
+	g.Edges = append(g.Edges, edge)
+
+	return edge
+}
+
+// implementations lists the concrete methods an interface method may dispatch to.
+func (g *Graph) implementations(method *types.Func) []*types.Func {
+	recv := method.Type().(*types.Signature).Recv()
+	iface, ok := recv.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	impls := make([]*types.Func, 0)
+	for _, pkg := range g.pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || types.IsInterface(named) || named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			var typ types.Type = named
+			if !types.Implements(typ, iface) {
+				typ = types.NewPointer(named)
+				if !types.Implements(typ, iface) {
+					continue
+				}
+			}
+			obj, _, _ := types.LookupFieldOrMethod(typ, false, method.Pkg(), method.Name())
+			if impl, ok := obj.(*types.Func); ok {
+				impls = append(impls, impl)
+			}
+		}
+	}
+
+	return impls
+}
+
+func (g *Graph) annotate(as *astx.AstSpec) {
+	if as == nil || as.Package == nil {
+		return
+	}
+	for _, ss := range as.Package.Structs {
+		for _, ms := range ss.Methods {
+			if n := g.MethodNode(ms); n != nil {
+				n.Method = ms
+				n.Annotations = ms.Annotations
+			}
+		}
+	}
+	for _, fns := range as.Package.Funcs {
+		if n := g.FuncNode(fns); n != nil {
+			n.Func = fns
+			n.Annotations = fns.Annotations
+		}
+	}
+}
+
+func typedPackages(roots []*loader.Package) []*loader.Package {
+	seen := make(map[*loader.Package]bool)
+	pkgs := make([]*loader.Package, 0)
+	var visit func(pkg *loader.Package)
+	visit = func(pkg *loader.Package) {
+		if seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		if pkg.Types == nil {
+			return
+		}
+		pkgs = append(pkgs, pkg)
+		for _, imported := range pkg.Imports() {
+			visit(imported)
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+
+	return pkgs
+}
+
+func isInterface(fn *types.Func) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+
+	return recv != nil && types.IsInterface(recv.Type())
+}
+
+func uniqueNodes(nodes []*Node) []*Node {
+	seen := make(map[*Node]bool, len(nodes))
+	out := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
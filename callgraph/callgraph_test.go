@@ -0,0 +1,162 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+)
+
+func TestBuild(t *testing.T) {
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/tests/callx")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+	specs := make([]*astx.AstSpec, 0, len(roots))
+	for _, root := range roots {
+		specs = append(specs, parser.Parse(root))
+	}
+
+	tests := []struct {
+		mode Mode
+		want []string
+	}{
+		{
+			mode: ModeStatic,
+			want: []string{"Greet", "normalize"},
+		},
+		{
+			mode: ModeCHA,
+			want: []string{"Greet", "Greet", "Greet", "normalize"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			g := Build(tt.mode, roots, specs...)
+
+			audited := g.Annotated("Audit")
+			if len(audited) != 1 || audited[0].Name != "Welcome" || audited[0].Method == nil {
+				t.Fatalf("Annotated(Audit) = %v", audited)
+			}
+			if n := g.MethodNode(audited[0].Method); n != audited[0] {
+				t.Errorf("MethodNode() = %v, want %v", n, audited[0])
+			}
+
+			names := make([]string, 0)
+			for _, callee := range g.Callees(audited[0]) {
+				names = append(names, callee.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Callees(Welcome) = %v, want %v", names, tt.want)
+			}
+
+			normalize := g.Nodes["github.com/photowey/parsergo/tests/callx.normalize"]
+			if normalize == nil || len(g.Callers(normalize)) != 1 {
+				t.Errorf("Callers(normalize) = %v", normalize)
+			}
+		})
+	}
+}
+
+func TestGraph_Export(t *testing.T) {
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/tests/callx")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+	g := Build(ModeCHA, roots)
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	if !strings.HasPrefix(dot.String(), "digraph callgraph {") || !strings.Contains(dot.String(), "[style=dashed]") {
+		t.Errorf("WriteDOT() = %s", dot.String())
+	}
+
+	var out bytes.Buffer
+	if err := g.WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	decoded := &jsonGraph{}
+	if err := json.Unmarshal(out.Bytes(), decoded); err != nil {
+		t.Fatalf("WriteJSON() invalid json: %v", err)
+	}
+	if decoded.Mode != ModeCHA || len(decoded.Edges) != len(g.Edges) {
+		t.Errorf("WriteJSON() = %+v", decoded)
+	}
+}
+
+func TestBuild_AcrossPackages(t *testing.T) {
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/tests/callx/...")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+	specs := make([]*astx.AstSpec, 0, len(roots))
+	for _, root := range roots {
+		specs = append(specs, parser.Parse(root))
+	}
+
+	tests := []struct {
+		mode Mode
+		want []string
+	}{
+		{
+			mode: ModeStatic,
+			want: []string{
+				"(*github.com/photowey/parsergo/tests/callx.GreetingService).Welcome",
+				"(github.com/photowey/parsergo/tests/callx.Greeter).Greet",
+			},
+		},
+		{
+			mode: ModeCHA,
+			want: []string{
+				"(*github.com/photowey/parsergo/tests/callx.GreetingService).Welcome",
+				"(*github.com/photowey/parsergo/tests/callx.LoudGreeter).Greet",
+				"(github.com/photowey/parsergo/tests/callx.EnglishGreeter).Greet",
+				"(github.com/photowey/parsergo/tests/callx.Greeter).Greet",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			g := Build(tt.mode, roots, specs...)
+
+			handlers := g.Annotated("Get")
+			if len(handlers) != 1 || handlers[0].Pkg != "github.com/photowey/parsergo/tests/callx/apix" {
+				t.Fatalf("Annotated(Get) = %v", handlers)
+			}
+			ids := make([]string, 0)
+			for _, callee := range g.Callees(handlers[0]) {
+				ids = append(ids, callee.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Callees(Hello) = %v, want %v", ids, tt.want)
+			}
+
+			welcome := g.Nodes["(*github.com/photowey/parsergo/tests/callx.GreetingService).Welcome"]
+			if welcome == nil || len(g.Callers(welcome)) != 1 || g.Callers(welcome)[0] != handlers[0] {
+				t.Errorf("Callers(Welcome) = %v, want [Hello]", welcome)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type jsonGraph struct {
+	Mode  Mode        `json:"mode"`
+	Nodes []*jsonNode `json:"nodes"`
+	Edges []*jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	ID          string   `json:"id"`
+	Pkg         string   `json:"pkg"`
+	Name        string   `json:"name"`
+	Recv        string   `json:"recv,omitempty"`
+	Abstract    bool     `json:"abstract,omitempty"`
+	Position    string   `json:"position"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+type jsonEdge struct {
+	Caller   string `json:"caller"`
+	Callee   string `json:"callee"`
+	Position string `json:"position"`
+	Dynamic  bool   `json:"dynamic,omitempty"`
+}
+
+func (g *Graph) WriteJSON(w io.Writer) error {
+	out := &jsonGraph{
+		Mode:  g.Mode,
+		Nodes: make([]*jsonNode, 0, len(g.Nodes)),
+		Edges: make([]*jsonEdge, 0, len(g.Edges)),
+	}
+	for _, n := range g.SortedNodes() {
+		jn := &jsonNode{
+			ID:       n.ID,
+			Pkg:      n.Pkg,
+			Name:     n.Name,
+			Recv:     n.Recv,
+			Abstract: n.Abstract,
+			Position: n.Position.String(),
+		}
+		for _, anno := range n.Annotations {
+			jn.Annotations = append(jn.Annotations, anno.Name)
+		}
+		out.Nodes = append(out.Nodes, jn)
+	}
+	for _, edge := range g.sortedEdges() {
+		out.Edges = append(out.Edges, &jsonEdge{
+			Caller:   edge.Caller.ID,
+			Callee:   edge.Callee.ID,
+			Position: edge.Position.String(),
+			Dynamic:  edge.Dynamic,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(out)
+}
+
+// WriteDOT renders the graph in Graphviz DOT, dynamic calls dashed and annotated nodes boxed.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph callgraph {\n")
+	b.WriteString("\tnode [shape=ellipse];\n")
+	for _, n := range g.SortedNodes() {
+		attrs := []string{fmt.Sprintf("label=%q", n.label())}
+		if len(n.Annotations) > 0 {
+			attrs = append(attrs, "shape=box")
+		}
+		if n.Abstract {
+			attrs = append(attrs, "style=dotted")
+		}
+		b.WriteString(fmt.Sprintf("\t%q [%s];\n", n.ID, strings.Join(attrs, ", ")))
+	}
+
+	seen := make(map[string]bool)
+	for _, edge := range g.sortedEdges() {
+		line := fmt.Sprintf("\t%q -> %q", edge.Caller.ID, edge.Callee.ID)
+		if edge.Dynamic {
+			line += " [style=dashed]"
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		b.WriteString(line + ";\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func (n *Node) label() string {
+	label := n.Name
+	if n.Recv != "" {
+		label = "(" + n.Recv + ")." + n.Name
+	}
+	for _, anno := range n.Annotations {
+		label += "\n@" + anno.Name
+	}
+
+	return label
+}
+
+func (g *Graph) sortedEdges() []*Edge {
+	edges := append(make([]*Edge, 0, len(g.Edges)), g.Edges...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].Caller.ID != edges[j].Caller.ID {
+			return edges[i].Caller.ID < edges[j].Caller.ID
+		}
+		if edges[i].Callee.ID != edges[j].Callee.ID {
+			return edges[i].Callee.ID < edges[j].Callee.ID
+		}
+		return edges[i].Position.Offset < edges[j].Position.Offset
+	})
+
+	return edges
+}
@@ -0,0 +1,30 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command parsergo-vet runs the parsergo analyzers, standalone or as `go vet -vettool=$(which parsergo-vet)`.
+package main
+
+import (
+	"github.com/photowey/parsergo/analysis"
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	// the annotations of the generators
+	_ "github.com/photowey/parsergo/gen/builtin"
+)
+
+func main() {
+	multichecker.Main(analysis.Analyzers...)
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/photowey/parsergo/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzers_Fixtures runs the analyzers over the generator fixtures, free of diagnostics once the annotations
+// of every generator are registered, as in the vet tool.
+func TestAnalyzers_Fixtures(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := []string{
+		"./tests/routex",
+		"./tests/builderx",
+		"./tests/checkx",
+		"./tests/objectx",
+	}
+	for _, analyzer := range analysis.Analyzers {
+		t.Run(analyzer.Name, func(t *testing.T) {
+			analysistest.Run(t, root, analyzer, fixtures...)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/photowey/parsergo/arch"
+)
+
+func init() {
+	register(&command{
+		name:  "arch",
+		usage: "check the package imports against architecture rules",
+		run:   runArch,
+	})
+}
+
+func runArch(args []string, stdout io.Writer) error {
+	var (
+		lf     loadFlags
+		config string
+	)
+	fs := flag.NewFlagSet("arch", flag.ContinueOnError)
+	lf.bind(fs)
+	fs.StringVar(&config, "config", "arch.yaml", "the YAML rules file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	conf, err := arch.LoadConfig(config)
+	if err != nil {
+		return err
+	}
+	roots, err := lf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	violations, err := arch.Check(roots, conf.Rules...)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range violations {
+		fmt.Fprintln(stdout, v)
+	}
+	if len(violations) > 0 {
+		return &exitError{code: 1, msg: fmt.Sprintf("%d violation(s)", len(violations))}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/photowey/parsergo/depgraph"
+)
+
+func init() {
+	register(&command{
+		name:  "deps",
+		usage: "print the package dependency graph",
+		run:   runDeps,
+	})
+}
+
+func runDeps(args []string, stdout io.Writer) error {
+	var (
+		lf      loadFlags
+		format  string
+		local   bool
+		reverse string
+		cycles  bool
+	)
+	fs := flag.NewFlagSet("deps", flag.ContinueOnError)
+	lf.bind(fs)
+	fs.StringVar(&format, "format", "text", "output format: text, topo, dot or mermaid")
+	fs.BoolVar(&local, "local", false, "keep only the packages of the main module")
+	fs.StringVar(&reverse, "reverse", "", "print the packages depending on this package instead")
+	fs.BoolVar(&cycles, "cycles", false, "print the import cycles and fail if there is any")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	roots, err := lf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	g := depgraph.Build(roots)
+	if local {
+		g = g.Local()
+	}
+
+	switch {
+	case cycles:
+		found := g.Cycles()
+		for _, cycle := range found {
+			fmt.Fprintln(stdout, strings.Join(cycle, " <-> "))
+		}
+		if len(found) > 0 {
+			return &exitError{code: 1, msg: fmt.Sprintf("%d import cycle(s)", len(found))}
+		}
+		return nil
+	case reverse != "":
+		for _, path := range g.ReverseDeps(reverse) {
+			fmt.Fprintln(stdout, path)
+		}
+		return nil
+	}
+
+	switch format {
+	case "text":
+		for _, path := range g.Paths() {
+			fmt.Fprintln(stdout, path)
+			for _, dep := range g.Imports(path) {
+				fmt.Fprintf(stdout, "\t%s\n", dep)
+			}
+		}
+		return nil
+	case "topo":
+		order, err := g.TopoOrder()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, strings.Join(order, "\n"))
+		return nil
+	case "dot":
+		return g.WriteDOT(stdout)
+	case "mermaid":
+		return g.WriteMermaid(stdout)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/gen/builtin"
+)
+
+func init() {
+	register(&command{
+		name:  "gen",
+		usage: "run code generators over the packages",
+		run:   runGen,
+	})
+}
+
+func runGen(args []string, stdout io.Writer) error {
+	var (
+		lf     loadFlags
+		names  string
+		dryRun bool
+	)
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	lf.bind(fs)
+	fs.StringVar(&names, "g", "", fmt.Sprintf("comma-separated generators: %s", strings.Join(builtin.Names(), ", ")))
+	fs.BoolVar(&dryRun, "dry-run", false, "print the paths of the generated files without writing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	selected := make([]gen.Generator, 0)
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		newGenerator, ok := builtin.Generators[name]
+		if !ok {
+			return fmt.Errorf("unknown generator %q", name)
+		}
+		selected = append(selected, newGenerator())
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no generator, use -g %s", strings.Join(builtin.Names(), ","))
+	}
+
+	roots, err := lf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	result, err := gen.Run(roots, selected...)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range result.Paths() {
+		fmt.Fprintln(stdout, path)
+	}
+	if dryRun {
+		return nil
+	}
+
+	return result.Write()
+}
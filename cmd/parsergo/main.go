@@ -0,0 +1,112 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/photowey/parsergo/loader"
+	"golang.org/x/tools/go/packages"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout io.Writer) error
+}
+
+var commands = map[string]*command{}
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+// exitError carries the exit code of a command that ran but failed its check, e.g. a cycle was found.
+type exitError struct {
+	code int
+	msg  string
+}
+
+func (e *exitError) Error() string {
+	return e.msg
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || commands[args[0]] == nil {
+		usage(stderr)
+		return 2
+	}
+
+	if err := commands[args[0]].run(args[1:], stdout); err != nil {
+		if err == flag.ErrHelp {
+			return 2
+		}
+		fmt.Fprintf(stderr, "parsergo %s: %v\n", args[0], err)
+		if exitErr, ok := err.(*exitError); ok {
+			return exitErr.code
+		}
+		return 1
+	}
+
+	return 0
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: parsergo <command> [flags] [packages]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].usage)
+	}
+}
+
+// loadFlags are the flags shared by every command loading packages.
+type loadFlags struct {
+	dir  string
+	tags string
+}
+
+func (lf *loadFlags) bind(fs *flag.FlagSet) {
+	fs.StringVar(&lf.dir, "dir", "", "directory to load the packages from")
+	fs.StringVar(&lf.tags, "tags", "", "comma-separated build tags")
+}
+
+func (lf *loadFlags) load(patterns []string) ([]*loader.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	conf := &packages.Config{Dir: lf.dir}
+	if lf.tags != "" {
+		conf.BuildFlags = []string{"-tags=" + strings.Join([]string{loader.IgnoreAutogeneratedTag, lf.tags}, ",")}
+	}
+
+	return loader.LoadRootsWithConfig(conf, patterns...)
+}
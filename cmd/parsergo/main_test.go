@@ -0,0 +1,66 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "no command",
+			args:     []string{},
+			wantCode: 2,
+		},
+		{
+			name:     "deps topo",
+			args:     []string{"deps", "-dir", "../..", "-local", "-format", "topo", "./depgraph"},
+			wantCode: 0,
+			wantOut: "github.com/photowey/parsergo/sets\n" +
+				"github.com/photowey/parsergo/loader\n" +
+				"github.com/photowey/parsergo/depgraph\n",
+		},
+		{
+			name:     "arch violations",
+			args:     []string{"arch", "-dir", "../..", "-config", "../../tests/archx/arch.yaml", "./tests/archx/..."},
+			wantCode: 1,
+		},
+		{
+			name:     "deps unknown format",
+			args:     []string{"deps", "-dir", "../..", "-format", "svg", "./sets"},
+			wantCode: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d, stderr = %s", code, tt.wantCode, stderr.String())
+			}
+			if tt.wantOut != "" && stdout.String() != tt.wantOut {
+				t.Errorf("run() stdout = %q, want %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/photowey/parsergo/loader"
+)
+
+type Node struct {
+	Path    string
+	Name    string
+	Module  string // empty for the standard library
+	Root    bool
+	Package *loader.Package
+}
+
+type Graph struct {
+	Module string // the module of the roots
+	Nodes  map[string]*Node
+
+	imports    map[string]map[string]bool
+	dependents map[string]map[string]bool
+}
+
+func New() *Graph {
+	return &Graph{
+		Nodes:      make(map[string]*Node),
+		imports:    make(map[string]map[string]bool),
+		dependents: make(map[string]map[string]bool),
+	}
+}
+
+// Build walks the imports of roots transitively, keyed by package path.
+func Build(roots []*loader.Package) *Graph {
+	g := New()
+	visited := make(map[*loader.Package]bool)
+
+	var visit func(pkg *loader.Package)
+	visit = func(pkg *loader.Package) {
+		if visited[pkg] {
+			return
+		}
+		visited[pkg] = true
+		g.addPackage(pkg)
+		for _, dep := range pkg.Imports() {
+			g.AddEdge(pkg.PkgPath, dep.PkgPath)
+			g.addPackage(dep)
+			visit(dep)
+		}
+	}
+
+	for _, root := range roots {
+		if root.IsTestMain() {
+			continue
+		}
+		visit(root)
+		g.Nodes[root.PkgPath].Root = true
+		if g.Module == "" && root.Module != nil {
+			g.Module = root.Module.Path
+		}
+	}
+
+	return g
+}
+
+func (g *Graph) addPackage(pkg *loader.Package) {
+	node := g.node(pkg.PkgPath)
+	if node.Package != nil {
+		return
+	}
+	node.Name = pkg.Name
+	node.Package = pkg
+	if pkg.Module != nil {
+		node.Module = pkg.Module.Path
+	}
+}
+
+func (g *Graph) node(path string) *Node {
+	if g.Nodes[path] == nil {
+		g.Nodes[path] = &Node{Path: path}
+	}
+
+	return g.Nodes[path]
+}
+
+// AddEdge records that from imports to.
+func (g *Graph) AddEdge(from, to string) {
+	g.node(from)
+	g.node(to)
+	if g.imports[from] == nil {
+		g.imports[from] = make(map[string]bool)
+	}
+	if g.dependents[to] == nil {
+		g.dependents[to] = make(map[string]bool)
+	}
+	g.imports[from][to] = true
+	g.dependents[to][from] = true
+}
+
+func (g *Graph) HasEdge(from, to string) bool {
+	return g.imports[from][to]
+}
+
+// Imports returns the packages imported by path, sorted.
+func (g *Graph) Imports(path string) []string {
+	return sortedKeys(g.imports[path])
+}
+
+// Dependents returns the packages importing path directly, sorted.
+func (g *Graph) Dependents(path string) []string {
+	return sortedKeys(g.dependents[path])
+}
+
+// ReverseDeps returns every package depending on path, directly or transitively, sorted.
+func (g *Graph) ReverseDeps(path string) []string {
+	seen := make(map[string]bool)
+	queue := []string{path}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for dependent := range g.dependents[current] {
+			if !seen[dependent] && dependent != path {
+				seen[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	return sortedKeys(seen)
+}
+
+// IsLocal reports whether path belongs to the module of the roots.
+func (g *Graph) IsLocal(path string) bool {
+	node := g.Nodes[path]
+	return node != nil && g.Module != "" && node.Module == g.Module
+}
+
+// Filter returns the subgraph of the nodes accepted by keep.
+func (g *Graph) Filter(keep func(node *Node) bool) *Graph {
+	out := New()
+	out.Module = g.Module
+	for path, node := range g.Nodes {
+		if keep(node) {
+			copied := *node
+			out.Nodes[path] = &copied
+		}
+	}
+	for from, tos := range g.imports {
+		if out.Nodes[from] == nil {
+			continue
+		}
+		for to := range tos {
+			if out.Nodes[to] != nil {
+				out.AddEdge(from, to)
+			}
+		}
+	}
+
+	return out
+}
+
+// Local returns the subgraph of the module-local packages.
+func (g *Graph) Local() *Graph {
+	return g.Filter(func(node *Node) bool {
+		return g.IsLocal(node.Path)
+	})
+}
+
+func (g *Graph) Paths() []string {
+	paths := make([]string, 0, len(g.Nodes))
+	for path := range g.Nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// TopoOrder returns the packages with every dependency before its dependents.
+// Ties are broken by path so the order is deterministic.
+func (g *Graph) TopoOrder() ([]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, &CycleError{Cycles: cycles}
+	}
+
+	pending := make(map[string]int, len(g.Nodes))
+	ready := make([]string, 0)
+	for _, path := range g.Paths() {
+		pending[path] = len(g.imports[path])
+		if pending[path] == 0 {
+			ready = append(ready, path)
+		}
+	}
+
+	order := make([]string, 0, len(g.Nodes))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+		for dependent := range g.dependents[current] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	return order, nil
+}
+
+// Cycles returns the import cycles as strongly connected components, each sorted by path.
+func (g *Graph) Cycles() [][]string {
+	var (
+		index   = 0
+		indices = make(map[string]int)
+		lowlink = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   = make([]string, 0)
+		cycles  = make([][]string, 0)
+	)
+
+	var connect func(path string)
+	connect = func(path string) {
+		indices[path] = index
+		lowlink[path] = index
+		index++
+		stack = append(stack, path)
+		onStack[path] = true
+
+		for _, dep := range g.Imports(path) {
+			if _, ok := indices[dep]; !ok {
+				connect(dep)
+				lowlink[path] = min(lowlink[path], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[path] = min(lowlink[path], indices[dep])
+			}
+		}
+
+		if lowlink[path] != indices[path] {
+			return
+		}
+		component := make([]string, 0)
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == path {
+				break
+			}
+		}
+		if len(component) > 1 || g.HasEdge(path, path) {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, path := range g.Paths() {
+		if _, ok := indices[path]; !ok {
+			connect(path)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+
+	return cycles
+}
+
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, 0, len(e.Cycles))
+	for _, cycle := range e.Cycles {
+		parts = append(parts, "["+strings.Join(cycle, ", ")+"]")
+	}
+
+	return fmt.Sprintf("depgraph: import cycles: %s", strings.Join(parts, " "))
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
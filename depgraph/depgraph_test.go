@@ -0,0 +1,136 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package depgraph
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/photowey/parsergo/loader"
+)
+
+func TestBuild(t *testing.T) {
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/callgraph")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+	g := Build(roots).Local()
+
+	if g.Module != "github.com/photowey/parsergo" {
+		t.Fatalf("Module = %q", g.Module)
+	}
+	if g.Nodes["strings"] != nil || g.Nodes["golang.org/x/tools/go/packages"] != nil {
+		t.Errorf("Local() kept non-local packages: %v", g.Paths())
+	}
+
+	order, err := g.TopoOrder()
+	if err != nil {
+		t.Fatalf("TopoOrder() error = %v", err)
+	}
+	position := make(map[string]int)
+	for i, path := range order {
+		position[path] = i
+	}
+	for _, from := range order {
+		for _, to := range g.Imports(from) {
+			if position[to] > position[from] {
+				t.Errorf("TopoOrder() has %s before its dependency %s", from, to)
+			}
+		}
+	}
+	if order[len(order)-1] != "github.com/photowey/parsergo/callgraph" {
+		t.Errorf("TopoOrder() last = %s", order[len(order)-1])
+	}
+
+	reverse := strings.Join(g.ReverseDeps("github.com/photowey/parsergo/loader"), ",")
+	for _, want := range []string{"github.com/photowey/parsergo/astx", "github.com/photowey/parsergo/callgraph"} {
+		if !strings.Contains(reverse, want) {
+			t.Errorf("ReverseDeps(loader) = %v, want %s", reverse, want)
+		}
+	}
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges [][2]string
+		want  [][]string
+	}{
+		{
+			name:  "acyclic",
+			edges: [][2]string{{"api", "service"}, {"service", "repo"}},
+			want:  [][]string{},
+		},
+		{
+			name:  "cycle",
+			edges: [][2]string{{"api", "service"}, {"service", "repo"}, {"repo", "service"}},
+			want:  [][]string{{"repo", "service"}},
+		},
+		{
+			name:  "self",
+			edges: [][2]string{{"api", "api"}},
+			want:  [][]string{{"api"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New()
+			for _, edge := range tt.edges {
+				g.AddEdge(edge[0], edge[1])
+			}
+			if got := g.Cycles(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycles() = %v, want %v", got, tt.want)
+			}
+			_, err := g.TopoOrder()
+			var cycleErr *CycleError
+			if (len(tt.want) > 0) != errors.As(err, &cycleErr) {
+				t.Errorf("TopoOrder() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGraph_Export(t *testing.T) {
+	g := New()
+	g.AddEdge("example.com/api", "example.com/service")
+	g.AddEdge("example.com/service", "example.com/repo")
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	if !strings.Contains(dot.String(), `"example.com/api" -> "example.com/service";`) {
+		t.Errorf("WriteDOT() = %s", dot.String())
+	}
+
+	var mermaid bytes.Buffer
+	if err := g.WriteMermaid(&mermaid); err != nil {
+		t.Fatalf("WriteMermaid() error = %v", err)
+	}
+	want := "graph LR\n" +
+		"    p0[\"example.com/api\"]\n" +
+		"    p1[\"example.com/repo\"]\n" +
+		"    p2[\"example.com/service\"]\n" +
+		"    p0 --> p2\n" +
+		"    p2 --> p1\n"
+	if mermaid.String() != want {
+		t.Errorf("WriteMermaid() = %s, want %s", mermaid.String(), want)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph packages {\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, path := range g.Paths() {
+		attrs := fmt.Sprintf("label=%q", path)
+		if g.Nodes[path].Root {
+			attrs += ", style=bold"
+		}
+		b.WriteString(fmt.Sprintf("\t%q [%s];\n", path, attrs))
+	}
+	for _, from := range g.Paths() {
+		for _, to := range g.Imports(from) {
+			b.WriteString(fmt.Sprintf("\t%q -> %q;\n", from, to))
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart; node ids are positional since Mermaid ids cannot hold `/` or `.`.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	paths := g.Paths()
+	ids := make(map[string]string, len(paths))
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, path := range paths {
+		ids[path] = fmt.Sprintf("p%d", i)
+		b.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", ids[path], strings.ReplaceAll(path, `"`, `#quot;`)))
+	}
+	for _, from := range paths {
+		for _, to := range g.Imports(from) {
+			b.WriteString(fmt.Sprintf("    %s --> %s\n", ids[from], ids[to]))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
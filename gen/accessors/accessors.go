@@ -0,0 +1,224 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accessors
+
+import (
+	"go/token"
+	"go/types"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const (
+	GetterAnnotation = "Getter"
+	SetterAnnotation = "Setter"
+)
+
+func init() {
+	parser.RegisterMarker(
+		&parser.MarkerDefinition{
+			Name:    GetterAnnotation,
+			Targets: []parser.Target{parser.TargetStruct, parser.TargetField},
+			Help:    "generates the getters of the fields of the struct, or of the field: `Name()` for `name`, `GetName()` for `Name`",
+		},
+		&parser.MarkerDefinition{
+			Name:    SetterAnnotation,
+			Targets: []parser.Target{parser.TargetStruct, parser.TargetField},
+			Help:    "generates the `SetName(name)` setters of the fields of the struct, or of the field",
+		},
+	)
+}
+
+// Generator emits the getters and setters of the @Getter and @Setter structs and fields.
+// An unexported field `name` gets `Name()`, an exported one `GetName()`, as in protobuf. The locks are left out.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "accessors"
+}
+
+type accessor struct {
+	Field  *types.Var
+	Getter bool
+	Setter bool
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	file := ctx.NewFile()
+	w := &writer{file: file}
+	for _, ss := range ctx.Spec.Package.Structs {
+		getters := annotation(ss.Annotations, GetterAnnotation) != nil
+		setters := annotation(ss.Annotations, SetterAnnotation) != nil
+		accessors := make(map[string]*accessor)
+		for _, fs := range ss.Fields {
+			getter := getters || annotation(fs.Annotations, GetterAnnotation) != nil
+			setter := setters || annotation(fs.Annotations, SetterAnnotation) != nil
+			if (getter || setter) && !fs.Embedded && fs.Name != "_" {
+				accessors[fs.Name] = &accessor{Getter: getter, Setter: setter}
+			}
+		}
+		if len(accessors) == 0 {
+			continue
+		}
+
+		ctx.Package.NeedTypesInfo()
+		obj, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+		if !ok {
+			ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			ctx.Errorf(ss.Position, "%s: a generic struct can't get accessors", ss.Name)
+			continue
+		}
+		st := named.Underlying().(*types.Struct)
+
+		ordered := make([]*accessor, 0, len(accessors))
+		declared := make(map[string]bool)
+		failed := false
+		for i := 0; i < st.NumFields(); i++ {
+			a := accessors[st.Field(i).Name()]
+			if a == nil {
+				continue
+			}
+			a.Field = st.Field(i)
+			if gen.Locked(a.Field.Type()) {
+				continue
+			}
+			for _, method := range a.methods() {
+				if existing, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), method); existing != nil || declared[method] {
+					ctx.Errorf(fieldPosition(ss, a.Field.Name()), "%s.%s: the accessor %s is already declared", ss.Name, a.Field.Name(), method)
+					failed = true
+				}
+				declared[method] = true
+			}
+			ordered = append(ordered, a)
+		}
+		if !failed {
+			w.accessors(ss.Name, ordered)
+		}
+	}
+	if w.structs == 0 {
+		return nil
+	}
+
+	return ctx.AddFile(file)
+}
+
+func (a *accessor) getter() string {
+	if a.Field.Exported() {
+		return "Get" + a.Field.Name()
+	}
+
+	return exported(a.Field.Name())
+}
+
+func (a *accessor) setter() string {
+	return "Set" + exported(a.Field.Name())
+}
+
+func (a *accessor) methods() []string {
+	methods := make([]string, 0, 2)
+	if a.Getter {
+		methods = append(methods, a.getter())
+	}
+	if a.Setter {
+		methods = append(methods, a.setter())
+	}
+
+	return methods
+}
+
+type writer struct {
+	file    *gen.File
+	structs int
+}
+
+func (w *writer) accessors(name string, accessors []*accessor) {
+	w.structs++
+	recv := unexported(name[:1])
+	for _, a := range accessors {
+		typ := types.TypeString(a.Field.Type(), func(pkg *types.Package) string {
+			return w.file.Imports.NeedImport(pkg.Path())
+		})
+		field := a.Field.Name()
+		if a.Getter {
+			w.file.Printf("func (%s *%s) %s() %s {\nreturn %s.%s\n}\n\n", recv, name, a.getter(), typ, recv, field)
+		}
+		if a.Setter {
+			param := lowerInitial(field)
+			if param == recv || token.IsKeyword(param) {
+				param = "value"
+			}
+			w.file.Printf("func (%s *%s) %s(%s %s) {\n%s.%s = %s\n}\n\n", recv, name, a.setter(), param, typ, recv, field, param)
+		}
+	}
+}
+
+func fieldPosition(ss *astx.StructSpec, name string) astx.Position {
+	for _, fs := range ss.Fields {
+		if fs.Name == name {
+			return fs.Position
+		}
+	}
+
+	return ss.Position
+}
+
+func annotation(annotations []*astx.Annotation, name string) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == name && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func exported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// lowerInitial lowers the leading initialism of name: `TLS` to `tls` and `URLPath` to `urlPath`.
+func lowerInitial(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
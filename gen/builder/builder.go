@@ -0,0 +1,263 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"errors"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+	"github.com/photowey/parsergo/pkg/validatex"
+)
+
+const (
+	BuilderAnnotation = "Builder"
+	OptionsAnnotation = "Options"
+)
+
+func init() {
+	parser.RegisterMarker(
+		&parser.MarkerDefinition{
+			Name:    BuilderAnnotation,
+			Targets: []parser.Target{parser.TargetStruct},
+			Help:    "generates a fluent `<Name>Builder` of the struct",
+		},
+		&parser.MarkerDefinition{
+			Name:    OptionsAnnotation,
+			Targets: []parser.Target{parser.TargetStruct},
+			Help:    "generates `With<Field>` option funcs and a `New<Name>(opts...)` constructor: `@Options(prefix=\"WithServer\")`",
+		},
+	)
+}
+
+// Generator emits a fluent builder per @Builder struct and functional options per @Options struct.
+// The fields take their `default` tag, `validate:"required"` fields are checked on construction
+// and a `builder:"-"` tag leaves a field out. The generated names are exported with the struct.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "builder"
+}
+
+type field struct {
+	Name     string
+	Param    string
+	Var      *types.Var
+	Default  string // the Go expression of the `default` tag, if any
+	Required bool
+}
+
+type target struct {
+	Spec     *astx.StructSpec
+	Exported bool
+	Fields   []*field
+}
+
+// ident joins the prefix and name, exported or unexported with the struct: `NewServer` or `newRetryPolicy`.
+func (t *target) ident(prefix, name string) string {
+	if prefix != "" {
+		name = exported(name)
+	}
+	if t.Exported {
+		return exported(prefix + name)
+	}
+
+	return unexported(prefix + name)
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	file := ctx.NewFile()
+	w := &writer{file: file, declared: make(map[string]bool)}
+	for _, ss := range ctx.Spec.Package.Structs {
+		builder := annotation(ss.Annotations, BuilderAnnotation)
+		options := annotation(ss.Annotations, OptionsAnnotation)
+		if builder == nil && options == nil {
+			continue
+		}
+
+		ctx.Package.NeedTypesInfo()
+		t := collect(ctx, ss)
+		if t == nil {
+			continue
+		}
+		if builder != nil && w.declare(ctx, ss, t.ident("", ss.Name+"Builder"), t.ident("New", ss.Name+"Builder")) {
+			w.builder(ctx, t)
+		}
+		if options != nil {
+			prefix := "With"
+			if arg := options.Arg("prefix"); arg != nil {
+				prefix = arg.Value
+			}
+			names := []string{t.ident("", ss.Name+"Option"), t.ident("New", ss.Name)}
+			for _, f := range t.Fields {
+				names = append(names, t.ident(prefix, f.Name))
+			}
+			if w.declare(ctx, ss, names...) {
+				w.options(t, prefix)
+			}
+		}
+	}
+	if len(w.declared) == 0 {
+		return nil
+	}
+
+	return ctx.AddFile(file)
+}
+
+// collect resolves the fields of the struct, reporting the invalid tags.
+func collect(ctx *gen.Context, ss *astx.StructSpec) *target {
+	obj, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+	if !ok {
+		ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+		return nil
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		ctx.Errorf(ss.Position, "%s: a generic struct can't be built", ss.Name)
+		return nil
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		ctx.Errorf(ss.Position, "%s: @%s needs a struct", ss.Name, BuilderAnnotation)
+		return nil
+	}
+
+	t := &target{Spec: ss, Exported: token.IsExported(ss.Name)}
+	failed := false
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		if v.Embedded() || v.Name() == "_" || tag.Get("builder") == "-" {
+			continue
+		}
+		position := ss.Position
+		if fs := fieldSpec(ss, v.Name()); fs != nil {
+			position = fs.Position
+		}
+
+		f := &field{Name: v.Name(), Param: param(v.Name()), Var: v}
+		if value, ok := tag.Lookup("default"); ok {
+			literal, err := defaultOf(v.Type(), value)
+			if numErr := (*strconv.NumError)(nil); errors.As(err, &numErr) {
+				err = numErr.Err
+			}
+			if err != nil {
+				ctx.Errorf(position, "%s.%s: invalid default %q: %v", ss.Name, v.Name(), value, err)
+				failed = true
+				continue
+			}
+			f.Default = literal
+		}
+		if outer, _ := validatex.Dive(validatex.Parse(tag.Get("validate"))); validatex.Find(outer, "required") != nil {
+			if !comparable(v.Type()) {
+				ctx.Errorf(position, "%s.%s: a required %s can't be checked", ss.Name, v.Name(), v.Type())
+				failed = true
+				continue
+			}
+			f.Required = true
+		}
+		for _, other := range t.Fields {
+			if exported(other.Name) == exported(f.Name) {
+				ctx.Errorf(position, "%s.%s: conflicts with the field %s", ss.Name, f.Name, other.Name)
+				failed = true
+			}
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	if failed {
+		return nil
+	}
+
+	return t
+}
+
+// param names the param of a field, avoiding the keywords and the receivers and locals of the generated code.
+func param(name string) string {
+	name = lowerInitial(name)
+	switch {
+	case token.IsKeyword(name), name == "b", name == "target", name == "opt", name == "opts", name == "missing", name == "key":
+		return "value"
+	}
+
+	return name
+}
+
+// comparable reports whether the zero value of t can be checked with `==`, `len` or `nil`.
+func comparable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map, *types.Signature:
+		return true
+	}
+
+	return types.Comparable(t)
+}
+
+func fieldSpec(ss *astx.StructSpec, name string) *astx.FieldSpec {
+	for _, fs := range ss.Fields {
+		if fs.Name == name {
+			return fs
+		}
+	}
+
+	return nil
+}
+
+func annotation(annotations []*astx.Annotation, name string) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == name && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func exported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// lowerInitial lowers the leading initialism of name: `TLS` to `tls` and `URLPath` to `urlPath`.
+func lowerInitial(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/photowey/parsergo/gen/gentest"
+)
+
+func TestGenerator(t *testing.T) {
+	gentest.Run(t, New,
+		gentest.Case{
+			Name:      "builderx",
+			Pkg:       "github.com/photowey/parsergo/tests/builderx",
+			WantFiles: []string{"builderx/zz_generated.builder.go"},
+		},
+		gentest.Case{
+			Name: "invalid",
+			Pkg:  "github.com/photowey/parsergo/tests/builderx/invalidx",
+			WantDiags: []string{
+				`Config.Port: invalid default "http": invalid syntax`,
+				`Config.Limits: invalid default "1": no default for a [2]int`,
+				`Config.Hooks: invalid default "x": no default for a map[string]int`,
+				"Config.Options: a required struct{F func()} can't be checked",
+				"Client: the generated NewClient is already declared",
+			},
+		},
+	)
+}
+
+func TestDefaultOf(t *testing.T) {
+	duration := types.NewNamed(types.NewTypeName(0, types.NewPackage("time", "time"), "Duration", nil), types.Typ[types.Int64], nil)
+	tests := []struct {
+		name    string
+		typ     types.Type
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "string", typ: types.Typ[types.String], value: `a "b"`, want: `"a \"b\""`},
+		{name: "bool", typ: types.Typ[types.Bool], value: "1", want: "true"},
+		{name: "hex", typ: types.Typ[types.Int], value: "0x10", want: "16"},
+		{name: "overflow", typ: types.Typ[types.Int8], value: "128", wantErr: true},
+		{name: "negative unsigned", typ: types.Typ[types.Uint], value: "-1", wantErr: true},
+		{name: "float32", typ: types.Typ[types.Float32], value: "0.1", want: "0.1"},
+		{name: "duration", typ: duration, value: "1h30m", want: "90 * time.Minute"},
+		{name: "unit", typ: duration, value: "1s", want: "time.Second"},
+		{name: "nanoseconds", typ: duration, value: "1500ns", want: "1500"},
+		{name: "slice", typ: types.NewSlice(types.Typ[types.String]), value: "a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := defaultOf(tt.typ, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("defaultOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("defaultOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
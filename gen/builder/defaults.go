@@ -0,0 +1,113 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"time"
+)
+
+var units = []struct {
+	unit time.Duration
+	name string
+}{
+	{unit: time.Hour, name: "time.Hour"},
+	{unit: time.Minute, name: "time.Minute"},
+	{unit: time.Second, name: "time.Second"},
+	{unit: time.Millisecond, name: "time.Millisecond"},
+	{unit: time.Microsecond, name: "time.Microsecond"},
+}
+
+// defaultOf renders the `default` tag value as a Go constant of type t:
+// a string, bool or number, or a time.Duration such as `1m30s`.
+func defaultOf(t types.Type, value string) (string, error) {
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", err
+		}
+		return duration(d), nil
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return "", fmt.Errorf("no default for a %s", t)
+	}
+	info := basic.Info()
+	switch {
+	case info&types.IsString != 0:
+		return strconv.Quote(value), nil
+	case info&types.IsBoolean != 0:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case info&types.IsUnsigned != 0:
+		n, err := strconv.ParseUint(value, 0, bits(basic))
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(n, 10), nil
+	case info&types.IsInteger != 0:
+		n, err := strconv.ParseInt(value, 0, bits(basic))
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	case info&types.IsFloat != 0:
+		f, err := strconv.ParseFloat(value, bits(basic))
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, bits(basic)), nil
+	}
+
+	return "", fmt.Errorf("no default for a %s", t)
+}
+
+// duration renders d in its largest whole unit, e.g. `90 * time.Second`.
+func duration(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			if d == u.unit {
+				return u.name
+			}
+			return strconv.FormatInt(int64(d/u.unit), 10) + " * " + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(d), 10)
+}
+
+func bits(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	}
+
+	return 64
+}
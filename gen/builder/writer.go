@@ -0,0 +1,197 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+)
+
+type writer struct {
+	file     *gen.File
+	declared map[string]bool
+}
+
+// declare reserves the package-level names of a struct, reporting those already declared.
+func (w *writer) declare(ctx *gen.Context, ss *astx.StructSpec, names ...string) bool {
+	ok := true
+	for _, name := range names {
+		if w.declared[name] || ctx.Package.Types.Scope().Lookup(name) != nil {
+			ctx.Errorf(ss.Position, "%s: the generated %s is already declared", ss.Name, name)
+			ok = false
+		}
+	}
+	if ok {
+		for _, name := range names {
+			w.declared[name] = true
+		}
+	}
+
+	return ok
+}
+
+func (w *writer) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return w.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+func (w *writer) builder(ctx *gen.Context, t *target) {
+	name := t.Spec.Name
+	builder := t.ident("", name+"Builder")
+
+	methods := map[string]bool{"Build": true}
+	for _, f := range t.Fields {
+		for _, method := range helpers(f) {
+			if methods[method] {
+				ctx.Errorf(t.Spec.Position, "%s: the builder method %s of %s is declared twice", name, method, f.Name)
+				return
+			}
+			methods[method] = true
+		}
+	}
+
+	w.file.Printf("// %s builds a %s.\n", builder, name)
+	w.file.Printf("type %s struct {\ntarget *%s\n}\n\n", builder, name)
+	w.file.Printf("func %s() *%s {\nreturn &%s{\ntarget: %s,\n}\n}\n\n", t.ident("New", name+"Builder"), builder, builder, w.defaults(t))
+
+	for _, f := range t.Fields {
+		typ := w.typeName(f.Var.Type())
+		w.file.Printf("func (b *%s) %s(%s %s) *%s {\nb.target.%s = %s\nreturn b\n}\n\n", builder, exported(f.Name), f.Param, typ, builder, f.Name, f.Param)
+		switch u := f.Var.Type().Underlying().(type) {
+		case *types.Slice:
+			w.file.Printf("func (b *%s) Add%s(%s ...%s) *%s {\nb.target.%s = append(b.target.%s, %s...)\nreturn b\n}\n\n",
+				builder, exported(f.Name), f.Param, w.typeName(u.Elem()), builder, f.Name, f.Name, f.Param)
+		case *types.Map:
+			w.file.Printf("func (b *%s) Put%s(key %s, value %s) *%s {\n", builder, exported(f.Name), w.typeName(u.Key()), w.typeName(u.Elem()), builder)
+			w.file.Printf("if b.target.%s == nil {\nb.target.%s = make(%s)\n}\nb.target.%s[key] = value\nreturn b\n}\n\n", f.Name, f.Name, typ, f.Name)
+		}
+	}
+
+	w.file.Printf("// Build returns the %s, or an error if a required field is zero.\n", name)
+	w.file.Printf("func (b *%s) Build() (*%s, error) {\n", builder, name)
+	w.required(t, "b.target")
+	w.file.Printf("return b.target, nil\n}\n\n")
+}
+
+func (w *writer) options(t *target, prefix string) {
+	name := t.Spec.Name
+	option := t.ident("", name+"Option")
+
+	w.file.Printf("// %s configures a %s.\n", option, name)
+	w.file.Printf("type %s func(*%s)\n\n", option, name)
+	w.file.Printf("// %s returns a %s with its defaults and the opts, or an error if a required field is zero.\n", t.ident("New", name), name)
+	w.file.Printf("func %s(opts ...%s) (*%s, error) {\ntarget := %s\n", t.ident("New", name), option, name, w.defaults(t))
+	w.file.Printf("for _, opt := range opts {\nopt(target)\n}\n\n")
+	w.required(t, "target")
+	w.file.Printf("return target, nil\n}\n\n")
+
+	for _, f := range t.Fields {
+		fn := t.ident(prefix, f.Name)
+		switch u := f.Var.Type().Underlying().(type) {
+		case *types.Slice:
+			w.file.Printf("// %s appends to the %s.\n", fn, f.Name)
+			w.file.Printf("func %s(%s ...%s) %s {\nreturn func(target *%s) {\ntarget.%s = append(target.%s, %s...)\n}\n}\n\n",
+				fn, f.Param, w.typeName(u.Elem()), option, name, f.Name, f.Name, f.Param)
+		case *types.Map:
+			w.file.Printf("// %s puts an entry of the %s.\n", fn, f.Name)
+			w.file.Printf("func %s(key %s, value %s) %s {\nreturn func(target *%s) {\n", fn, w.typeName(u.Key()), w.typeName(u.Elem()), option, name)
+			w.file.Printf("if target.%s == nil {\ntarget.%s = make(%s)\n}\ntarget.%s[key] = value\n}\n}\n\n", f.Name, f.Name, w.typeName(f.Var.Type()), f.Name)
+		default:
+			w.file.Printf("func %s(%s %s) %s {\nreturn func(target *%s) {\ntarget.%s = %s\n}\n}\n\n",
+				fn, f.Param, w.typeName(f.Var.Type()), option, name, f.Name, f.Param)
+		}
+	}
+}
+
+// defaults renders a pointer to the struct with the default values of its fields.
+func (w *writer) defaults(t *target) string {
+	values := make([]string, 0)
+	for _, f := range t.Fields {
+		if f.Default == "" {
+			continue
+		}
+		value := f.Default
+		if strings.HasPrefix(value, "time.") || strings.Contains(value, " * time.") {
+			value = strings.ReplaceAll(value, "time.", w.file.Imports.NeedImport("time")+".")
+		}
+		values = append(values, f.Name+": "+value+",\n")
+	}
+	if len(values) == 0 {
+		return "&" + t.Spec.Name + "{}"
+	}
+
+	return "&" + t.Spec.Name + "{\n" + strings.Join(values, "") + "}"
+}
+
+// required renders the checks of the required fields of the struct at the expression x.
+func (w *writer) required(t *target, x string) {
+	checks := 0
+	for _, f := range t.Fields {
+		if !f.Required {
+			continue
+		}
+		if checks == 0 {
+			w.file.Printf("missing := make([]string, 0)\n")
+		}
+		checks++
+		w.file.Printf("if %s {\nmissing = append(missing, %q)\n}\n", w.zero(f.Var.Type(), x+"."+f.Name), f.Name)
+	}
+	if checks == 0 {
+		return
+	}
+
+	fmtPkg := w.file.Imports.NeedImport("fmt")
+	stringsPkg := w.file.Imports.NeedImport("strings")
+	w.file.Printf("if len(missing) > 0 {\nreturn nil, %s.Errorf(\"%s: missing the required %%s\", %s.Join(missing, \", \"))\n}\n\n", fmtPkg, t.Spec.Name, stringsPkg)
+}
+
+// zero renders the check of the zero value of x.
+func (w *writer) zero(t types.Type, x string) string {
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return "len(" + x + ") == 0"
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return x + ` == ""`
+		case u.Info()&types.IsBoolean != 0:
+			return "!" + x
+		}
+		return x + " == 0"
+	case *types.Pointer, *types.Interface, *types.Chan, *types.Signature:
+		return x + " == nil"
+	}
+
+	return x + " == (" + w.typeName(t) + "{})"
+}
+
+// helpers returns the names of the builder methods of a field.
+func helpers(f *field) []string {
+	methods := []string{exported(f.Name)}
+	switch f.Var.Type().Underlying().(type) {
+	case *types.Slice:
+		methods = append(methods, "Add"+exported(f.Name))
+	case *types.Map:
+		methods = append(methods, "Put"+exported(f.Name))
+	}
+
+	return methods
+}
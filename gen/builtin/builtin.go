@@ -0,0 +1,66 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package builtin lists the built-in generators.
+// Importing it registers the annotations of every one of them, e.g. for the analyzers to know them.
+package builtin
+
+import (
+	"sort"
+
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/gen/accessors"
+	"github.com/photowey/parsergo/gen/builder"
+	"github.com/photowey/parsergo/gen/deepcopy"
+	"github.com/photowey/parsergo/gen/equals"
+	"github.com/photowey/parsergo/gen/jsonschema"
+	"github.com/photowey/parsergo/gen/mapper"
+	"github.com/photowey/parsergo/gen/mock"
+	"github.com/photowey/parsergo/gen/openapi"
+	"github.com/photowey/parsergo/gen/proto"
+	"github.com/photowey/parsergo/gen/proxy"
+	"github.com/photowey/parsergo/gen/routes"
+	"github.com/photowey/parsergo/gen/tostring"
+	"github.com/photowey/parsergo/gen/validate"
+)
+
+// Generators are the built-in generators, by name.
+var Generators = map[string]func() gen.Generator{
+	"routes":     func() gen.Generator { return routes.New() },
+	"openapi":    func() gen.Generator { return openapi.New() },
+	"jsonschema": func() gen.Generator { return jsonschema.New() },
+	"proto":      func() gen.Generator { return proto.New() },
+	"mock":       func() gen.Generator { return mock.New() },
+	"proxy":      func() gen.Generator { return proxy.New() },
+	"builder":    func() gen.Generator { return builder.New() },
+	"mapper":     func() gen.Generator { return mapper.New() },
+	"accessors":  func() gen.Generator { return accessors.New() },
+	"tostring":   func() gen.Generator { return tostring.New() },
+	"equals":     func() gen.Generator { return equals.New() },
+	"deepcopy":   func() gen.Generator { return deepcopy.New() },
+	"validate":   func() gen.Generator { return validate.New() },
+}
+
+// Names returns the names of the generators, sorted.
+func Names() []string {
+	names := make([]string, 0, len(Generators))
+	for name := range Generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2019-2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deepcopy
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const (
+	DeepCopyAnnotation = "DeepCopy"
+	// GenerateMarker enables the generation for a package, or enables or disables it for a type.
+	GenerateMarker = "kubebuilder:object:generate"
+)
+
+func init() {
+	parser.RegisterMarker(
+		&parser.MarkerDefinition{
+			Name:    DeepCopyAnnotation,
+			Targets: []parser.Target{parser.TargetStruct},
+			Help:    "generates the DeepCopy and DeepCopyInto methods of the struct",
+		},
+		&parser.MarkerDefinition{
+			Name:    GenerateMarker,
+			Targets: []parser.Target{parser.TargetPackage, parser.TargetStruct},
+			Help:    "enables or disables the deepcopy generation for the package or the type: `+kubebuilder:object:generate=true`",
+		},
+	)
+}
+
+// Generator emits controller-gen style DeepCopyInto and DeepCopy methods for the @DeepCopy structs,
+// or for every struct of a package marked `+kubebuilder:object:generate=true`.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "deepcopy"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	enabled := false
+	for _, fs := range ctx.Spec.Package.Files {
+		for _, anno := range fs.Annotations {
+			if anno.Name == GenerateMarker && anno.Marker {
+				enabled = anno.Value() == "true"
+			}
+		}
+	}
+	explicit := make(map[string]bool)
+	specs := make(map[string]*astx.StructSpec)
+	for _, ss := range ctx.Spec.Package.Structs {
+		specs[ss.Name] = ss
+		for _, anno := range ss.Annotations {
+			switch {
+			case anno.Name == DeepCopyAnnotation && !anno.Marker:
+				explicit[ss.Name] = true
+			case anno.Name == GenerateMarker && anno.Marker:
+				explicit[ss.Name] = anno.Value() == "true"
+			}
+		}
+	}
+	if !enabled && len(explicit) == 0 {
+		return nil
+	}
+
+	ctx.Package.NeedTypesInfo()
+	c := &copier{ctx: ctx, targets: make(map[*types.Named]bool), visiting: make(map[types.Type]bool)}
+	scope := ctx.Package.Types.Scope()
+	names := make([]string, 0)
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		if on, found := explicit[name]; found && !on || !found && !enabled {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if _, isStruct := obj.Type().Underlying().(*types.Struct); !ok || !isStruct || named.TypeParams().Len() > 0 {
+			if explicit[name] {
+				ctx.Errorf(specs[name].Position, "%s: only a non-generic struct can be deep copied", name)
+			}
+			continue
+		}
+		if gen.Locked(named) {
+			ctx.Errorf(astx.NewPosition(ctx.Package.Fset, obj.Pos(), token.NoPos), "%s: a struct holding a lock can't be deep copied", name)
+			continue
+		}
+		c.targets[named] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	file := ctx.NewFile()
+	c.file = file
+	for _, name := range names {
+		c.generate(scope.Lookup(name).Type().(*types.Named))
+	}
+
+	return ctx.AddFile(file)
+}
+
+type copier struct {
+	ctx      *gen.Context
+	file     *gen.File
+	targets  map[*types.Named]bool // the types getting the methods
+	visiting map[types.Type]bool
+}
+
+func (c *copier) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return c.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+func (c *copier) generate(named *types.Named) {
+	name := named.Obj().Name()
+	st := named.Underlying().(*types.Struct)
+	position := astx.NewPosition(c.ctx.Package.Fset, named.Obj().Pos(), token.NoPos)
+
+	c.file.Printf("// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.\n")
+	c.file.Printf("func (in *%s) DeepCopyInto(out *%s) {\n*out = *in\n", name, name)
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Name() == "_" || !c.deep(field.Type()) {
+			continue
+		}
+		if err := c.copy(field.Type(), "in."+field.Name(), "out."+field.Name(), 0); err != nil {
+			c.ctx.Errorf(position, "%s.%s: %v", name, field.Name(), err)
+		}
+	}
+	c.file.Printf("}\n\n")
+
+	c.file.Printf("// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new %s.\n", name)
+	c.file.Printf("func (in *%s) DeepCopy() *%s {\nif in == nil {\nreturn nil\n}\nout := new(%s)\nin.DeepCopyInto(out)\nreturn out\n}\n\n", name, name, name)
+}
+
+// copy renders the deep copy of in into out, two addressable expressions of type t, out being a shallow copy of in.
+func (c *copier) copy(t types.Type, in, out string, depth int) error {
+	if c.hasDeepCopy(t) {
+		c.file.Printf("%s.DeepCopyInto(&%s)\n", in, out)
+		return nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		c.file.Printf("if %s != nil {\nin, out := &%s, &%s\n*out = new(%s)\n", in, in, out, c.typeName(u.Elem()))
+		switch {
+		case !c.deep(u.Elem()):
+			c.file.Printf("**out = **in\n")
+		case c.hasDeepCopy(u.Elem()):
+			c.file.Printf("(*in).DeepCopyInto(*out)\n")
+		default:
+			c.file.Printf("**out = **in\n")
+			if err := c.copy(u.Elem(), "(**in)", "(**out)", depth); err != nil {
+				return err
+			}
+		}
+		c.file.Printf("}\n")
+	case *types.Slice:
+		i := index(depth)
+		c.file.Printf("if %s != nil {\nin, out := &%s, &%s\n*out = make(%s, len(*in))\n", in, in, out, c.typeName(t))
+		switch {
+		case !c.deep(u.Elem()):
+			c.file.Printf("copy(*out, *in)\n")
+		case c.hasDeepCopy(u.Elem()):
+			c.file.Printf("for %s := range *in {\n(*in)[%s].DeepCopyInto(&(*out)[%s])\n}\n", i, i, i)
+		default:
+			c.file.Printf("copy(*out, *in)\nfor %s := range *in {\n", i)
+			if err := c.copy(u.Elem(), "(*in)["+i+"]", "(*out)["+i+"]", depth+1); err != nil {
+				return err
+			}
+			c.file.Printf("}\n")
+		}
+		c.file.Printf("}\n")
+	case *types.Map:
+		if c.deep(u.Key()) {
+			return fmt.Errorf("can't deep copy the map keys of %s", t)
+		}
+		c.file.Printf("if %s != nil {\nin, out := &%s, &%s\n*out = make(%s, len(*in))\nfor key, val := range *in {\n", in, in, out, c.typeName(t))
+		if !c.deep(u.Elem()) {
+			c.file.Printf("(*out)[key] = val\n")
+		} else {
+			c.file.Printf("outVal := val\n")
+			if err := c.copy(u.Elem(), "val", "outVal", depth); err != nil {
+				return err
+			}
+			c.file.Printf("(*out)[key] = outVal\n")
+		}
+		c.file.Printf("}\n}\n")
+	case *types.Array:
+		i := index(depth)
+		c.file.Printf("for %s := range %s {\n", i, in)
+		if err := c.copy(u.Elem(), in+"["+i+"]", out+"["+i+"]", depth+1); err != nil {
+			return err
+		}
+		c.file.Printf("}\n")
+	case *types.Struct:
+		for j := 0; j < u.NumFields(); j++ {
+			field := u.Field(j)
+			if !c.deep(field.Type()) {
+				continue
+			}
+			if err := c.copy(field.Type(), in+"."+field.Name(), out+"."+field.Name(), depth); err != nil {
+				return err
+			}
+		}
+	case *types.Interface:
+		return fmt.Errorf("can't deep copy the interface %s", t)
+	}
+
+	return nil
+}
+
+// deep reports whether a shallow copy of t shares memory with the original.
+func (c *copier) deep(t types.Type) bool {
+	if named, ok := t.(*types.Named); ok && !c.targets[named] && c.hasDeepCopy(t) {
+		return true
+	}
+	if c.visiting[t] {
+		return false
+	}
+	c.visiting[t] = true
+	defer delete(c.visiting, t)
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		return true
+	case *types.Array:
+		return c.deep(u.Elem())
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok && !c.accessible(named, u) {
+			return false // e.g. time.Time, copied as a value
+		}
+		for i := 0; i < u.NumFields(); i++ {
+			if c.deep(u.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasDeepCopy reports whether t is a named type with a DeepCopyInto method, declared or generated.
+func (c *copier) hasDeepCopy(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	if c.targets[named] {
+		return true
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), "DeepCopyInto")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+
+	return sig.Params().Len() == 1 && types.Identical(sig.Params().At(0).Type(), types.NewPointer(named)) && sig.Results().Len() == 0
+}
+
+// accessible reports whether the generated code can copy the fields of the struct one by one.
+func (c *copier) accessible(named *types.Named, st *types.Struct) bool {
+	if named.Obj().Pkg() == c.ctx.Package.Types {
+		return true
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if !st.Field(i).Exported() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func index(depth int) string {
+	if depth < 4 {
+		return string("ijkl"[depth])
+	}
+
+	return fmt.Sprintf("i%d", depth)
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepcopy
+
+import (
+	"testing"
+
+	"github.com/photowey/parsergo/gen/gentest"
+)
+
+func TestGenerator(t *testing.T) {
+	gentest.Run(t, New,
+		gentest.Case{
+			Name:      "objectx",
+			Pkg:       "github.com/photowey/parsergo/tests/objectx",
+			WantFiles: []string{"objectx/zz_generated.deepcopy.go"},
+		},
+		gentest.Case{
+			Name:      "package",
+			Pkg:       "github.com/photowey/parsergo/tests/objectx/v1",
+			WantFiles: []string{"objectx/v1/zz_generated.deepcopy.go"},
+		},
+		gentest.Case{
+			Name: "invalid",
+			Pkg:  "github.com/photowey/parsergo/tests/objectx/invalidx",
+			WantDiags: []string{
+				"Guarded: a struct holding a lock can't be deep copied",
+				"Handler.Next: can't deep copy the interface interface{}",
+			},
+		},
+	)
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package equals
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const EqualsAnnotation = "Equals"
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    EqualsAnnotation,
+		Targets: []parser.Target{parser.TargetStruct},
+		Help:    "generates the `Equal(other *T) bool` method of the struct, leaving out some fields: `@Equals(exclude={cache})`",
+	})
+}
+
+// Generator emits an `Equal(other *T) bool` method per @Equals struct, comparing the pointees,
+// the elements and the entries rather than the pointers, slices and maps themselves. The locks are left out.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "equals"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	annotated := make([]*astx.StructSpec, 0)
+	for _, ss := range ctx.Spec.Package.Structs {
+		if annotation(ss.Annotations) != nil {
+			annotated = append(annotated, ss)
+		}
+	}
+	if len(annotated) == 0 {
+		return nil
+	}
+
+	ctx.Package.NeedTypesInfo()
+	c := &comparer{ctx: ctx, targets: make(map[*types.Named]bool)}
+	named := make(map[*astx.StructSpec]*types.Named)
+	for _, ss := range annotated {
+		obj, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+		if !ok {
+			ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+			continue
+		}
+		t, ok := obj.Type().(*types.Named)
+		if !ok || t.TypeParams().Len() > 0 {
+			ctx.Errorf(ss.Position, "%s: a generic struct can't get an Equal method", ss.Name)
+			continue
+		}
+		if existing, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, t.Obj().Pkg(), "Equal"); existing != nil {
+			ctx.Errorf(ss.Position, "%s: Equal is already declared", ss.Name)
+			continue
+		}
+		c.targets[t] = true
+		named[ss] = t
+	}
+
+	c.file = ctx.NewFile()
+	for _, ss := range annotated {
+		if t := named[ss]; t != nil {
+			c.generate(ss, t)
+		}
+	}
+	if len(named) == 0 {
+		return nil
+	}
+
+	return ctx.AddFile(c.file)
+}
+
+type comparer struct {
+	ctx     *gen.Context
+	file    *gen.File
+	targets map[*types.Named]bool // the types getting the method
+}
+
+func (c *comparer) generate(ss *astx.StructSpec, named *types.Named) {
+	anno := annotation(ss.Annotations)
+	excluded := make(map[string]bool)
+	if arg := anno.Arg("exclude"); arg != nil {
+		values := arg.Values
+		if values == nil {
+			values = []string{arg.Value}
+		}
+		for _, name := range values {
+			excluded[name] = true
+		}
+	}
+
+	recv := unexported(ss.Name[:1])
+	switch recv {
+	case "i", "j", "k", "l":
+		recv = "x" // the loop indexes
+	}
+	c.file.Printf("// Equal reports whether the %s equals other, field by field.\n", ss.Name)
+	c.file.Printf("func (%s *%s) Equal(other *%s) bool {\nif %s == nil || other == nil {\nreturn %s == other\n}\n", recv, ss.Name, ss.Name, recv, recv)
+	st := named.Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if excluded[field.Name()] {
+			delete(excluded, field.Name())
+			continue
+		}
+		if field.Name() == "_" || gen.Locked(field.Type()) {
+			continue
+		}
+		if err := c.compare(field.Type(), recv+"."+field.Name(), "other."+field.Name(), 0); err != nil {
+			c.ctx.Errorf(anno.Position, "%s.%s: %v", ss.Name, field.Name(), err)
+		}
+	}
+	for name := range excluded {
+		c.ctx.Errorf(anno.Position, "%s: no field %s to exclude", ss.Name, name)
+	}
+	c.file.Printf("\nreturn true\n}\n\n")
+}
+
+// compare renders the statements returning false if a and b, two addressable expressions of type t, differ.
+func (c *comparer) compare(t types.Type, a, b string, depth int) error {
+	switch method := c.equal(t); method {
+	case "pointer":
+		c.file.Printf("if !%s.Equal(&%s) {\nreturn false\n}\n", a, b)
+		return nil
+	case "value":
+		c.file.Printf("if !%s.Equal(%s) {\nreturn false\n}\n", a, b)
+		return nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		if c.equal(u.Elem()) == "pointer" {
+			c.file.Printf("if !%s.Equal(%s) {\nreturn false\n}\n", a, b)
+			return nil
+		}
+		c.file.Printf("if (%s == nil) != (%s == nil) {\nreturn false\n}\nif %s != nil {\n", a, b, a)
+		deref := func(x string) string {
+			if _, ok := u.Elem().Underlying().(*types.Basic); ok {
+				return "*" + x
+			}
+			return "(*" + x + ")"
+		}
+		if err := c.compare(u.Elem(), deref(a), deref(b), depth); err != nil {
+			return err
+		}
+		c.file.Printf("}\n")
+	case *types.Slice:
+		i := index(depth)
+		c.file.Printf("if len(%s) != len(%s) {\nreturn false\n}\nfor %s := range %s {\n", a, b, i, a)
+		if err := c.compare(u.Elem(), a+"["+i+"]", b+"["+i+"]", depth+1); err != nil {
+			return err
+		}
+		c.file.Printf("}\n")
+	case *types.Array:
+		i := index(depth)
+		c.file.Printf("for %s := range %s {\n", i, a)
+		if err := c.compare(u.Elem(), a+"["+i+"]", b+"["+i+"]", depth+1); err != nil {
+			return err
+		}
+		c.file.Printf("}\n")
+	case *types.Map:
+		n := strconv.Itoa(depth + 1)
+		c.file.Printf("if len(%s) != len(%s) {\nreturn false\n}\n", a, b)
+		c.file.Printf("for key%s, a%s := range %s {\nb%s, ok := %s[key%s]\nif !ok {\nreturn false\n}\n", n, n, a, n, b, n)
+		if err := c.compare(u.Elem(), "a"+n, "b"+n, depth+1); err != nil {
+			return err
+		}
+		c.file.Printf("}\n")
+	case *types.Struct:
+		named, ok := t.(*types.Named)
+		if !ok || named.Obj().Pkg() == c.ctx.Package.Types || exported(u) {
+			for i := 0; i < u.NumFields(); i++ {
+				if field := u.Field(i); field.Name() != "_" {
+					if err := c.compare(field.Type(), a+"."+field.Name(), b+"."+field.Name(), depth); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		if !types.Comparable(t) {
+			return fmt.Errorf("can't compare a %s", t)
+		}
+		c.file.Printf("if %s != %s {\nreturn false\n}\n", a, b)
+	case *types.Interface:
+		reflectPkg := c.file.Imports.NeedImport("reflect")
+		c.file.Printf("if !%s.DeepEqual(%s, %s) {\nreturn false\n}\n", reflectPkg, a, b)
+	case *types.Signature:
+		return fmt.Errorf("can't compare a %s", t)
+	default:
+		c.file.Printf("if %s != %s {\nreturn false\n}\n", a, b)
+	}
+
+	return nil
+}
+
+// equal returns how t compares with an Equal method of its own: "pointer" for `Equal(*T) bool`,
+// declared or generated, "value" for `Equal(T) bool` like time.Time, or "".
+func (c *comparer) equal(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+	if c.targets[named] {
+		return "pointer"
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), "Equal")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return ""
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool]) {
+		return ""
+	}
+	switch param := sig.Params().At(0).Type(); {
+	case types.Identical(param, types.NewPointer(named)):
+		return "pointer"
+	case types.Identical(param, named):
+		return "value"
+	}
+
+	return ""
+}
+
+// exported reports whether all the fields of the struct are exported.
+func exported(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if !st.Field(i).Exported() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func index(depth int) string {
+	if depth < 4 {
+		return string("ijkl"[depth])
+	}
+
+	return "i" + strconv.Itoa(depth)
+}
+
+func annotation(annotations []*astx.Annotation) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == EqualsAnnotation && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+)
+
+// Generator renders the files of one package at a time.
+// Generators are run sequentially, so state kept across packages, e.g. to detect conflicts, is safe.
+type Generator interface {
+	Name() string
+	Generate(ctx *Context) error
+}
+
+// Finisher is implemented by the generators writing their files once every package is generated,
+// e.g. a single document for all of them.
+type Finisher interface {
+	Finish(result *Result) error
+}
+
+type Context struct {
+	Package     *loader.Package
+	Spec        *astx.AstSpec
+	Diagnostics []*astx.Diagnostic
+
+	generator string
+	files     map[string][]byte
+}
+
+// Errorf records a generation-time error, failing the run once every package is generated.
+func (ctx *Context) Errorf(position astx.Position, format string, args ...interface{}) {
+	ctx.Diagnostics = append(ctx.Diagnostics, &astx.Diagnostic{
+		Severity: astx.SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+		Position: position,
+	})
+}
+
+// NewFile starts `zz_generated.<generator>.go` in the package directory.
+// The file is excluded by the `ignore_autogenerated` tag, so the loader never sees stale generated code.
+func (ctx *Context) NewFile() *File {
+	return &File{
+		Name:            fmt.Sprintf("zz_generated.%s.go", ctx.generator),
+		Generator:       ctx.generator,
+		Package:         ctx.Package.Name,
+		Imports:         parser.NewPackageImports(ctx.Package),
+		BuildConstraint: "!" + loader.IgnoreAutogeneratedTag,
+	}
+}
+
+// AddFile adds the Go file to the output, formatted.
+func (ctx *Context) AddFile(file *File) error {
+	content, err := file.Bytes()
+	if err != nil {
+		return err
+	}
+	ctx.WriteFile(file.Name, content)
+
+	return nil
+}
+
+// WriteFile adds a file with a name relative to the package directory to the output, as is.
+func (ctx *Context) WriteFile(name string, content []byte) {
+	ctx.files[filepath.Join(ctx.Dir(), name)] = content
+}
+
+func (ctx *Context) Dir() string {
+	for _, files := range [][]string{ctx.Package.GoFiles, ctx.Package.CompiledGoFiles} {
+		if len(files) > 0 {
+			return filepath.Dir(files[0])
+		}
+	}
+
+	return "."
+}
+
+// File is a generated Go file of the package.
+type File struct {
+	Name      string
+	Generator string
+	Package   string
+	Imports   *parser.Imports
+	// BuildConstraint is the `//go:build` expression of the file, if any.
+	BuildConstraint string
+
+	body bytes.Buffer
+}
+
+func (f *File) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+// Bytes renders the header, package clause, imports and body, gofmt-ed.
+func (f *File) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if f.BuildConstraint != "" {
+		fmt.Fprintf(&b, "//go:build %s\n\n", f.BuildConstraint)
+	}
+	fmt.Fprintf(&b, "// Code generated by parsergo %s. DO NOT EDIT.\n\n", f.Generator)
+	fmt.Fprintf(&b, "package %s\n\n", f.Package)
+	b.WriteString(f.Imports.ImportBlock())
+	b.WriteString("\n")
+	b.Write(f.body.Bytes())
+
+	content, err := format.Source(b.Bytes())
+	if err != nil {
+		return b.Bytes(), fmt.Errorf("gen: formatting %s: %w", f.Name, err)
+	}
+
+	return content, nil
+}
+
+type Result struct {
+	// Files maps the output paths to their contents.
+	Files       map[string][]byte
+	Diagnostics []*astx.Diagnostic
+}
+
+// Paths returns the output paths, sorted.
+func (r *Result) Paths() []string {
+	paths := make([]string, 0, len(r.Files))
+	for path := range r.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+func (r *Result) Write() error {
+	for _, path := range r.Paths() {
+		if err := os.WriteFile(path, r.Files[path], 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Error aggregates the error diagnostics of a run.
+type Error struct {
+	Diagnostics []*astx.Diagnostic
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, 0, len(e.Diagnostics))
+	for _, diagnostic := range e.Diagnostics {
+		lines = append(lines, diagnostic.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Run runs the generators over the roots, skipping test mains, parsing each package once.
+// Any error diagnostic fails the run with an *Error before the finishers run, the result still carrying every diagnostic.
+func Run(roots []*loader.Package, generators ...Generator) (*Result, error) {
+	result := &Result{
+		Files:       make(map[string][]byte),
+		Diagnostics: make([]*astx.Diagnostic, 0),
+	}
+
+	for _, root := range roots {
+		if root.IsTestMain() || root.TestVariant() != loader.TestVariantNone {
+			continue
+		}
+		spec := parser.Parse(root)
+		for _, generator := range generators {
+			ctx := &Context{
+				Package:     root,
+				Spec:        spec,
+				Diagnostics: make([]*astx.Diagnostic, 0),
+				generator:   generator.Name(),
+				files:       result.Files,
+			}
+			if err := generator.Generate(ctx); err != nil {
+				return result, fmt.Errorf("gen: %s: %s: %w", generator.Name(), root.PkgPath, err)
+			}
+			result.Diagnostics = append(result.Diagnostics, ctx.Diagnostics...)
+		}
+	}
+
+	failed := make([]*astx.Diagnostic, 0)
+	for _, diagnostic := range result.Diagnostics {
+		if diagnostic.Severity == astx.SeverityError {
+			failed = append(failed, diagnostic)
+		}
+	}
+	if len(failed) > 0 {
+		return result, &Error{Diagnostics: failed}
+	}
+
+	for _, generator := range generators {
+		if finisher, ok := generator.(Finisher); ok {
+			if err := finisher.Finish(result); err != nil {
+				return result, fmt.Errorf("gen: %s: %w", generator.Name(), err)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gentest runs the generators over the fixture packages of the tests directory,
+// comparing their output with golden files and their diagnostics with the expected messages.
+package gentest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/loader"
+)
+
+// Case is a fixture package, either generated into its golden files or failing with its diagnostics.
+type Case struct {
+	Name string
+	Pkg  string
+	// With are the generators running before the tested one, e.g. the ones sharing its model.
+	With []gen.Generator
+	// WantFiles are the golden files, by slash-separated path relative to the tests directory.
+	WantFiles []string
+	// WantDiags are the messages of the error diagnostics, in order.
+	WantDiags []string
+}
+
+// Run runs a new generator over the package of each case, in a subtest.
+func Run[G gen.Generator](t *testing.T, newGenerator func() G, cases ...Case) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			generator := newGenerator()
+			roots, err := loader.LoadRoots(tc.Pkg)
+			if err != nil {
+				t.Fatalf("LoadRoots() error = %v", err)
+			}
+			result, err := gen.Run(roots, append(tc.With, generator)...)
+
+			if len(tc.WantDiags) > 0 {
+				diagnostics(t, err, tc.WantDiags)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			for _, file := range tc.WantFiles {
+				golden(t, generator, result, file)
+			}
+		})
+	}
+}
+
+// Path is the absolute path of a slash-separated path relative to the tests directory.
+func Path(t *testing.T, name string) string {
+	t.Helper()
+
+	path, err := filepath.Abs(filepath.Join("..", "..", "tests", filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func golden(t *testing.T, generator gen.Generator, result *gen.Result, name string) {
+	t.Helper()
+
+	path := Path(t, name)
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(result.Files[path]); got != string(want) {
+		t.Errorf("Run() = %s, want the content of %s, regenerate it with `parsergo gen -g %s`", got, name, generator.Name())
+	}
+}
+
+func diagnostics(t *testing.T, err error, want []string) {
+	t.Helper()
+
+	var genErr *gen.Error
+	if !errors.As(err, &genErr) {
+		t.Fatalf("Run() error = %v, want %d diagnostics", err, len(want))
+	}
+	got := make([]string, 0, len(genErr.Diagnostics))
+	for _, diagnostic := range genErr.Diagnostics {
+		got = append(got, diagnostic.Message)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Run() diagnostics = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Run() diagnostic #%d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
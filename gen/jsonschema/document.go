@@ -0,0 +1,109 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"github.com/photowey/parsergo/gen/schema"
+)
+
+// Draft is the dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema 2020-12 object, its keywords in the order they are written.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // a name, or the names of a nullable type
+	Format               string             `json:"format,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// schemaOf converts a neutral schema, ref maps the definition ids to their `$ref`s.
+func schemaOf(s *schema.Schema, ref func(id string) string) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		out := &Schema{Ref: ref(s.Ref), Description: s.Description}
+		if s.Nullable {
+			out = &Schema{
+				Description: s.Description,
+				AnyOf:       []*Schema{{Ref: ref(s.Ref)}, {Type: "null"}},
+			}
+		}
+		return out
+	}
+
+	out := &Schema{
+		Description:          s.Description,
+		Format:               s.Format,
+		Enum:                 s.Enum,
+		Items:                schemaOf(s.Items, ref),
+		Required:             s.Required,
+		AdditionalProperties: schemaOf(s.AdditionalProperties, ref),
+		MinLength:            s.MinLength,
+		MaxLength:            s.MaxLength,
+		MinItems:             s.MinItems,
+		MaxItems:             s.MaxItems,
+		Pattern:              s.Pattern,
+	}
+	if s.Type != "" {
+		out.Type = s.Type
+		if s.Nullable {
+			out.Type = []string{s.Type, "null"}
+		}
+	}
+	if s.Nullable && len(s.Enum) > 0 {
+		out.Enum = append(append(make([]interface{}, 0, len(s.Enum)+1), s.Enum...), nil)
+	}
+	if s.ExclusiveMinimum {
+		out.ExclusiveMinimum = s.Minimum
+	} else {
+		out.Minimum = s.Minimum
+	}
+	if s.ExclusiveMaximum {
+		out.ExclusiveMaximum = s.Maximum
+	} else {
+		out.Maximum = s.Maximum
+	}
+	if s.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			out.Properties[name] = schemaOf(property, ref)
+		}
+	}
+
+	return out
+}
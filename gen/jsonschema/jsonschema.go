@@ -0,0 +1,144 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/gen/schema"
+	"github.com/photowey/parsergo/parser"
+	"github.com/photowey/parsergo/pkg/stringx"
+)
+
+const SchemaAnnotation = "Schema"
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    SchemaAnnotation,
+		Targets: []parser.Target{parser.TargetStruct},
+		Help:    "writes the JSON Schema of the struct, optionally to the named file: `@Schema(\"config.schema.json\", id=\"https://example.com/config\", title=\"Config\")`",
+	})
+}
+
+// Generator writes a JSON Schema 2020-12 document per @Schema struct, `<snake_name>.schema.json` by default,
+// the named types it references in its `$defs`.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "jsonschema"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	written := make(map[string]*astx.StructSpec)
+	for _, ss := range ctx.Spec.Package.Structs {
+		anno := annotation(ss)
+		if anno == nil {
+			continue
+		}
+
+		ctx.Package.NeedTypesInfo()
+		tn, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+		if !ok {
+			ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			ctx.Errorf(anno.Position, "%s: @%s needs a non-generic struct", ss.Name, SchemaAnnotation)
+			continue
+		}
+
+		name := anno.Value()
+		if name == "" {
+			name = stringx.Snake(ss.Name) + ".schema.json"
+		}
+		if first := written[name]; first != nil {
+			ctx.Errorf(anno.Position, "%s: %s is already written for %s", ss.Name, name, first.Name)
+			continue
+		}
+		written[name] = ss
+
+		content, err := document(ctx, named, anno)
+		if err != nil {
+			return err
+		}
+		ctx.WriteFile(name, content)
+	}
+
+	return nil
+}
+
+// document renders the schema of t, its own refs pointing to the document root.
+func document(ctx *gen.Context, t *types.Named, anno *astx.Annotation) ([]byte, error) {
+	builder := schema.NewBuilder()
+	builder.AddPackage(ctx.Package, ctx.Spec)
+	root := builder.Schema(t).Ref
+	names := builder.Names()
+	ref := func(id string) string {
+		if id == root {
+			return "#"
+		}
+		return "#/$defs/" + names[id]
+	}
+
+	doc := schemaOf(builder.Definitions[root].Schema, ref)
+	doc.Schema = Draft
+	doc.Title = t.Obj().Name()
+	if arg := anno.Arg("title"); arg != nil {
+		doc.Title = arg.Value
+	}
+	if arg := anno.Arg("id"); arg != nil {
+		doc.ID = arg.Value
+	}
+	for _, def := range builder.Sorted() {
+		if def.ID == root {
+			continue
+		}
+		if doc.Defs == nil {
+			doc.Defs = make(map[string]*Schema)
+		}
+		doc.Defs[names[def.ID]] = schemaOf(def.Schema, ref)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func annotation(ss *astx.StructSpec) *astx.Annotation {
+	for _, anno := range ss.Annotations {
+		if anno.Name == SchemaAnnotation && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gen
+
+import (
+	"go/types"
+)
+
+// Locked reports whether a value of t holds a lock, such as a sync.Mutex, which `go vet` reports copied.
+func Locked(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return false
+	}
+	lock, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, "Lock")
+	unlock, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, "Unlock")
+	if _, ok := lock.(*types.Func); ok {
+		if _, ok := unlock.(*types.Func); ok {
+			return true
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if Locked(u.Field(i).Type()) {
+				return true
+			}
+		}
+	case *types.Array:
+		return Locked(u.Elem())
+	}
+
+	return false
+}
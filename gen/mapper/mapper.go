@@ -0,0 +1,447 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapper
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const MapToAnnotation = "MapTo"
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:       MapToAnnotation,
+		Targets:    []parser.Target{parser.TargetStruct},
+		Repeatable: true,
+		Help:       "generates a mapper to another struct, optionally named and ignoring some of its fields: `@MapTo(domain.User, name=ToUser, ignore={ID})`",
+	})
+}
+
+// Generator emits a `<Struct>To<Other>(src) *Other` func per @MapTo annotation. The fields match by name,
+// or by the `map:"Other"` tag of the source field, `map:"-"` leaves a field out. The nested structs
+// without a mapper of their own get an unexported one.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "mapper"
+}
+
+type mapping struct {
+	Name     string
+	Src      *types.Named
+	Dst      *types.Named
+	Ignore   map[string]bool
+	Position astx.Position // of the @MapTo annotation, the nested mappings report there too
+}
+
+type mapper struct {
+	ctx      *gen.Context
+	file     *gen.File
+	mappings []*mapping
+	declared map[string]bool
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	m := &mapper{ctx: ctx, declared: make(map[string]bool)}
+	for _, fs := range ctx.Spec.Package.Files {
+		for _, ss := range fs.Structs {
+			for _, anno := range ss.Annotations {
+				if anno.Name != MapToAnnotation || anno.Marker {
+					continue
+				}
+				ctx.Package.NeedTypesInfo()
+				m.declare(fs, ss, anno)
+			}
+		}
+	}
+	if len(m.mappings) == 0 {
+		return nil
+	}
+
+	m.file = ctx.NewFile()
+	for i := 0; i < len(m.mappings); i++ {
+		m.render(m.mappings[i])
+	}
+
+	return ctx.AddFile(m.file)
+}
+
+func (m *mapper) declare(fs *astx.FileSpec, ss *astx.StructSpec, anno *astx.Annotation) {
+	src, ok := m.ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+	if !ok {
+		m.ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+		return
+	}
+	dstObj := fs.LookupType(m.ctx.Package.Types, anno.Value())
+	if dstObj == nil {
+		m.ctx.Errorf(anno.Position, "%s: unknown struct %q to map to", ss.Name, anno.Value())
+		return
+	}
+	srcNamed, dstNamed := structNamed(src.Type()), structNamed(dstObj.Type())
+	if srcNamed == nil || dstNamed == nil {
+		m.ctx.Errorf(anno.Position, "%s: @%s maps a non-generic struct to another", ss.Name, MapToAnnotation)
+		return
+	}
+
+	mp := &mapping{
+		Name:     ss.Name + "To" + dstObj.Name(),
+		Src:      srcNamed,
+		Dst:      dstNamed,
+		Ignore:   make(map[string]bool),
+		Position: anno.Position,
+	}
+	if arg := anno.Arg("name"); arg != nil {
+		mp.Name = arg.Value
+	}
+	if arg := anno.Arg("ignore"); arg != nil {
+		values := arg.Values
+		if values == nil {
+			values = []string{arg.Value}
+		}
+		for _, name := range values {
+			mp.Ignore[name] = true
+		}
+	}
+	if !token.IsIdentifier(mp.Name) {
+		m.ctx.Errorf(anno.Position, "%s: invalid mapper name %q", ss.Name, mp.Name)
+		return
+	}
+	if existing := m.lookup(srcNamed, dstNamed); existing != nil {
+		m.ctx.Errorf(anno.Position, "%s: %s already maps to %s", ss.Name, existing.Name, anno.Value())
+		return
+	}
+	if m.declared[mp.Name] || m.ctx.Package.Types.Scope().Lookup(mp.Name) != nil {
+		m.ctx.Errorf(anno.Position, "%s: the generated %s is already declared", ss.Name, mp.Name)
+		return
+	}
+	m.declared[mp.Name] = true
+	m.mappings = append(m.mappings, mp)
+}
+
+func (m *mapper) lookup(src, dst *types.Named) *mapping {
+	for _, mp := range m.mappings {
+		if types.Identical(mp.Src, src) && types.Identical(mp.Dst, dst) {
+			return mp
+		}
+	}
+
+	return nil
+}
+
+// nested returns the mapper of a nested pair of structs, adding an unexported one if there's none.
+func (m *mapper) nested(parent *mapping, src, dst *types.Named) *mapping {
+	if mp := m.lookup(src, dst); mp != nil {
+		return mp
+	}
+
+	name := unexported(src.Obj().Name()) + "To" + dst.Obj().Name()
+	for i := 2; m.declared[name] || m.ctx.Package.Types.Scope().Lookup(name) != nil; i++ {
+		name = unexported(src.Obj().Name()) + "To" + dst.Obj().Name() + strconv.Itoa(i)
+	}
+	mp := &mapping{Name: name, Src: src, Dst: dst, Ignore: make(map[string]bool), Position: parent.Position}
+	m.declared[name] = true
+	m.mappings = append(m.mappings, mp)
+
+	return mp
+}
+
+func (m *mapper) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return m.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+// render writes the mapper func, reporting the fields it can't map.
+func (m *mapper) render(mp *mapping) {
+	srcName, dstName := mp.Src.Obj().Name(), mp.Dst.Obj().Name()
+	srcFields, dstFields := m.fields(mp.Src), m.fields(mp.Dst)
+
+	b := &body{mapper: m, parent: mp}
+	mapped := make(map[string]bool)
+	for _, sf := range srcFields {
+		target := sf.Name()
+		if tag, ok := reflect.StructTag(sf.tag).Lookup("map"); ok {
+			if tag == "-" {
+				continue
+			}
+			target = tag
+		}
+		df := find(dstFields, target)
+		if df == nil {
+			m.ctx.Errorf(mp.Position, "%s: the field %s.%s has no field %s in %s", mp.Name, srcName, sf.Name(), target, dstName)
+			continue
+		}
+		if mapped[target] {
+			m.ctx.Errorf(mp.Position, "%s: the field %s.%s is mapped twice", mp.Name, dstName, target)
+			continue
+		}
+		mapped[target] = true
+		if err := b.assign("dst."+target, "src."+sf.Name(), df.Type(), sf.Type(), 0); err != nil {
+			m.ctx.Errorf(mp.Position, "%s: can't map %s.%s to %s.%s: %v", mp.Name, srcName, sf.Name(), dstName, target, err)
+		}
+	}
+	for _, df := range dstFields {
+		if !mapped[df.Name()] && !mp.Ignore[df.Name()] {
+			m.ctx.Errorf(mp.Position, "%s: the field %s.%s is not mapped from %s", mp.Name, dstName, df.Name(), srcName)
+		}
+	}
+
+	src, dst := m.typeName(mp.Src), m.typeName(mp.Dst)
+	if token.IsExported(mp.Name) {
+		m.file.Printf("// %s maps %s to %s.\n", mp.Name, src, dst)
+	}
+	m.file.Printf("func %s(src *%s) *%s {\nif src == nil {\nreturn nil\n}\n\n", mp.Name, src, dst)
+	m.file.Printf("dst := &%s{}\n%s\nreturn dst\n}\n\n", dst, b.String())
+}
+
+type field struct {
+	*types.Var
+	tag string
+}
+
+// fields returns the fields of the struct this package can access.
+func (m *mapper) fields(named *types.Named) []*field {
+	st := named.Underlying().(*types.Struct)
+	fields := make([]*field, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if v.Name() == "_" || !v.Exported() && v.Pkg() != m.ctx.Package.Types {
+			continue
+		}
+		fields = append(fields, &field{Var: v, tag: st.Tag(i)})
+	}
+
+	return fields
+}
+
+func find(fields []*field, name string) *field {
+	for _, f := range fields {
+		if f.Name() == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// body renders the statements of a mapper func.
+type body struct {
+	strings.Builder
+	mapper *mapper
+	parent *mapping
+	temps  int
+}
+
+func (b *body) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&b.Builder, format, args...)
+}
+
+func (b *body) temp() string {
+	b.temps++
+
+	return "v" + strconv.Itoa(b.temps)
+}
+
+// assign renders `dst = src`, converting the src of type st to dt. depth names the loop indexes of nested slices.
+func (b *body) assign(dst, src string, dt, st types.Type, depth int) error {
+	if types.AssignableTo(st, dt) {
+		b.printf("%s = %s\n", dst, src)
+		return nil
+	}
+
+	dp, dstPtr := dt.(*types.Pointer)
+	sp, srcPtr := st.(*types.Pointer)
+	switch {
+	case dstPtr && srcPtr:
+		if mp := b.structs(dp.Elem(), sp.Elem()); mp != nil {
+			b.printf("%s = %s(%s)\n", dst, mp.Name, src)
+			return nil
+		}
+		v := b.temp()
+		b.printf("if %s != nil {\n%s := new(%s)\n", src, v, b.mapper.typeName(dp.Elem()))
+		if err := b.assign("*"+v, "*"+src, dp.Elem(), sp.Elem(), depth); err != nil {
+			return err
+		}
+		b.printf("%s = %s\n}\n", dst, v)
+		return nil
+	case srcPtr:
+		if mp := b.structs(dt, sp.Elem()); mp != nil {
+			b.printf("if %s != nil {\n%s = *%s(%s)\n}\n", src, dst, mp.Name, src)
+			return nil
+		}
+		b.printf("if %s != nil {\n", src)
+		if err := b.assign(dst, "*"+src, dt, sp.Elem(), depth); err != nil {
+			return err
+		}
+		b.printf("}\n")
+		return nil
+	case dstPtr:
+		if mp := b.structs(dp.Elem(), st); mp != nil {
+			b.printf("%s = %s(&%s)\n", dst, mp.Name, src)
+			return nil
+		}
+		v := b.temp()
+		b.printf("%s := new(%s)\n", v, b.mapper.typeName(dp.Elem()))
+		if err := b.assign("*"+v, src, dp.Elem(), st, depth); err != nil {
+			return err
+		}
+		b.printf("%s = %s\n", dst, v)
+		return nil
+	}
+
+	if mp := b.structs(dt, st); mp != nil {
+		b.printf("%s = *%s(&%s)\n", dst, mp.Name, src)
+		return nil
+	}
+	ds, dstSlice := dt.Underlying().(*types.Slice)
+	ss, srcSlice := st.Underlying().(*types.Slice)
+	if dstSlice && srcSlice {
+		i := index(depth)
+		b.printf("if %s != nil {\n%s = make(%s, len(%s))\nfor %s := range %s {\n", src, dst, b.mapper.typeName(dt), src, i, src)
+		if err := b.assign(dst+"["+i+"]", src+"["+i+"]", ds.Elem(), ss.Elem(), depth+1); err != nil {
+			return err
+		}
+		b.printf("}\n}\n")
+		return nil
+	}
+	if convertible(dt, st) {
+		b.printf("%s = %s(%s)\n", dst, b.mapper.typeName(dt), src)
+		return nil
+	}
+	if lossy(dt, st) {
+		return fmt.Errorf("converting %s to %s may lose the value", st, dt)
+	}
+
+	return fmt.Errorf("no conversion from %s to %s", st, dt)
+}
+
+// structs returns the mapper of two distinct named structs.
+func (b *body) structs(dt, st types.Type) *mapping {
+	dst, src := structNamed(dt), structNamed(st)
+	if dst == nil || src == nil || types.Identical(dst, src) {
+		return nil
+	}
+
+	return b.mapper.nested(b.parent, src, dst)
+}
+
+// convertible reports whether a `T(x)` conversion keeps the value: same underlying types, strings,
+// or numbers the destination holds them all, e.g. int16 to int32 or an integer to a float.
+func convertible(dt, st types.Type) bool {
+	if types.Identical(dt.Underlying(), st.Underlying()) {
+		return true
+	}
+	db, sb := basic(dt), basic(st)
+	if db == nil || sb == nil {
+		return false
+	}
+	if db.Info()&types.IsString != 0 && sb.Info()&types.IsString != 0 {
+		return true
+	}
+	if !numeric(db) || !numeric(sb) || lossy(dt, st) {
+		return false
+	}
+
+	// complex numbers only convert to complex numbers
+	return db.Info()&types.IsComplex == sb.Info()&types.IsComplex
+}
+
+// lossy reports whether converting the numbers of st to dt truncates or narrows some of them:
+// a float to an integer, a wider to a narrower type, or a signed integer to an unsigned one.
+func lossy(dt, st types.Type) bool {
+	db, sb := basic(dt), basic(st)
+	if db == nil || sb == nil || !numeric(db) || !numeric(sb) || db.Info()&types.IsComplex != sb.Info()&types.IsComplex {
+		return false
+	}
+
+	d, s := db.Info(), sb.Info()
+	switch {
+	case d&(types.IsFloat|types.IsComplex) != 0:
+		return s&(types.IsFloat|types.IsComplex) != 0 && bits(db) < bits(sb)
+	case s&types.IsFloat != 0:
+		return true
+	case s&types.IsUnsigned != 0 && d&types.IsUnsigned == 0:
+		return bits(db) <= bits(sb)
+	case s&types.IsUnsigned == 0 && d&types.IsUnsigned != 0:
+		return true
+	}
+
+	return bits(db) < bits(sb)
+}
+
+func basic(t types.Type) *types.Basic {
+	b, _ := t.Underlying().(*types.Basic)
+
+	return b
+}
+
+func numeric(b *types.Basic) bool {
+	return b.Info()&types.IsNumeric != 0
+}
+
+// bits is the size of a number type, int, uint and uintptr taken as 64 bits.
+func bits(b *types.Basic) int {
+	switch b.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Complex128:
+		return 128
+	}
+
+	return 64
+}
+
+func structNamed(t types.Type) *types.Named {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+
+	return named
+}
+
+func index(depth int) string {
+	if depth < 4 {
+		return string("ijkl"[depth])
+	}
+
+	return "i" + strconv.Itoa(depth)
+}
+
+func unexported(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapper
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/photowey/parsergo/gen/gentest"
+)
+
+func TestGenerator(t *testing.T) {
+	gentest.Run(t, New,
+		gentest.Case{
+			Name:      "mapperx",
+			Pkg:       "github.com/photowey/parsergo/tests/mapperx",
+			WantFiles: []string{"mapperx/zz_generated.mapper.go"},
+		},
+		gentest.Case{
+			Name: "invalid",
+			Pkg:  "github.com/photowey/parsergo/tests/mapperx/invalidx",
+			WantDiags: []string{
+				`Source: unknown struct "Missing" to map to`,
+				"SourceToTarget: the field Source.Extra has no field Extra in Target",
+				"SourceToTarget: can't map Source.Count to Target.Count: no conversion from string to int",
+				"SourceToTarget: can't map Source.Ratio to Target.Ratio: converting float64 to int may lose the value",
+				"SourceToTarget: can't map Source.Size to Target.Size: converting int64 to int32 may lose the value",
+				"SourceToTarget: can't map Source.Signal to Target.Signal: no conversion from complex128 to float64",
+				"SourceToTarget: the field Target.Comment is not mapped from Source",
+			},
+		},
+	)
+}
+
+func TestConvertible(t *testing.T) {
+	tests := []struct {
+		dst, src  types.BasicKind
+		want      bool
+		wantLossy bool
+	}{
+		{dst: types.Int32, src: types.Int16, want: true},
+		{dst: types.Int32, src: types.Int64, wantLossy: true},
+		{dst: types.Int64, src: types.Uint32, want: true},
+		{dst: types.Int64, src: types.Uint64, wantLossy: true},
+		{dst: types.Uint64, src: types.Int8, wantLossy: true},
+		{dst: types.Float64, src: types.Int, want: true},
+		{dst: types.Int, src: types.Float64, wantLossy: true},
+		{dst: types.Float32, src: types.Float64, wantLossy: true},
+		{dst: types.Complex128, src: types.Complex64, want: true},
+		{dst: types.Float64, src: types.Complex128},
+		{dst: types.Complex128, src: types.Int},
+		{dst: types.String, src: types.Int},
+	}
+	for _, tt := range tests {
+		dt, st := types.Typ[tt.dst], types.Typ[tt.src]
+		t.Run(st.Name()+" to "+dt.Name(), func(t *testing.T) {
+			if got := convertible(dt, st); got != tt.want {
+				t.Errorf("convertible() = %v, want %v", got, tt.want)
+			}
+			if got := lossy(dt, st); got != tt.wantLossy {
+				t.Errorf("lossy() = %v, want %v", got, tt.wantLossy)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const MockAnnotation = "Mock"
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    MockAnnotation,
+		Targets: []parser.Target{parser.TargetInterface},
+		Help:    "generates a mock of the interface, `Mock<Name>` unless named: `@Mock(\"FakeStore\")`",
+	})
+}
+
+// Generator emits a mock struct per @Mock interface. Each method records its calls in `<Method>Calls`,
+// then calls `<Method>Func` if set or returns `<Method>Returns`.
+// A @Mock struct, e.g. a hand-written double, is left alone.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "mock"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	file := ctx.NewFile()
+	w := &writer{file: file}
+	for _, is := range ctx.Spec.Package.Interfaces {
+		anno := annotation(is.Annotations)
+		if anno == nil {
+			continue
+		}
+
+		ctx.Package.NeedTypesInfo()
+		tn, ok := ctx.Package.Types.Scope().Lookup(is.Name).(*types.TypeName)
+		if !ok {
+			ctx.Errorf(is.Position, "%s: missing type information", is.Name)
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || !types.IsInterface(named) {
+			ctx.Errorf(anno.Position, "%s: @%s needs an interface", is.Name, MockAnnotation)
+			continue
+		}
+		iface := named.Underlying().(*types.Interface)
+		if !iface.IsMethodSet() {
+			ctx.Errorf(anno.Position, "%s: a constraint interface can't be mocked", is.Name)
+			continue
+		}
+
+		methods := methodsOf(is, iface)
+		failed := false
+		for _, method := range methods {
+			if !method.Exported() && method.Pkg() != nil && method.Pkg().Path() != ctx.Package.PkgPath {
+				ctx.Errorf(is.Position, "%s: the unexported method %s of %s can't be implemented here", is.Name, method.Name(), method.Pkg().Path())
+				failed = true
+			}
+		}
+		if failed {
+			continue
+		}
+
+		name := anno.Value()
+		if name == "" {
+			name = "Mock" + is.Name
+		}
+		w.mock(name, named, methods)
+	}
+	if w.mocks == 0 {
+		return nil
+	}
+
+	return ctx.AddFile(file)
+}
+
+// methodsOf lists the methods declared by the interface in order, then those of its embedded interfaces by name.
+func methodsOf(is *astx.InterfaceSpec, iface *types.Interface) []*types.Func {
+	order := make(map[string]int, len(is.Methods))
+	for i, ms := range is.Methods {
+		order[ms.Name] = i
+	}
+
+	methods := make([]*types.Func, 0, iface.NumMethods())
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods = append(methods, iface.Method(i))
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		oi, declaredI := order[methods[i].Name()]
+		oj, declaredJ := order[methods[j].Name()]
+		if declaredI != declaredJ {
+			return declaredI
+		}
+		if declaredI {
+			return oi < oj
+		}
+		return methods[i].Name() < methods[j].Name()
+	})
+
+	return methods
+}
+
+type writer struct {
+	file  *gen.File
+	mocks int
+}
+
+func (w *writer) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return w.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+func (w *writer) mock(name string, iface *types.Named, methods []*types.Func) {
+	w.mocks++
+	params, args := "", ""
+	if tparams := iface.TypeParams(); tparams.Len() > 0 {
+		decls := make([]string, 0, tparams.Len())
+		names := make([]string, 0, tparams.Len())
+		for i := 0; i < tparams.Len(); i++ {
+			tp := tparams.At(i)
+			decls = append(decls, tp.Obj().Name()+" "+w.typeName(tp.Constraint()))
+			names = append(names, tp.Obj().Name())
+		}
+		params = "[" + strings.Join(decls, ", ") + "]"
+		args = "[" + strings.Join(names, ", ") + "]"
+	}
+	ifaceName := iface.Obj().Name()
+	syncPkg := w.file.Imports.NeedImport("sync")
+
+	w.file.Printf("// %s is a mock of %s.\n", name, ifaceName)
+	w.file.Printf("// A method records its call, then calls its Func field if set, otherwise returns its Returns field.\n")
+	w.file.Printf("type %s%s struct {\nmu %s.Mutex\n\n", name, params, syncPkg)
+	for _, method := range methods {
+		sig := method.Type().(*types.Signature)
+		w.file.Printf("%sFunc func%s\n", method.Name(), w.signature(sig))
+		if sig.Results().Len() > 0 {
+			w.file.Printf("%sReturns %s\n", method.Name(), w.resultsStruct(sig))
+		}
+		w.file.Printf("%sCalls []%s\n", method.Name(), w.callStruct(sig))
+	}
+	w.file.Printf("}\n\n")
+
+	if params == "" {
+		w.file.Printf("var _ %s = (*%s)(nil)\n\n", ifaceName, name)
+	} else {
+		w.file.Printf("func _%s() {\nvar _ %s%s = (*%s%s)(nil)\n}\n\n", params, ifaceName, args, name, args)
+	}
+
+	for _, method := range methods {
+		w.method(name+args, method)
+	}
+}
+
+func (w *writer) method(recv string, method *types.Func) {
+	sig := method.Type().(*types.Signature)
+	names := paramNames(sig)
+	w.file.Printf("func (m *%s) %s%s {\n", recv, method.Name(), w.signature(sig))
+	w.file.Printf("m.mu.Lock()\n")
+
+	fields := make([]string, 0, len(names))
+	for _, param := range names {
+		fields = append(fields, exported(param)+": "+param)
+	}
+	w.file.Printf("m.%sCalls = append(m.%sCalls, %s{%s})\n", method.Name(), method.Name(), w.callStruct(sig), strings.Join(fields, ", "))
+	w.file.Printf("fn := m.%sFunc\n", method.Name())
+	if sig.Results().Len() > 0 {
+		w.file.Printf("returns := m.%sReturns\n", method.Name())
+	}
+	w.file.Printf("m.mu.Unlock()\n\n")
+
+	call := "fn(" + strings.Join(names, ", ")
+	if sig.Variadic() {
+		call += "..."
+	}
+	call += ")"
+	if sig.Results().Len() == 0 {
+		w.file.Printf("if fn != nil {\n%s\n}\n}\n\n", call)
+		return
+	}
+
+	results := make([]string, 0, sig.Results().Len())
+	for _, field := range resultNames(sig) {
+		results = append(results, "returns."+field)
+	}
+	w.file.Printf("if fn != nil {\nreturn %s\n}\n\nreturn %s\n}\n\n", call, strings.Join(results, ", "))
+}
+
+// signature renders the params and results of sig with the param names of paramNames.
+func (w *writer) signature(sig *types.Signature) string {
+	names := paramNames(sig)
+	params := make([]string, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		t := w.typeName(sig.Params().At(i).Type())
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			t = "..." + strings.TrimPrefix(t, "[]")
+		}
+		params = append(params, names[i]+" "+t)
+	}
+
+	results := make([]string, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, w.typeName(sig.Results().At(i).Type()))
+	}
+	switch len(results) {
+	case 0:
+		return "(" + strings.Join(params, ", ") + ")"
+	case 1:
+		return "(" + strings.Join(params, ", ") + ") " + results[0]
+	}
+
+	return "(" + strings.Join(params, ", ") + ") (" + strings.Join(results, ", ") + ")"
+}
+
+func (w *writer) callStruct(sig *types.Signature) string {
+	names := paramNames(sig)
+	fields := make([]string, 0, len(names))
+	for i, param := range names {
+		fields = append(fields, exported(param)+" "+w.typeName(sig.Params().At(i).Type()))
+	}
+	if len(fields) == 0 {
+		return "struct{}"
+	}
+
+	return "struct {\n" + strings.Join(fields, "\n") + "\n}"
+}
+
+func (w *writer) resultsStruct(sig *types.Signature) string {
+	names := resultNames(sig)
+	fields := make([]string, 0, len(names))
+	for i, field := range names {
+		fields = append(fields, field+" "+w.typeName(sig.Results().At(i).Type()))
+	}
+
+	return "struct {\n" + strings.Join(fields, "\n") + "\n}"
+}
+
+// paramNames names the params, the unnamed ones and those shadowing the locals of a mock method `p<i>`.
+func paramNames(sig *types.Signature) []string {
+	names := make([]string, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		switch name := sig.Params().At(i).Name(); name {
+		case "", "_", "m", "fn", "returns":
+			names = append(names, "p"+strconv.Itoa(i))
+		default:
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// resultNames names the fields of the Returns struct, `R<i>` for the unnamed results.
+func resultNames(sig *types.Signature) []string {
+	names := make([]string, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		name := sig.Results().At(i).Name()
+		if name == "" || name == "_" {
+			name = "r" + strconv.Itoa(i)
+		}
+		names = append(names, exported(name))
+	}
+
+	return names
+}
+
+func exported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+func annotation(annotations []*astx.Annotation) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == MockAnnotation && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"github.com/photowey/parsergo/gen/schema"
+)
+
+// Document is an OpenAPI 3.0 document, its fields in the order they are written.
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components,omitempty"`
+}
+
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type PathItem struct {
+	Get     *Operation `yaml:"get,omitempty"`
+	Put     *Operation `yaml:"put,omitempty"`
+	Post    *Operation `yaml:"post,omitempty"`
+	Delete  *Operation `yaml:"delete,omitempty"`
+	Options *Operation `yaml:"options,omitempty"`
+	Head    *Operation `yaml:"head,omitempty"`
+	Patch   *Operation `yaml:"patch,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Tags        []string             `yaml:"tags,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `yaml:"required,omitempty"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `yaml:"$ref,omitempty"`
+	AllOf                []*Schema          `yaml:"allOf,omitempty"`
+	Type                 string             `yaml:"type,omitempty"`
+	Format               string             `yaml:"format,omitempty"`
+	Nullable             bool               `yaml:"nullable,omitempty"`
+	Description          string             `yaml:"description,omitempty"`
+	Enum                 []interface{}      `yaml:"enum,omitempty"`
+	Items                *Schema            `yaml:"items,omitempty"`
+	Properties           map[string]*Schema `yaml:"properties,omitempty"`
+	Required             []string           `yaml:"required,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty"`
+	Minimum              *float64           `yaml:"minimum,omitempty"`
+	ExclusiveMinimum     bool               `yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `yaml:"maximum,omitempty"`
+	ExclusiveMaximum     bool               `yaml:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `yaml:"minLength,omitempty"`
+	MaxLength            *int               `yaml:"maxLength,omitempty"`
+	MinItems             *int               `yaml:"minItems,omitempty"`
+	MaxItems             *int               `yaml:"maxItems,omitempty"`
+	Pattern              string             `yaml:"pattern,omitempty"`
+}
+
+// schemaOf converts a neutral schema, its refs naming definitions by id until resolve renames them.
+// OpenAPI 3.0 ignores the siblings of `$ref`, a nullable or described ref is wrapped in `allOf`.
+func schemaOf(s *schema.Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		ref := &Schema{Ref: s.Ref}
+		if !s.Nullable && s.Description == "" {
+			return ref
+		}
+		return &Schema{AllOf: []*Schema{ref}, Nullable: s.Nullable, Description: s.Description}
+	}
+
+	out := &Schema{
+		Type:                 s.Type,
+		Format:               s.Format,
+		Nullable:             s.Nullable,
+		Description:          s.Description,
+		Enum:                 s.Enum,
+		Items:                schemaOf(s.Items),
+		Required:             s.Required,
+		AdditionalProperties: schemaOf(s.AdditionalProperties),
+		Minimum:              s.Minimum,
+		ExclusiveMinimum:     s.ExclusiveMinimum,
+		Maximum:              s.Maximum,
+		ExclusiveMaximum:     s.ExclusiveMaximum,
+		MinLength:            s.MinLength,
+		MaxLength:            s.MaxLength,
+		MinItems:             s.MinItems,
+		MaxItems:             s.MaxItems,
+		Pattern:              s.Pattern,
+	}
+	if s.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			out.Properties[name] = schemaOf(property)
+		}
+	}
+
+	return out
+}
+
+// resolve points the refs of s to the component schemas.
+func (s *Schema) resolve(names map[string]string) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		s.Ref = "#/components/schemas/" + names[s.Ref]
+	}
+	for _, sub := range s.AllOf {
+		sub.resolve(names)
+	}
+	s.Items.resolve(names)
+	s.AdditionalProperties.resolve(names)
+	for _, property := range s.Properties {
+		property.resolve(names)
+	}
+}
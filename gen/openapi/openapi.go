@@ -0,0 +1,341 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"bytes"
+	"go/types"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/gen/routes"
+	"github.com/photowey/parsergo/gen/schema"
+	"github.com/photowey/parsergo/parser"
+	"github.com/photowey/parsergo/pkg/validatex"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Version           = "3.0.3"
+	OpenAPIAnnotation = "OpenAPI"
+)
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    OpenAPIAnnotation,
+		Targets: []parser.Target{parser.TargetPackage},
+		Help:    "the info of the OpenAPI document: `@OpenAPI(title=\"Users\", version=\"1.0.0\")`",
+	})
+}
+
+// Generator writes a single `openapi.yaml` describing the routes of every @Controller struct,
+// the structs they read and write becoming component schemas.
+type Generator struct {
+	Title   string
+	Version string
+	// Output is the path of the document, `openapi.yaml` in the directory of the first controller package by default.
+	Output string
+
+	builder *schema.Builder
+	doc     *Document
+}
+
+func New() *Generator {
+	return &Generator{
+		builder: schema.NewBuilder(),
+		doc: &Document{
+			OpenAPI: Version,
+			Paths:   make(map[string]*PathItem),
+		},
+	}
+}
+
+func (g *Generator) Name() string {
+	return "openapi"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	collected := routes.Collect(ctx)
+	if len(collected) == 0 {
+		return nil
+	}
+
+	if g.Output == "" {
+		g.Output = filepath.Join(ctx.Dir(), "openapi.yaml")
+	}
+	for _, anno := range ctx.Spec.Package.Annotations {
+		if anno.Name != OpenAPIAnnotation || anno.Marker {
+			continue
+		}
+		for _, arg := range anno.Args {
+			switch {
+			case arg.Name == "title" && g.Title == "":
+				g.Title = arg.Value
+			case arg.Name == "version" && g.Version == "":
+				g.Version = arg.Value
+			}
+		}
+	}
+	if g.Title == "" {
+		g.Title = ctx.Package.Name
+	}
+	g.builder.AddPackage(ctx.Package, ctx.Spec)
+
+	for _, r := range collected {
+		path := pathOf(r.Path)
+		item := g.doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			g.doc.Paths[path] = item
+		}
+		slot := item.slot(r.Verb)
+		if *slot != nil {
+			ctx.Errorf(r.Method.Position, "%s.%s: operation %s %s is already defined by %s",
+				r.Controller.Name, r.Method.Name, r.Verb, path, (*slot).OperationID)
+			continue
+		}
+		*slot = g.operation(r)
+	}
+
+	return nil
+}
+
+func (g *Generator) Finish(result *gen.Result) error {
+	if len(g.doc.Paths) == 0 {
+		return nil
+	}
+
+	g.doc.Info = Info{Title: g.Title, Version: g.Version}
+	if g.doc.Info.Version == "" {
+		g.doc.Info.Version = "1.0.0"
+	}
+	names := g.builder.Names()
+	g.doc.Components.Schemas = make(map[string]*Schema, len(g.builder.Definitions))
+	for _, def := range g.builder.Sorted() {
+		s := schemaOf(def.Schema)
+		s.resolve(names)
+		g.doc.Components.Schemas[names[def.ID]] = s
+	}
+	for _, item := range g.doc.Paths {
+		for _, op := range item.operations() {
+			op.resolve(names)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(g.doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	result.Files[g.Output] = buf.Bytes()
+
+	return nil
+}
+
+func (g *Generator) operation(r *routes.Route) *Operation {
+	op := &Operation{
+		OperationID: r.Controller.Name + "." + r.Method.Name,
+		Tags:        []string{r.Controller.Name},
+		Responses:   make(map[string]*Response),
+	}
+	if doc := r.Method.Doc; doc != nil {
+		op.Summary = doc.Summary
+		op.Description = strings.Join(doc.Body, "\n\n")
+	}
+
+	parsed := false
+	for _, b := range r.Bindings {
+		switch b.Source {
+		case routes.SourcePath, routes.SourceQuery, routes.SourceHeader:
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     b.Name,
+				In:       in(b.Source),
+				Required: b.Source == routes.SourcePath,
+				Schema:   schemaOf(g.builder.Schema(b.Param.Type())),
+			})
+		case routes.SourceTagged:
+			st := b.Param.Type()
+			if ptr, ok := st.(*types.Pointer); ok {
+				st = ptr.Elem()
+			}
+			for _, fb := range b.Fields {
+				s := g.builder.Schema(fb.Field.Type())
+				rules := validatex.Parse(fieldTag(st.Underlying().(*types.Struct), fb.Field).Get("validate"))
+				schema.Constrain(s, rules)
+				op.Parameters = append(op.Parameters, &Parameter{
+					Name:     fb.Name,
+					In:       in(fb.Source),
+					Required: fb.Source == routes.SourcePath || validatex.Find(rules, "required") != nil,
+					Schema:   schemaOf(s),
+				})
+			}
+			if b.Body {
+				op.RequestBody = g.requestBody(b.Param.Type())
+			}
+		case routes.SourceBody:
+			op.RequestBody = g.requestBody(b.Param.Type())
+		default:
+			continue
+		}
+		parsed = true
+	}
+
+	status := strconv.Itoa(r.Status)
+	response := &Response{Description: http.StatusText(r.Status)}
+	if response.Description == "" {
+		response.Description = status
+	}
+	results := r.Func.Type().(*types.Signature).Results()
+	for i := 0; i < results.Len(); i++ {
+		if t := results.At(i).Type(); !isError(t) {
+			response.Content = jsonContent(g.builder.Schema(deref(t)))
+			continue
+		}
+		op.Responses["default"] = &Response{
+			Description: "The error, its status given by a `StatusCode() int` method or 500.",
+			Content:     textContent(),
+		}
+	}
+	op.Responses[status] = response
+	if parsed {
+		op.Responses[strconv.Itoa(http.StatusBadRequest)] = &Response{
+			Description: http.StatusText(http.StatusBadRequest),
+			Content:     textContent(),
+		}
+	}
+
+	return op
+}
+
+func (g *Generator) requestBody(t types.Type) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  jsonContent(g.builder.Schema(deref(t))),
+	}
+}
+
+func (item *PathItem) slot(verb string) **Operation {
+	switch verb {
+	case http.MethodPut:
+		return &item.Put
+	case http.MethodPost:
+		return &item.Post
+	case http.MethodDelete:
+		return &item.Delete
+	case http.MethodOptions:
+		return &item.Options
+	case http.MethodHead:
+		return &item.Head
+	case http.MethodPatch:
+		return &item.Patch
+	}
+
+	return &item.Get
+}
+
+func (item *PathItem) operations() []*Operation {
+	ops := make([]*Operation, 0)
+	for _, op := range []*Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+
+	return ops
+}
+
+func (op *Operation) resolve(names map[string]string) {
+	for _, param := range op.Parameters {
+		param.Schema.resolve(names)
+	}
+	if op.RequestBody != nil {
+		for _, media := range op.RequestBody.Content {
+			media.Schema.resolve(names)
+		}
+	}
+	for _, response := range op.Responses {
+		for _, media := range response.Content {
+			media.Schema.resolve(names)
+		}
+	}
+}
+
+// pathOf turns a `net/http` pattern path into an OpenAPI path template.
+func pathOf(path string) string {
+	path = strings.TrimSuffix(path, "{$}")
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}") {
+			segments[i] = strings.TrimSuffix(segment, "...}") + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func in(src routes.Source) string {
+	switch src {
+	case routes.SourcePath:
+		return "path"
+	case routes.SourceHeader:
+		return "header"
+	}
+
+	return "query"
+}
+
+func jsonContent(s *schema.Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: schemaOf(s)},
+	}
+}
+
+func textContent() map[string]*MediaType {
+	return map[string]*MediaType{
+		"text/plain": {Schema: &Schema{Type: "string"}},
+	}
+}
+
+func fieldTag(st *types.Struct, field *types.Var) reflect.StructTag {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i) == field {
+			return reflect.StructTag(st.Tag(i))
+		}
+	}
+
+	return ""
+}
+
+func deref(t types.Type) types.Type {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+
+	return t
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
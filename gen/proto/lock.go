@@ -0,0 +1,149 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// LockFile keeps the field numbers assigned to the fields without @ProtoField, so they never change.
+const LockFile = "proto.lock"
+
+// reservedNumbers are the field numbers reserved for the protobuf implementation.
+const (
+	firstReserved = 19000
+	lastReserved  = 19999
+)
+
+// Lock maps the message names to the numbers of their fields, by proto field name.
+// The fields removed from a message stay locked, their numbers and names reserved.
+type Lock struct {
+	Messages map[string]map[string]int `json:"messages"`
+}
+
+func ReadLock(path string) (*Lock, error) {
+	lock := &Lock{Messages: make(map[string]map[string]int)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if lock.Messages == nil {
+		lock.Messages = make(map[string]map[string]int)
+	}
+
+	return lock, nil
+}
+
+func (l *Lock) Bytes() ([]byte, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+// assign numbers the fields of a message: an explicit number, then the locked one, then the next free one.
+// An explicit number can't take the locked number of another field, which would silently renumber it.
+// The numbers and names of the locked fields no longer in the message are reserved.
+func (l *Lock) assign(msg *message) error {
+	locked := l.Messages[msg.name]
+	if locked == nil {
+		locked = make(map[string]int)
+		l.Messages[msg.name] = locked
+	}
+
+	present := make(map[string]bool, len(msg.fields))
+	lockedBy := make(map[int]string)
+	used := make(map[int]string)
+	highest := 0
+	for _, f := range msg.fields {
+		present[f.name] = true
+		if number, ok := locked[f.name]; ok && f.number == 0 {
+			lockedBy[number] = f.name
+		}
+	}
+	for name, number := range locked {
+		if !present[name] {
+			used[number] = name
+		}
+		if number > highest {
+			highest = number
+		}
+	}
+
+	for _, f := range msg.fields {
+		if f.number == 0 {
+			continue
+		}
+		if f.number < 1 || f.number > 1<<29-1 || f.number >= firstReserved && f.number <= lastReserved {
+			return fmt.Errorf("%s.%s: invalid field number %d", msg.name, f.name, f.number)
+		}
+		if other, ok := used[f.number]; ok {
+			return fmt.Errorf("%s.%s: field number %d is already used by %s", msg.name, f.name, f.number, other)
+		}
+		if other, ok := lockedBy[f.number]; ok && other != f.name {
+			return fmt.Errorf("%s.%s: field number %d is locked by %s", msg.name, f.name, f.number, other)
+		}
+		used[f.number] = f.name
+		if f.number > highest {
+			highest = f.number
+		}
+	}
+	for _, f := range msg.fields {
+		if f.number != 0 {
+			continue
+		}
+		if number, ok := locked[f.name]; ok && used[number] == "" {
+			f.number = number
+		} else {
+			highest++
+			if highest == firstReserved {
+				highest = lastReserved + 1
+			}
+			f.number = highest
+		}
+		used[f.number] = f.name
+	}
+
+	for _, f := range msg.fields {
+		locked[f.name] = f.number
+	}
+	msg.reserved = make([]int, 0)
+	msg.reservedNames = make([]string, 0)
+	for name, number := range locked {
+		if !present[name] {
+			msg.reserved = append(msg.reserved, number)
+			msg.reservedNames = append(msg.reservedNames, name)
+		}
+	}
+	sort.Ints(msg.reserved)
+	sort.Strings(msg.reservedNames)
+
+	return nil
+}
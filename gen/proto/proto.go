@@ -0,0 +1,463 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+	"github.com/photowey/parsergo/pkg/stringx"
+)
+
+const (
+	ProtoAnnotation        = "Proto"
+	ProtoFieldAnnotation   = "ProtoField"
+	ProtoPackageAnnotation = "ProtoPackage"
+)
+
+func init() {
+	parser.RegisterMarker(
+		&parser.MarkerDefinition{
+			Name:    ProtoAnnotation,
+			Targets: []parser.Target{parser.TargetStruct, parser.TargetInterface},
+			Help:    "a proto message for a struct, a gRPC service for an interface",
+		},
+		&parser.MarkerDefinition{
+			Name:    ProtoFieldAnnotation,
+			Targets: []parser.Target{parser.TargetField},
+			Help:    "the number of the proto field, which never changes: `@ProtoField(3)`",
+		},
+		&parser.MarkerDefinition{
+			Name:    ProtoPackageAnnotation,
+			Targets: []parser.Target{parser.TargetPackage},
+			Help:    "the proto package, and the go_package option: `@ProtoPackage(\"users.v1\", go_package=\"example.com/users/v1;usersv1\")`",
+		},
+	)
+}
+
+// Generator writes `<package>.proto` with a message per @Proto struct, and per struct or enum they use,
+// and a service per @Proto interface. The numbers of the fields without @ProtoField are kept in LockFile.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "proto"
+}
+
+type file struct {
+	pkg       string
+	goPackage string
+	imports   map[string]bool
+	enums     []*enum
+	messages  []*message
+	services  []*service
+}
+
+type message struct {
+	name          string
+	doc           string
+	fields        []*field
+	reserved      []int
+	reservedNames []string
+}
+
+type field struct {
+	name     string
+	typ      string
+	number   int // 0 until assigned
+	repeated bool
+	optional bool
+	doc      string
+}
+
+type enum struct {
+	name   string
+	doc    string
+	values []*enumValue
+}
+
+type enumValue struct {
+	name   string
+	number int64
+	doc    string
+}
+
+type service struct {
+	name string
+	doc  string
+	rpcs []*rpc
+}
+
+type rpc struct {
+	name     string
+	doc      string
+	request  string
+	response string
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	b := &builder{
+		ctx:      ctx,
+		file:     &file{pkg: ctx.Package.Name, imports: make(map[string]bool)},
+		messages: make(map[string]*message),
+		enums:    make(map[string]*enum),
+		types:    make(map[*types.TypeName]string),
+	}
+	for _, anno := range ctx.Spec.Package.Annotations {
+		if anno.Name == ProtoPackageAnnotation && !anno.Marker {
+			if anno.Value() != "" {
+				b.file.pkg = anno.Value()
+			}
+			if arg := anno.Arg("go_package"); arg != nil {
+				b.file.goPackage = arg.Value
+			}
+		}
+	}
+
+	ctx.Package.NeedTypesInfo()
+	for _, ss := range ctx.Spec.Package.Structs {
+		if anno := annotation(ss.Annotations, ProtoAnnotation); anno != nil {
+			if named := b.lookup(ss.Name, ss.Position); named != nil {
+				b.message(named, ss.Position)
+			}
+		}
+	}
+	for _, is := range ctx.Spec.Package.Interfaces {
+		if anno := annotation(is.Annotations, ProtoAnnotation); anno != nil {
+			if named := b.lookup(is.Name, is.Position); named != nil {
+				b.service(named, is)
+			}
+		}
+	}
+	if len(b.file.messages) == 0 && len(b.file.services) == 0 {
+		return nil
+	}
+
+	lock, err := ReadLock(filepath.Join(ctx.Dir(), LockFile))
+	if err != nil {
+		return err
+	}
+	for _, msg := range b.file.messages {
+		if err := lock.assign(msg); err != nil {
+			ctx.Errorf(b.positions[msg.name], "%s", err)
+		}
+	}
+	content, err := lock.Bytes()
+	if err != nil {
+		return err
+	}
+	ctx.WriteFile(LockFile, content)
+	ctx.WriteFile(ctx.Package.Name+".proto", b.file.bytes())
+
+	return nil
+}
+
+type builder struct {
+	ctx       *gen.Context
+	file      *file
+	messages  map[string]*message
+	enums     map[string]*enum
+	types     map[*types.TypeName]string // the message or enum of a Go type
+	positions map[string]astx.Position
+}
+
+func (b *builder) lookup(name string, position astx.Position) *types.Named {
+	tn, ok := b.ctx.Package.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		b.ctx.Errorf(position, "%s: missing type information", name)
+		return nil
+	}
+	named, ok := tn.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		b.ctx.Errorf(position, "%s: @%s needs a non-generic type", name, ProtoAnnotation)
+		return nil
+	}
+
+	return named
+}
+
+// declare claims a message or enum name, reporting a clash.
+func (b *builder) declare(name string, position astx.Position) bool {
+	if _, ok := b.positions[name]; ok {
+		b.ctx.Errorf(position, "proto: %s is declared twice", name)
+		return false
+	}
+	if b.positions == nil {
+		b.positions = make(map[string]astx.Position)
+	}
+	b.positions[name] = position
+
+	return true
+}
+
+// message declares the message of a struct of the package once, returning its name.
+func (b *builder) message(t *types.Named, position astx.Position) string {
+	obj := t.Obj()
+	if name, ok := b.types[obj]; ok {
+		return name
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		b.ctx.Errorf(position, "%s: a message needs a struct", obj.Name())
+		return obj.Name()
+	}
+	if obj.Pkg() == nil || obj.Pkg().Path() != b.ctx.Package.PkgPath {
+		b.ctx.Errorf(position, "%s is declared in another package, it has no message here", types.TypeString(t, nil))
+		return obj.Name()
+	}
+
+	b.types[obj] = obj.Name()
+	ss := b.structSpec(obj.Name())
+	if ss != nil {
+		position = ss.Position
+	}
+	if !b.declare(obj.Name(), position) {
+		return obj.Name()
+	}
+	msg := &message{name: obj.Name()}
+	if ss != nil {
+		msg.doc = docText(ss.Doc)
+	}
+	b.file.messages = append(b.file.messages, msg)
+	b.fields(msg, st, ss, position)
+
+	return msg.name
+}
+
+func (b *builder) fields(msg *message, st *types.Struct, ss *astx.StructSpec, position astx.Position) {
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		fs := fieldSpec(ss, v.Name())
+		at := position
+		if fs != nil {
+			at = fs.Position
+		}
+		if v.Embedded() {
+			if named, ok := types.Unalias(deref(v.Type())).(*types.Named); ok {
+				if est, ok := named.Underlying().(*types.Struct); ok {
+					b.fields(msg, est, b.structSpec(named.Obj().Name()), at)
+					continue
+				}
+			}
+		}
+		if !v.Exported() {
+			continue
+		}
+
+		f := &field{name: stringx.Snake(v.Name())}
+		if !b.fieldType(f, v.Type(), at) {
+			continue
+		}
+		if fs != nil {
+			f.doc = docText(fs.Doc)
+			if anno := annotation(fs.Annotations, ProtoFieldAnnotation); anno != nil {
+				number, err := strconv.Atoi(anno.Value())
+				if err != nil || number < 1 {
+					b.ctx.Errorf(anno.Position, "%s.%s: invalid field number %q", msg.name, v.Name(), anno.Value())
+					continue
+				}
+				f.number = number
+			}
+		}
+		msg.fields = append(msg.fields, f)
+	}
+}
+
+// service declares the service of an interface, its methods in declaration order.
+func (b *builder) service(t *types.Named, is *astx.InterfaceSpec) {
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		b.ctx.Errorf(is.Position, "%s: a service needs an interface", is.Name)
+		return
+	}
+
+	srv := &service{name: is.Name, doc: docText(is.Doc)}
+	docs := methodDocs(is)
+	methods := make([]*types.Func, 0, iface.NumMethods())
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods = append(methods, iface.Method(i))
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		return methods[i].Pos() < methods[j].Pos()
+	})
+	for _, method := range methods {
+		sig := method.Type().(*types.Signature)
+		call := &rpc{name: method.Name(), doc: docs[method.Name()]}
+		call.request = b.payload(method.Name()+"Request", sig.Params(), is.Position)
+		call.response = b.payload(method.Name()+"Response", sig.Results(), is.Position)
+		srv.rpcs = append(srv.rpcs, call)
+	}
+	b.file.services = append(b.file.services, srv)
+}
+
+// payload returns the message of a single struct, skipping a context and an error,
+// google.protobuf.Empty for nothing, or a message of the given name holding the vars.
+func (b *builder) payload(name string, tuple *types.Tuple, position astx.Position) string {
+	vars := make([]*types.Var, 0, tuple.Len())
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		if isNamed(v.Type(), "context", "Context") || isError(v.Type()) {
+			continue
+		}
+		vars = append(vars, v)
+	}
+
+	switch {
+	case len(vars) == 0:
+		b.file.imports["google/protobuf/empty.proto"] = true
+		return "google.protobuf.Empty"
+	case len(vars) == 1:
+		if named, ok := types.Unalias(deref(vars[0].Type())).(*types.Named); ok {
+			if _, ok := named.Underlying().(*types.Struct); ok && !wellKnown(named) {
+				return b.message(named, position)
+			}
+		}
+	}
+
+	if !b.declare(name, position) {
+		return name
+	}
+	msg := &message{name: name}
+	b.file.messages = append(b.file.messages, msg)
+	for i, v := range vars {
+		f := &field{name: stringx.Snake(v.Name())}
+		if v.Name() == "" || v.Name() == "_" {
+			f.name = "result"
+			if len(vars) > 1 {
+				f.name += strconv.Itoa(i)
+			}
+		}
+		if b.fieldType(f, v.Type(), position) {
+			msg.fields = append(msg.fields, f)
+		}
+	}
+
+	return name
+}
+
+func (b *builder) structSpec(name string) *astx.StructSpec {
+	for _, ss := range b.ctx.Spec.Package.Structs {
+		if ss.Name == name {
+			return ss
+		}
+	}
+
+	return nil
+}
+
+// methodDocs maps the methods declared by an interface to their docs.
+func methodDocs(is *astx.InterfaceSpec) map[string]string {
+	docs := make(map[string]string, len(is.Methods))
+	for _, ms := range is.Methods {
+		docs[ms.Name] = docText(ms.Doc)
+	}
+
+	return docs
+}
+
+func (f *file) bytes() []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by parsergo proto. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", f.pkg)
+
+	if len(f.imports) > 0 {
+		b.WriteString("\n")
+		imports := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+		for _, path := range imports {
+			fmt.Fprintf(&b, "import %q;\n", path)
+		}
+	}
+	if f.goPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", f.goPackage)
+	}
+
+	for _, srv := range f.services {
+		b.WriteString("\n")
+		writeDoc(&b, "", srv.doc)
+		fmt.Fprintf(&b, "service %s {\n", srv.name)
+		for _, call := range srv.rpcs {
+			writeDoc(&b, "  ", call.doc)
+			fmt.Fprintf(&b, "  rpc %s(%s) returns (%s);\n", call.name, call.request, call.response)
+		}
+		b.WriteString("}\n")
+	}
+	for _, msg := range f.messages {
+		b.WriteString("\n")
+		writeDoc(&b, "", msg.doc)
+		fmt.Fprintf(&b, "message %s {\n", msg.name)
+		for _, fd := range msg.fields {
+			writeDoc(&b, "  ", fd.doc)
+			label := ""
+			switch {
+			case fd.repeated:
+				label = "repeated "
+			case fd.optional:
+				label = "optional "
+			}
+			fmt.Fprintf(&b, "  %s%s %s = %d;\n", label, fd.typ, fd.name, fd.number)
+		}
+		if len(msg.reserved) > 0 {
+			numbers := make([]string, 0, len(msg.reserved))
+			for _, number := range msg.reserved {
+				numbers = append(numbers, strconv.Itoa(number))
+			}
+			fmt.Fprintf(&b, "  reserved %s;\n", strings.Join(numbers, ", "))
+			fmt.Fprintf(&b, "  reserved \"%s\";\n", strings.Join(msg.reservedNames, "\", \""))
+		}
+		b.WriteString("}\n")
+	}
+	for _, e := range f.enums {
+		b.WriteString("\n")
+		writeDoc(&b, "", e.doc)
+		fmt.Fprintf(&b, "enum %s {\n", e.name)
+		for _, value := range e.values {
+			writeDoc(&b, "  ", value.doc)
+			fmt.Fprintf(&b, "  %s = %d;\n", value.name, value.number)
+		}
+		b.WriteString("}\n")
+	}
+
+	return []byte(b.String())
+}
+
+func writeDoc(b *strings.Builder, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/photowey/parsergo/gen/gentest"
+)
+
+func TestGenerator(t *testing.T) {
+	gentest.Run(t, New,
+		gentest.Case{
+			Name:      "protox",
+			Pkg:       "github.com/photowey/parsergo/tests/protox",
+			WantFiles: []string{"protox/protox.proto", "protox/" + LockFile},
+		},
+	)
+}
+
+func TestLock_assign(t *testing.T) {
+	tests := []struct {
+		name         string
+		locked       map[string]int
+		fields       map[string]int // the explicit numbers, 0 for none
+		order        []string
+		want         map[string]int
+		wantReserved []int
+		wantErr      string
+	}{
+		{
+			name:  "Test assign in order",
+			order: []string{"id", "name"},
+			want:  map[string]int{"id": 1, "name": 2},
+		},
+		{
+			name:   "Test assign keeps locked numbers",
+			locked: map[string]int{"id": 1, "name": 2},
+			order:  []string{"name", "email", "id"},
+			want:   map[string]int{"id": 1, "name": 2, "email": 3},
+		},
+		{
+			name:         "Test assign reserves removed fields",
+			locked:       map[string]int{"id": 1, "nickname": 2},
+			order:        []string{"id", "name"},
+			want:         map[string]int{"id": 1, "name": 3},
+			wantReserved: []int{2},
+		},
+		{
+			name:   "Test assign explicit number wins",
+			locked: map[string]int{"id": 1},
+			fields: map[string]int{"id": 3},
+			order:  []string{"id", "name"},
+			want:   map[string]int{"id": 3, "name": 4},
+		},
+		{
+			name:    "Test assign explicit number of a locked field",
+			locked:  map[string]int{"id": 1},
+			fields:  map[string]int{"name": 1},
+			order:   []string{"id", "name"},
+			wantErr: "User.name: field number 1 is locked by id",
+		},
+		{
+			name:    "Test assign explicit number of a removed field",
+			locked:  map[string]int{"nickname": 2},
+			fields:  map[string]int{"name": 2},
+			order:   []string{"name"},
+			wantErr: "User.name: field number 2 is already used by nickname",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lock := &Lock{Messages: map[string]map[string]int{}}
+			if tt.locked != nil {
+				lock.Messages["User"] = tt.locked
+			}
+			msg := &message{name: "User"}
+			for _, name := range tt.order {
+				msg.fields = append(msg.fields, &field{name: name, number: tt.fields[name]})
+			}
+
+			err := lock.assign(msg)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("assign() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("assign() error = %v", err)
+			}
+			got := make(map[string]int)
+			for _, f := range msg.fields {
+				got[f.name] = f.number
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("assign() = %v, want %v", got, tt.want)
+			}
+			if len(msg.reserved) != len(tt.wantReserved) || len(tt.wantReserved) > 0 && !reflect.DeepEqual(msg.reserved, tt.wantReserved) {
+				t.Errorf("assign() reserved = %v, want %v", msg.reserved, tt.wantReserved)
+			}
+		})
+	}
+}
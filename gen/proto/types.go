@@ -0,0 +1,329 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/pkg/stringx"
+)
+
+// scalars maps the basic Go kinds to their proto scalar types.
+var scalars = map[types.BasicKind]string{
+	types.Bool:    "bool",
+	types.String:  "string",
+	types.Int:     "int64",
+	types.Int8:    "int32",
+	types.Int16:   "int32",
+	types.Int32:   "int32",
+	types.Int64:   "int64",
+	types.Uint:    "uint64",
+	types.Uint8:   "uint32",
+	types.Uint16:  "uint32",
+	types.Uint32:  "uint32",
+	types.Uint64:  "uint64",
+	types.Float32: "float",
+	types.Float64: "double",
+}
+
+// wellKnowns maps the Go types to the well-known proto messages and their imports.
+var wellKnowns = map[string][2]string{
+	"time.Time":     {"google.protobuf.Timestamp", "google/protobuf/timestamp.proto"},
+	"time.Duration": {"google.protobuf.Duration", "google/protobuf/duration.proto"},
+}
+
+// fieldType sets the type and label of a field: pointers to scalars are optional, slices repeated.
+func (b *builder) fieldType(f *field, t types.Type, position astx.Position) bool {
+	t = types.Unalias(t)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(ptr.Elem())
+		f.optional = true
+	}
+
+	var elem types.Type
+	switch u := t.(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	case *types.Map:
+		key, ok := u.Key().Underlying().(*types.Basic)
+		if !ok || key.Info()&(types.IsString|types.IsBoolean|types.IsInteger) == 0 || scalars[key.Kind()] == "" {
+			b.ctx.Errorf(position, "%s: a map key must be a string, bool or integer, not %s", f.name, u.Key())
+			return false
+		}
+		value, _, ok := b.elemType(f.name, u.Elem(), position)
+		if !ok {
+			return false
+		}
+		f.typ, f.optional = "map<"+scalars[key.Kind()]+", "+value+">", false
+		return true
+	}
+
+	if elem != nil && !isByte(elem) {
+		typ, _, ok := b.elemType(f.name, elem, position)
+		if !ok {
+			return false
+		}
+		f.typ, f.repeated, f.optional = typ, true, false
+		return true
+	}
+
+	typ, message, ok := b.elemType(f.name, t, position)
+	f.typ = typ
+	if message {
+		f.optional = false
+	}
+
+	return ok
+}
+
+// elemType maps the type of a single value, reporting whether it is a message.
+func (b *builder) elemType(name string, t types.Type, position astx.Position) (string, bool, bool) {
+	t = types.Unalias(deref(t))
+	switch u := t.(type) {
+	case *types.Named:
+		if wk, ok := wellKnowns[types.TypeString(u, nil)]; ok {
+			b.file.imports[wk[1]] = true
+			return wk[0], true, true
+		}
+		if _, ok := u.Underlying().(*types.Struct); ok {
+			return b.message(u, position), true, true
+		}
+		if e := b.enum(u, position); e != "" {
+			return e, false, true
+		}
+		return b.elemType(name, u.Underlying(), position)
+	case *types.Basic:
+		if typ, ok := scalars[u.Kind()]; ok {
+			return typ, false, true
+		}
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			return "bytes", false, true
+		}
+		b.ctx.Errorf(position, "%s: %s has no proto type, repeated fields can't be nested", name, t)
+		return "", false, false
+	case *types.Array:
+		if isByte(u.Elem()) {
+			return "bytes", false, true
+		}
+		b.ctx.Errorf(position, "%s: %s has no proto type, repeated fields can't be nested", name, t)
+		return "", false, false
+	}
+
+	b.ctx.Errorf(position, "%s: %s has no proto type", name, t)
+
+	return "", false, false
+}
+
+// enum declares the enum of an integer type of the package with constants, returning its name or empty.
+func (b *builder) enum(t *types.Named, position astx.Position) string {
+	obj := t.Obj()
+	if name, ok := b.types[obj]; ok {
+		return name
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsInteger == 0 || obj.Pkg() == nil || obj.Pkg().Path() != b.ctx.Package.PkgPath {
+		return ""
+	}
+
+	consts := make([]*types.Const, 0)
+	for _, name := range obj.Pkg().Scope().Names() {
+		if c, ok := obj.Pkg().Scope().Lookup(name).(*types.Const); ok && types.Identical(c.Type(), t) {
+			consts = append(consts, c)
+		}
+	}
+	if len(consts) == 0 {
+		return ""
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	b.types[obj] = obj.Name()
+	if !b.declare(obj.Name(), position) {
+		return obj.Name()
+	}
+	e := &enum{name: obj.Name(), doc: b.typeDoc(obj)}
+	prefix := strings.ToUpper(stringx.Snake(obj.Name())) + "_"
+	docs := b.constDocs()
+	numbers := make(map[int64]string)
+	for _, c := range consts {
+		number, exact := constant.Int64Val(c.Val())
+		if !exact || number < math.MinInt32 || number > math.MaxInt32 {
+			b.ctx.Errorf(position, "%s: %s is out of the int32 range of proto enums", obj.Name(), c.Name())
+			continue
+		}
+		if other, ok := numbers[number]; ok {
+			b.ctx.Errorf(position, "%s: %s and %s have the same value %d", obj.Name(), other, c.Name(), number)
+			continue
+		}
+		numbers[number] = c.Name()
+		value := &enumValue{name: strings.ToUpper(stringx.Snake(c.Name())), number: number, doc: docs[c.Pos()]}
+		if !strings.HasPrefix(value.name, prefix) {
+			value.name = prefix + value.name
+		}
+		e.values = append(e.values, value)
+	}
+
+	// proto3 enums start with their zero value
+	if _, ok := numbers[0]; !ok {
+		e.values = append([]*enumValue{{name: prefix + "UNSPECIFIED"}}, e.values...)
+	}
+	sort.SliceStable(e.values, func(i, j int) bool {
+		return e.values[i].number == 0 && e.values[j].number != 0
+	})
+	b.file.enums = append(b.file.enums, e)
+
+	return e.name
+}
+
+func (b *builder) typeDoc(obj *types.TypeName) string {
+	for _, file := range b.ctx.Package.Syntax {
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				if ts := spec.(*ast.TypeSpec); ts.Name.Pos() == obj.Pos() {
+					if ts.Doc == nil && len(gd.Specs) == 1 {
+						return commentText(gd.Doc)
+					}
+					return commentText(ts.Doc)
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// constDocs maps the positions of the constant names of the package to their doc or line comments.
+func (b *builder) constDocs() map[token.Pos]string {
+	docs := make(map[token.Pos]string)
+	for _, file := range b.ctx.Package.Syntax {
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				doc := vs.Doc
+				if doc == nil {
+					doc = vs.Comment
+				}
+				for _, name := range vs.Names {
+					docs[name.Pos()] = commentText(doc)
+				}
+			}
+		}
+	}
+
+	return docs
+}
+
+func annotation(annotations []*astx.Annotation, name string) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == name && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func fieldSpec(ss *astx.StructSpec, name string) *astx.FieldSpec {
+	if ss == nil {
+		return nil
+	}
+	for _, fs := range ss.Fields {
+		if fs.Name == name {
+			return fs
+		}
+	}
+
+	return nil
+}
+
+func docText(doc *astx.DocSpec) string {
+	if doc == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.Join(append([]string{doc.Summary}, doc.Body...), "\n\n"))
+}
+
+// commentText is the text of a comment group without its annotation lines.
+func commentText(group *ast.CommentGroup) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(group.Text()), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "@") {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func wellKnown(t *types.Named) bool {
+	_, ok := wellKnowns[types.TypeString(t, nil)]
+	return ok
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func isByte(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+
+	return ok && basic.Kind() == types.Byte
+}
+
+func deref(t types.Type) types.Type {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+
+	return t
+}
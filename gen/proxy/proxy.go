@@ -0,0 +1,418 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const (
+	TransactionalAnnotation = "Transactional"
+	CachedAnnotation        = "Cached"
+	TimedAnnotation         = "Timed"
+	RetryAnnotation         = "Retry"
+)
+
+const proxyxPath = "github.com/photowey/parsergo/pkg/proxyx"
+
+// components are the struct annotations declaring the interface of a proxied struct with their `interface` arg.
+var components = []string{"Service", "Component", "Repository"}
+
+func init() {
+	parser.RegisterMarker(
+		&parser.MarkerDefinition{
+			Name:    TransactionalAnnotation,
+			Targets: []parser.Target{parser.TargetMethod},
+			Help:    "runs the method in a transaction, through the `Transactional` interceptor of the proxy",
+		},
+		&parser.MarkerDefinition{
+			Name:    CachedAnnotation,
+			Targets: []parser.Target{parser.TargetMethod},
+			Help:    "caches the results of the method, through the `Cached` interceptor of the proxy: `@Cached(ttl=5m)`",
+		},
+		&parser.MarkerDefinition{
+			Name:    TimedAnnotation,
+			Targets: []parser.Target{parser.TargetMethod},
+			Help:    "times the method, through the `Timed` interceptor of the proxy",
+		},
+		&parser.MarkerDefinition{
+			Name:    RetryAnnotation,
+			Targets: []parser.Target{parser.TargetMethod},
+			Help:    "retries the method up to n attempts, through the `Retry` interceptor of the proxy: `@Retry(3)`",
+		},
+	)
+}
+
+// Generator emits a `<Struct>Proxy` per struct with intercepted methods, implementing the interface
+// the struct declares with `@Service(interface=Api)`. The proxy delegates to the struct, running the
+// interceptors of the method annotations around the intercepted methods.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "proxy"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	file := ctx.NewFile()
+	w := &writer{file: file}
+	for _, fs := range ctx.Spec.Package.Files {
+		for _, ss := range fs.Structs {
+			intercepted := make(map[string][]*astx.Annotation)
+			for _, ms := range ss.Methods {
+				if annotations := interceptions(ctx, ss, ms); len(annotations) > 0 {
+					intercepted[ms.Name] = annotations
+				}
+			}
+			if len(intercepted) == 0 {
+				continue
+			}
+
+			ctx.Package.NeedTypesInfo()
+			iface := resolve(ctx, fs, ss)
+			if iface == nil {
+				continue
+			}
+			methods := make(map[string]bool)
+			for i := 0; i < iface.Underlying().(*types.Interface).NumMethods(); i++ {
+				methods[iface.Underlying().(*types.Interface).Method(i).Name()] = true
+			}
+			failed := false
+			for _, ms := range ss.Methods {
+				if intercepted[ms.Name] != nil && !methods[ms.Name] {
+					ctx.Errorf(ms.Position, "%s.%s: intercepted, but not a method of %s", ss.Name, ms.Name, iface.Obj().Name())
+					failed = true
+				}
+			}
+			if !failed {
+				w.proxy(ss.Name, iface, intercepted)
+			}
+		}
+	}
+	if w.proxies == 0 {
+		return nil
+	}
+
+	return ctx.AddFile(file)
+}
+
+// interceptions returns the intercepting annotations of the method, reporting the invalid ones.
+func interceptions(ctx *gen.Context, ss *astx.StructSpec, ms *astx.MethodSpec) []*astx.Annotation {
+	annotations := make([]*astx.Annotation, 0)
+	for _, anno := range ms.Annotations {
+		if anno.Marker {
+			continue
+		}
+		switch anno.Name {
+		case TransactionalAnnotation, TimedAnnotation:
+		case CachedAnnotation:
+			if arg := anno.Arg("ttl"); arg != nil {
+				if ttl, err := time.ParseDuration(arg.Value); err != nil || ttl <= 0 {
+					ctx.Errorf(anno.Position, "%s.%s: invalid ttl %q", ss.Name, ms.Name, arg.Value)
+					continue
+				}
+			}
+		case RetryAnnotation:
+			if n, err := strconv.Atoi(anno.Value()); err != nil || n <= 0 {
+				ctx.Errorf(anno.Position, "%s.%s: @%s needs a positive number of attempts, not %q", ss.Name, ms.Name, RetryAnnotation, anno.Value())
+				continue
+			}
+		default:
+			continue
+		}
+		annotations = append(annotations, anno)
+	}
+
+	return annotations
+}
+
+// resolve returns the interface the struct declares, reporting a missing, unknown or unimplemented one.
+func resolve(ctx *gen.Context, fs *astx.FileSpec, ss *astx.StructSpec) *types.Named {
+	var name string
+	for _, anno := range ss.Annotations {
+		for _, component := range components {
+			if anno.Name == component && !anno.Marker && anno.Arg("interface") != nil {
+				name = anno.Arg("interface").Value
+			}
+		}
+	}
+	if name == "" {
+		ctx.Errorf(ss.Position, "%s: a proxied struct needs the interface it implements: `@Service(interface=Api)`", ss.Name)
+		return nil
+	}
+
+	obj, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+	if !ok {
+		ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+		return nil
+	}
+	if named, ok := obj.Type().(*types.Named); !ok || named.TypeParams().Len() > 0 {
+		ctx.Errorf(ss.Position, "%s: a generic struct can't be proxied", ss.Name)
+		return nil
+	}
+
+	ifaceObj := fs.LookupType(ctx.Package.Types, name)
+	if ifaceObj == nil {
+		ctx.Errorf(ss.Position, "%s: unknown interface %s", ss.Name, name)
+		return nil
+	}
+	iface, ok := ifaceObj.Type().(*types.Named)
+	if !ok || !types.IsInterface(iface) || iface.TypeParams().Len() > 0 {
+		ctx.Errorf(ss.Position, "%s: %s is not a non-generic interface", ss.Name, name)
+		return nil
+	}
+	if !types.Implements(types.NewPointer(obj.Type()), iface.Underlying().(*types.Interface)) {
+		ctx.Errorf(ss.Position, "%s does not implement %s", ss.Name, name)
+		return nil
+	}
+
+	return iface
+}
+
+type writer struct {
+	file    *gen.File
+	proxies int
+}
+
+func (w *writer) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return w.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+func (w *writer) proxy(structName string, iface *types.Named, intercepted map[string][]*astx.Annotation) {
+	w.proxies++
+	name := exported(structName) + "Proxy"
+	ifaceName := w.typeName(iface)
+	proxyx := w.file.Imports.NeedImport(proxyxPath)
+	annotations := unexported(name) + "Annotations"
+
+	w.file.Printf("// %s implements %s, running the interceptors of the method annotations around %s.\n", name, ifaceName, structName)
+	w.file.Printf("type %s struct {\ntarget %s\ninterceptors %s.Interceptors\n}\n\n", name, ifaceName, proxyx)
+	w.file.Printf("var _ %s = (*%s)(nil)\n\n", ifaceName, name)
+	w.file.Printf("func New%s(target %s, interceptors %s.Interceptors) *%s {\n", name, ifaceName, proxyx, name)
+	w.file.Printf("return &%s{\ntarget: target,\ninterceptors: interceptors,\n}\n}\n\n", name)
+
+	methods := make([]string, 0, len(intercepted))
+	for method := range intercepted {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	w.file.Printf("var %s = map[string][]*%s.Annotation{\n", annotations, proxyx)
+	for _, method := range methods {
+		w.file.Printf("%q: {\n", method)
+		for _, anno := range intercepted[method] {
+			w.file.Printf("%s,\n", annotationLiteral(anno))
+		}
+		w.file.Printf("},\n")
+	}
+	w.file.Printf("}\n\n")
+
+	it := iface.Underlying().(*types.Interface)
+	for i := 0; i < it.NumMethods(); i++ {
+		method := it.Method(i)
+		if intercepted[method.Name()] != nil {
+			w.intercept(name, structName, annotations, method)
+		} else {
+			w.delegate(name, method)
+		}
+	}
+}
+
+func (w *writer) delegate(recv string, method *types.Func) {
+	sig := method.Type().(*types.Signature)
+	w.file.Printf("func (p *%s) %s%s {\n", recv, method.Name(), w.signature(sig))
+	if sig.Results().Len() > 0 {
+		w.file.Printf("return ")
+	}
+	w.file.Printf("p.target.%s\n}\n\n", call(method.Name(), sig))
+}
+
+func (w *writer) intercept(recv, structName, annotations string, method *types.Func) {
+	sig := method.Type().(*types.Signature)
+	proxyx := w.file.Imports.NeedImport(proxyxPath)
+	names := paramNames(sig)
+	results := make([]string, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, "r"+strconv.Itoa(i))
+	}
+	errResult := ""
+	if n := sig.Results().Len(); n > 0 && isError(sig.Results().At(n-1).Type()) {
+		errResult = results[n-1]
+	}
+
+	w.file.Printf("func (p *%s) %s%s {\n", recv, method.Name(), w.signature(sig))
+	switch len(results) {
+	case 0:
+	case 1:
+		w.file.Printf("var %s %s\n", results[0], w.typeName(sig.Results().At(0).Type()))
+	default:
+		w.file.Printf("var (\n")
+		for i, result := range results {
+			w.file.Printf("%s %s\n", result, w.typeName(sig.Results().At(i).Type()))
+		}
+		w.file.Printf(")\n")
+	}
+
+	invCtx := w.file.Imports.NeedImport("context") + ".Background()"
+	for i := 0; i < sig.Params().Len(); i++ {
+		if isContext(sig.Params().At(i).Type()) {
+			invCtx = names[i]
+			break
+		}
+	}
+	pointers := make([]string, 0, len(results))
+	for _, result := range results {
+		if result != errResult {
+			pointers = append(pointers, "&"+result)
+		}
+	}
+	w.file.Printf("inv := &%s.Invocation{\n", proxyx)
+	w.file.Printf("Context: %s,\nType: %q,\nMethod: %q,\nAnnotations: %s[%q],\n", invCtx, structName, method.Name(), annotations, method.Name())
+	w.file.Printf("Args: []interface{}{%s},\n", strings.Join(names, ", "))
+	w.file.Printf("Results: []interface{}{%s},\n}\n", strings.Join(pointers, ", "))
+
+	assign := "_ ="
+	if errResult != "" {
+		assign = errResult + " ="
+	}
+	w.file.Printf("%s %s.Invoke(inv, p.interceptors, func() error {\n", assign, proxyx)
+	if len(results) > 0 {
+		w.file.Printf("%s = ", strings.Join(results, ", "))
+	}
+	w.file.Printf("p.target.%s\n", call(method.Name(), sig))
+	if errResult != "" {
+		w.file.Printf("return %s\n})\n", errResult)
+	} else {
+		w.file.Printf("return nil\n})\n")
+	}
+	if len(results) > 0 {
+		w.file.Printf("\nreturn %s\n", strings.Join(results, ", "))
+	}
+	w.file.Printf("}\n\n")
+}
+
+// signature renders the params and results of sig with the param names of paramNames.
+func (w *writer) signature(sig *types.Signature) string {
+	names := paramNames(sig)
+	params := make([]string, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		t := w.typeName(sig.Params().At(i).Type())
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			t = "..." + strings.TrimPrefix(t, "[]")
+		}
+		params = append(params, names[i]+" "+t)
+	}
+
+	results := make([]string, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, w.typeName(sig.Results().At(i).Type()))
+	}
+	switch len(results) {
+	case 0:
+		return "(" + strings.Join(params, ", ") + ")"
+	case 1:
+		return "(" + strings.Join(params, ", ") + ") " + results[0]
+	}
+
+	return "(" + strings.Join(params, ", ") + ") (" + strings.Join(results, ", ") + ")"
+}
+
+func call(name string, sig *types.Signature) string {
+	args := strings.Join(paramNames(sig), ", ")
+	if sig.Variadic() {
+		args += "..."
+	}
+
+	return name + "(" + args + ")"
+}
+
+var resultName = regexp.MustCompile(`^r[0-9]+$`)
+
+// paramNames names the params, the unnamed ones and those shadowing the receiver, the locals
+// or the imports of a proxy method `p<i>`.
+func paramNames(sig *types.Signature) []string {
+	names := make([]string, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		switch name := sig.Params().At(i).Name(); {
+		case name == "", name == "_", name == "p", name == "inv", name == "proxyx", name == "context", resultName.MatchString(name):
+			names = append(names, "p"+strconv.Itoa(i))
+		default:
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// annotationLiteral renders anno as a `proxyx.Annotation` composite literal, without its type.
+func annotationLiteral(anno *astx.Annotation) string {
+	fields := []string{"Name: " + strconv.Quote(anno.Name)}
+	if value := anno.Value(); value != "" {
+		fields = append(fields, "Value: "+strconv.Quote(value))
+	}
+	args := make([]string, 0)
+	for _, arg := range anno.Args {
+		if arg.Name != "" {
+			args = append(args, strconv.Quote(arg.Name)+": "+strconv.Quote(arg.Value))
+		}
+	}
+	if len(args) > 0 {
+		fields = append(fields, "Args: map[string]string{"+strings.Join(args, ", ")+"}")
+	}
+
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	return named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func exported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routes
+
+import (
+	"go/types"
+	"net/http"
+	"reflect"
+
+	"github.com/photowey/parsergo/gen"
+)
+
+// Source is where a param, or a field of it, is read from.
+type Source int
+
+const (
+	SourceContext Source = iota
+	SourceRequest
+	SourceWriter
+	SourcePath
+	SourceQuery
+	SourceHeader
+	SourceBody
+	SourceTagged // a struct whose fields are bound by their `path`, `query` and `header` tags
+)
+
+var tagSources = []struct {
+	key    string
+	source Source
+}{
+	{key: "path", source: SourcePath},
+	{key: "query", source: SourceQuery},
+	{key: "header", source: SourceHeader},
+}
+
+type Binding struct {
+	Source Source
+	Name   string // the wire name
+	Param  *types.Var
+	Fields []*FieldBinding
+	Body   bool // decode the body into a tagged struct first
+}
+
+type FieldBinding struct {
+	Source Source
+	Name   string
+	Field  *types.Var
+}
+
+// bind maps the params of the route method to the request, reporting what can't be bound.
+func bind(ctx *gen.Context, r *Route) bool {
+	name := r.Controller.Name + "." + r.Method.Name
+	sig := r.Func.Type().(*types.Signature)
+
+	explicit := make(map[string]*Binding)
+	for _, anno := range r.Method.Annotations {
+		var src Source
+		switch anno.Name {
+		case PathAnnotation:
+			src = SourcePath
+		case QueryAnnotation:
+			src = SourceQuery
+		case HeaderAnnotation:
+			src = SourceHeader
+		case BodyAnnotation:
+			src = SourceBody
+		default:
+			continue
+		}
+		if len(anno.Args) == 0 || anno.Args[0].Value == "" {
+			ctx.Errorf(anno.Position, "%s: @%s needs the name of a param", name, anno.Name)
+			return false
+		}
+		b := &Binding{Source: src, Name: anno.Args[0].Value}
+		if len(anno.Args) > 1 {
+			b.Name = anno.Args[1].Value
+		}
+		explicit[anno.Args[0].Value] = b
+	}
+
+	wild := make(map[string]bool)
+	for _, wildcard := range wildcards(r.Path) {
+		wild[wildcard] = true
+	}
+	bound := make(map[string]bool)
+	bodies := 0
+	ok := true
+	fail := func(format string, args ...interface{}) {
+		ctx.Errorf(r.Method.Position, "%s: "+format, append([]interface{}{name}, args...)...)
+		ok = false
+	}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		t := param.Type()
+		b := explicit[param.Name()]
+		delete(explicit, param.Name())
+
+		switch {
+		case b != nil:
+		case isNamed(t, "context", "Context"):
+			b = &Binding{Source: SourceContext}
+		case isNamed(t, "net/http", "ResponseWriter"):
+			b = &Binding{Source: SourceWriter}
+		case isPointer(t) && isNamed(t.(*types.Pointer).Elem(), "net/http", "Request"):
+			b = &Binding{Source: SourceRequest}
+		case wild[param.Name()]:
+			b = &Binding{Source: SourcePath, Name: param.Name()}
+		case structOf(t) != nil:
+			b = &Binding{Source: SourceBody}
+			for j := 0; j < structOf(t).NumFields(); j++ {
+				field := structOf(t).Field(j)
+				tag := reflect.StructTag(structOf(t).Tag(j))
+				for _, ts := range tagSources {
+					if wire, found := tag.Lookup(ts.key); found && field.Exported() {
+						b.Fields = append(b.Fields, &FieldBinding{Source: ts.source, Name: wire, Field: field})
+					}
+				}
+			}
+			if len(b.Fields) > 0 {
+				b.Source = SourceTagged
+				b.Body = r.Verb == http.MethodPost || r.Verb == http.MethodPut || r.Verb == http.MethodPatch
+			}
+		default:
+			b = &Binding{Source: SourceQuery, Name: param.Name()}
+		}
+		b.Param = param
+
+		switch b.Source {
+		case SourcePath:
+			bound[b.Name] = true
+			if !scalar(t) {
+				fail("path param %s must be a string, bool or number, not %s", param.Name(), t)
+			}
+		case SourceQuery, SourceHeader:
+			if !scalar(t) && !(isSlice(t) && scalar(t.Underlying().(*types.Slice).Elem())) {
+				fail("param %s must be a string, bool, number or a slice of them, not %s", param.Name(), t)
+			}
+		case SourceTagged:
+			for _, fb := range b.Fields {
+				if fb.Source == SourcePath {
+					bound[fb.Name] = true
+				}
+				if !scalar(fb.Field.Type()) && (fb.Source == SourcePath || !isSlice(fb.Field.Type()) || !scalar(fb.Field.Type().Underlying().(*types.Slice).Elem())) {
+					fail("field %s of param %s can't be bound from a %s", fb.Field.Name(), param.Name(), tagSourceName(fb.Source))
+				}
+			}
+		}
+		if b.Source == SourceBody || b.Body {
+			bodies++
+		}
+		r.Bindings = append(r.Bindings, b)
+	}
+
+	for param := range explicit {
+		fail("no param %s to bind", param)
+	}
+	for wildcard := range wild {
+		if !bound[wildcard] {
+			fail("path wildcard {%s} is not bound to any param", wildcard)
+		}
+	}
+	for wildcard := range bound {
+		if !wild[wildcard] {
+			fail("path param %s has no {%s} wildcard in %q", wildcard, wildcard, r.Path)
+		}
+	}
+	if bodies > 1 {
+		fail("more than one param is bound to the request body")
+	}
+
+	results := sig.Results()
+	switch {
+	case results.Len() == 0:
+	case results.Len() == 1:
+	case results.Len() == 2 && isError(results.At(1).Type()):
+	default:
+		fail("results must be (), (T), (error) or (T, error)")
+	}
+
+	return ok
+}
+
+func tagSourceName(src Source) string {
+	return tagSources[int(src-SourcePath)].key
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.(*types.Pointer)
+	return ok
+}
+
+func isSlice(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Slice)
+	return ok
+}
+
+// structOf returns the struct of a named struct type or of a pointer to one.
+func structOf(t types.Type) *types.Struct {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if _, ok := t.(*types.Named); !ok {
+		return nil
+	}
+	st, _ := t.Underlying().(*types.Struct)
+
+	return st
+}
+
+func scalar(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+
+	return basic.Info()&(types.IsString|types.IsBoolean|types.IsInteger|types.IsFloat) != 0
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routes
+
+import (
+	"go/ast"
+	"go/types"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const (
+	ControllerAnnotation = "Controller"
+	PathAnnotation       = "Path"
+	QueryAnnotation      = "Query"
+	HeaderAnnotation     = "Header"
+	BodyAnnotation       = "Body"
+	StatusAnnotation     = "Status"
+)
+
+// verbs maps the method annotations to their HTTP methods.
+var verbs = map[string]string{
+	"Get":     http.MethodGet,
+	"Head":    http.MethodHead,
+	"Post":    http.MethodPost,
+	"Put":     http.MethodPut,
+	"Patch":   http.MethodPatch,
+	"Delete":  http.MethodDelete,
+	"Options": http.MethodOptions,
+}
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    ControllerAnnotation,
+		Targets: []parser.Target{parser.TargetStruct},
+		Help:    "an HTTP controller and its path prefix: `@Controller(\"/users\")`",
+	})
+	for name := range verbs {
+		parser.RegisterMarker(&parser.MarkerDefinition{
+			Name:    name,
+			Targets: []parser.Target{parser.TargetMethod},
+			Help:    "an HTTP route relative to the controller prefix: `@" + name + "(\"/{id}\")`",
+		})
+	}
+	for _, name := range []string{PathAnnotation, QueryAnnotation, HeaderAnnotation, BodyAnnotation} {
+		parser.RegisterMarker(&parser.MarkerDefinition{
+			Name:       name,
+			Targets:    []parser.Target{parser.TargetMethod},
+			Repeatable: true,
+			Help:       "binds a method param by name, optionally renamed: `@" + name + "(param, \"name\")`",
+		})
+	}
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    StatusAnnotation,
+		Targets: []parser.Target{parser.TargetMethod},
+		Help:    "the status code of a successful response: `@Status(201)`",
+	})
+}
+
+// Generator emits a `Register<Controller>Routes(mux, controller)` func per @Controller struct,
+// registering its routes as Go 1.22 `net/http` patterns.
+// The routes of a controller share a mux, so they must not conflict; the controllers are free to mount on separate muxes.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "routes"
+}
+
+// Route is the HTTP route of a @Controller method.
+type Route struct {
+	Controller *astx.StructSpec
+	Prefix     string
+	Method     *astx.MethodSpec
+	Func       *types.Func
+	Verb       string
+	Path       string
+	Status     int
+	Bindings   []*Binding
+}
+
+func (r *Route) Pattern() string {
+	return r.Verb + " " + r.Path
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	collected := Collect(ctx)
+	if len(collected) == 0 {
+		return nil
+	}
+
+	routes := make([]*Route, 0, len(collected))
+	registered := make(map[*astx.StructSpec][]*Route)
+	for _, r := range collected {
+		if first := conflicting(registered[r.Controller], r); first != nil {
+			ctx.Errorf(r.Method.Position, "%s.%s: route %q conflicts with %s.%s at %s",
+				r.Controller.Name, r.Method.Name, r.Pattern(), first.Controller.Name, first.Method.Name, first.Method.Position)
+			continue
+		}
+		registered[r.Controller] = append(registered[r.Controller], r)
+		routes = append(routes, r)
+	}
+
+	file := ctx.NewFile()
+	w := &writer{file: file}
+	for i := 0; i < len(routes); {
+		j := i + 1
+		for j < len(routes) && routes[j].Controller == routes[i].Controller {
+			j++
+		}
+		w.controller(routes[i].Controller, routes[i:j])
+		i = j
+	}
+	w.helpers()
+
+	return ctx.AddFile(file)
+}
+
+// routesKey caches the routes of a package on its spec, shared by every generator of the run.
+var routesKey = astx.NewExtensionKey[[]*Route]("routes.routes")
+
+// Collect returns the routes of the @Controller structs of the package, sorted by controller,
+// then in declaration order. The routes which can't be bound are reported on ctx and left out.
+// The package is collected once: the other generators, e.g. openapi, share the model and don't report its errors again.
+func Collect(ctx *gen.Context) []*Route {
+	if routes, ok := routesKey.Get(&ctx.Spec.Package.Extensions); ok {
+		return routes
+	}
+
+	controllers := make([]*astx.StructSpec, 0)
+	for _, ss := range ctx.Spec.Package.Structs {
+		if annotation(ss.Annotations, ControllerAnnotation) != nil {
+			controllers = append(controllers, ss)
+		}
+	}
+	sort.SliceStable(controllers, func(i, j int) bool {
+		return controllers[i].Name < controllers[j].Name
+	})
+
+	routes := make([]*Route, 0)
+	for _, ss := range controllers {
+		prefix := annotation(ss.Annotations, ControllerAnnotation).Value()
+		for _, ms := range ss.Methods {
+			if r := collect(ctx, ss, prefix, ms); r != nil {
+				routes = append(routes, r)
+			}
+		}
+	}
+
+	routesKey.Set(&ctx.Spec.Package.Extensions, routes)
+
+	return routes
+}
+
+func collect(ctx *gen.Context, ss *astx.StructSpec, prefix string, ms *astx.MethodSpec) *Route {
+	var verb *astx.Annotation
+	for _, anno := range ms.Annotations {
+		if _, ok := verbs[anno.Name]; ok && !anno.Marker {
+			if verb != nil {
+				ctx.Errorf(anno.Position, "%s.%s: more than one HTTP method annotation", ss.Name, ms.Name)
+				return nil
+			}
+			verb = anno
+		}
+	}
+	if verb == nil {
+		return nil
+	}
+
+	r := &Route{
+		Controller: ss,
+		Prefix:     prefix,
+		Method:     ms,
+		Verb:       verbs[verb.Name],
+		Path:       joinPath(prefix, verb.Value()),
+		Status:     http.StatusOK,
+	}
+	if anno := annotation(ms.Annotations, StatusAnnotation); anno != nil {
+		status, err := strconv.Atoi(anno.Value())
+		if err != nil || status < 100 || status > 599 {
+			ctx.Errorf(anno.Position, "%s.%s: invalid status %q", ss.Name, ms.Name, anno.Value())
+			return nil
+		}
+		r.Status = status
+	}
+	if !validPath(r.Path) {
+		ctx.Errorf(verb.Position, "%s.%s: invalid path %q", ss.Name, ms.Name, r.Path)
+		return nil
+	}
+
+	ctx.Package.NeedTypesInfo()
+	decl, ok := ms.Node.(*ast.FuncDecl)
+	if !ok || ctx.Package.TypesInfo == nil {
+		return nil
+	}
+	if r.Func, ok = ctx.Package.TypesInfo.Defs[decl.Name].(*types.Func); !ok {
+		ctx.Errorf(ms.Position, "%s.%s: missing type information", ss.Name, ms.Name)
+		return nil
+	}
+	if !bind(ctx, r) {
+		return nil
+	}
+
+	return r
+}
+
+func annotation(annotations []*astx.Annotation, name string) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == name && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func joinPath(prefix, sub string) string {
+	prefix = strings.Trim(prefix, "/")
+	sub = strings.TrimLeft(sub, "/")
+	joined := "/" + prefix
+	if sub != "" {
+		if prefix != "" {
+			joined += "/"
+		}
+		joined += sub
+	}
+
+	return joined
+}
+
+// wildcards returns the names of the `{name}` and `{name...}` segments of path.
+func wildcards(path string) []string {
+	names := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, strings.TrimSuffix(segment[1:len(segment)-1], "..."))
+		}
+	}
+
+	return names
+}
+
+func validPath(path string) bool {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") || segment == "{$}" && i == len(segments)-1 {
+			continue
+		}
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			return false
+		}
+		name := segment[1 : len(segment)-1]
+		if strings.HasSuffix(name, "...") {
+			if i != len(segments)-1 {
+				return false
+			}
+			name = strings.TrimSuffix(name, "...")
+		}
+		if !token(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func token(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conflicting returns the route conflicting with r, if any: `http.ServeMux` panics on the patterns
+// matching some requests in common without one being more specific, e.g. `/{id}/posts` and `/me/{section}`.
+func conflicting(registered []*Route, r *Route) *Route {
+	for _, other := range registered {
+		if !registers(other, r) {
+			return other
+		}
+	}
+
+	return nil
+}
+
+// registers reports whether the routes register on a single mux.
+func registers(routes ...*Route) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	mux := http.NewServeMux()
+	for _, r := range routes {
+		mux.HandleFunc(r.Pattern(), func(http.ResponseWriter, *http.Request) {})
+	}
+
+	return true
+}
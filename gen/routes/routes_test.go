@@ -0,0 +1,47 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routes
+
+import (
+	"testing"
+
+	"github.com/photowey/parsergo/gen/gentest"
+)
+
+func TestGenerator(t *testing.T) {
+	gentest.Run(t, New,
+		gentest.Case{
+			Name:      "routex",
+			Pkg:       "github.com/photowey/parsergo/tests/routex",
+			WantFiles: []string{"routex/zz_generated.routes.go"},
+		},
+		gentest.Case{
+			Name:      "separate muxes",
+			Pkg:       "github.com/photowey/parsergo/tests/routex/mountx/...",
+			WantFiles: []string{"routex/mountx/adminx/zz_generated.routes.go", "routex/mountx/shopx/zz_generated.routes.go"},
+		},
+		gentest.Case{
+			Name: "conflicts",
+			Pkg:  "github.com/photowey/parsergo/tests/routex/conflictx",
+			WantDiags: []string{
+				"ItemController.Missing: path wildcard {id} is not bound to any param",
+				`ItemController.ByName: route "GET /items/{name}" conflicts with ItemController.Get at ` + gentest.Path(t, "routex/conflictx/conflictx.go") + ":25:1",
+				`UserController.Section: route "GET /users/me/{section}" conflicts with UserController.Posts at ` + gentest.Path(t, "routex/conflictx/conflictx.go") + ":45:1",
+			},
+		},
+	)
+}
@@ -0,0 +1,286 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routes
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+)
+
+type writer struct {
+	file *gen.File
+	used map[string]bool
+
+	body    strings.Builder
+	needErr bool
+}
+
+func (w *writer) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return w.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+func (w *writer) pkg(path string) string {
+	return w.file.Imports.NeedImport(path)
+}
+
+func (w *writer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.body, format, args...)
+}
+
+func (w *writer) controller(ctrl *astx.StructSpec, routes []*Route) {
+	http := w.pkg("net/http")
+	w.file.Printf("// Register%sRoutes registers the routes of %s on mux.\n", ctrl.Name, ctrl.Name)
+	w.file.Printf("func Register%sRoutes(mux *%s.ServeMux, c *%s) {\n", ctrl.Name, http, ctrl.Name)
+	for _, r := range routes {
+		w.body.Reset()
+		w.needErr = false
+		w.handler(r)
+
+		w.file.Printf("mux.HandleFunc(%q, func(w %s.ResponseWriter, r *%s.Request) {\n", r.Pattern(), http, http)
+		if w.needErr {
+			w.file.Printf("var err error\n")
+		}
+		w.file.Printf("%s", w.body.String())
+		w.file.Printf("})\n")
+	}
+	w.file.Printf("}\n\n")
+}
+
+func (w *writer) handler(r *Route) {
+	args := make([]string, 0, len(r.Bindings))
+	writes := false
+	for i, b := range r.Bindings {
+		arg := fmt.Sprintf("arg%d", i)
+		args = append(args, arg)
+		t := b.Param.Type()
+
+		switch b.Source {
+		case SourceContext:
+			w.printf("%s := r.Context()\n", arg)
+		case SourceRequest:
+			w.printf("%s := r\n", arg)
+		case SourceWriter:
+			w.printf("%s := w\n", arg)
+			writes = true
+		case SourceBody:
+			w.decl(arg, t)
+			w.decode(arg, t)
+		case SourceTagged:
+			w.decl(arg, t)
+			if b.Body {
+				w.decode(arg, t)
+			}
+			for _, fb := range b.Fields {
+				w.bindValue(arg+"."+fb.Field.Name(), fb.Field.Type(), fb.Source, fb.Name)
+			}
+		default:
+			w.printf("var %s %s\n", arg, w.typeName(t))
+			w.bindValue(arg, t, b.Source, b.Name)
+		}
+	}
+
+	sig := r.Func.Type().(*types.Signature)
+	if sig.Variadic() {
+		args[len(args)-1] += "..."
+	}
+	call := fmt.Sprintf("c.%s(%s)", r.Method.Name, strings.Join(args, ", "))
+	http := w.pkg("net/http")
+	results := sig.Results()
+	switch {
+	case results.Len() == 0:
+		w.printf("%s\n", call)
+	case results.Len() == 1 && isError(results.At(0).Type()):
+		w.printf("if err := %s; err != nil {\nroutesWriteError(w, err, %s.StatusInternalServerError)\nreturn\n}\n", call, http)
+	case results.Len() == 1:
+		w.printf("result := %s\n", call)
+	default:
+		w.printf("result, err := %s\n", call)
+		w.printf("if err != nil {\nroutesWriteError(w, err, %s.StatusInternalServerError)\nreturn\n}\n", http)
+	}
+
+	status := strconv.Itoa(r.Status)
+	if name, ok := statusNames[r.Status]; ok {
+		status = http + "." + name
+	}
+	if results.Len() == 2 || results.Len() == 1 && !isError(results.At(0).Type()) {
+		w.use("writeJSON")
+		w.printf("routesWriteJSON(w, %s, result)\n", status)
+		return
+	}
+	if !writes {
+		w.printf("w.WriteHeader(%s)\n", status)
+	}
+}
+
+func (w *writer) decl(arg string, t types.Type) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		w.printf("%s := new(%s)\n", arg, w.typeName(ptr.Elem()))
+		return
+	}
+	w.printf("var %s %s\n", arg, w.typeName(t))
+}
+
+func (w *writer) decode(arg string, t types.Type) {
+	w.needErr = true
+	target := "&" + arg
+	if isPointer(t) {
+		target = arg
+	}
+	w.printf("if err = %s.NewDecoder(r.Body).Decode(%s); err != nil {\n", w.pkg("encoding/json"), target)
+	w.badRequest()
+	w.printf("}\n")
+}
+
+func (w *writer) bindValue(target string, t types.Type, src Source, name string) {
+	if isSlice(t) {
+		values := fmt.Sprintf("r.URL.Query()[%q]", name)
+		if src == SourceHeader {
+			values = fmt.Sprintf("r.Header.Values(%q)", name)
+		}
+		elem := t.Underlying().(*types.Slice).Elem()
+		w.printf("for _, raw := range %s {\n", values)
+		w.printf("var item %s\n", w.typeName(elem))
+		w.parse("item", elem)
+		w.printf("%s = append(%s, item)\n", target, target)
+		w.printf("}\n")
+		return
+	}
+
+	var raw string
+	switch src {
+	case SourcePath:
+		raw = fmt.Sprintf("r.PathValue(%q)", name)
+	case SourceHeader:
+		raw = fmt.Sprintf("r.Header.Get(%q)", name)
+	default:
+		raw = fmt.Sprintf("r.URL.Query().Get(%q)", name)
+	}
+	w.printf("if raw := %s; raw != \"\" {\n", raw)
+	w.parse(target, t)
+	w.printf("}\n")
+}
+
+// parse converts the string `raw` into target, answering 400 when it can't.
+func (w *writer) parse(target string, t types.Type) {
+	basic := t.Underlying().(*types.Basic)
+	info := basic.Info()
+	if info&types.IsString != 0 {
+		if types.Identical(t, types.Typ[types.String]) {
+			w.printf("%s = raw\n", target)
+		} else {
+			w.printf("%s = %s(raw)\n", target, w.typeName(t))
+		}
+		return
+	}
+
+	var call string
+	switch {
+	case info&types.IsBoolean != 0:
+		w.use("parseBool")
+		call = fmt.Sprintf("routesParseBool[%s](raw)", w.typeName(t))
+	case info&types.IsUnsigned != 0:
+		w.use("parseUint")
+		call = fmt.Sprintf("routesParseUint[%s](raw, %d)", w.typeName(t), bits(basic))
+	case info&types.IsInteger != 0:
+		w.use("parseInt")
+		call = fmt.Sprintf("routesParseInt[%s](raw, %d)", w.typeName(t), bits(basic))
+	default:
+		w.use("parseFloat")
+		call = fmt.Sprintf("routesParseFloat[%s](raw, %d)", w.typeName(t), bits(basic))
+	}
+
+	w.needErr = true
+	w.printf("if %s, err = %s; err != nil {\n", target, call)
+	w.badRequest()
+	w.printf("}\n")
+}
+
+func (w *writer) badRequest() {
+	w.printf("routesWriteError(w, err, %s.StatusBadRequest)\nreturn\n", w.pkg("net/http"))
+}
+
+func (w *writer) use(helper string) {
+	if w.used == nil {
+		w.used = make(map[string]bool)
+	}
+	w.used[helper] = true
+}
+
+func (w *writer) helpers() {
+	http := w.pkg("net/http")
+	w.file.Printf(`func routesWriteError(w %s.ResponseWriter, err error, status int) {
+	var coder interface{ StatusCode() int }
+	if %s.As(err, &coder) {
+		status = coder.StatusCode()
+	}
+	%s.Error(w, err.Error(), status)
+}
+`, http, w.pkg("errors"), http)
+
+	if w.used["writeJSON"] {
+		w.file.Printf(`
+func routesWriteJSON(w %s.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = %s.NewEncoder(w).Encode(v)
+}
+`, http, w.pkg("encoding/json"))
+	}
+
+	parsers := []struct {
+		helper, signature, body string
+	}{
+		{"parseBool", "routesParseBool[T ~bool](raw string)", "v, err := %s.ParseBool(raw)"},
+		{"parseInt", "routesParseInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](raw string, bits int)", "v, err := %s.ParseInt(raw, 10, bits)"},
+		{"parseUint", "routesParseUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](raw string, bits int)", "v, err := %s.ParseUint(raw, 10, bits)"},
+		{"parseFloat", "routesParseFloat[T ~float32 | ~float64](raw string, bits int)", "v, err := %s.ParseFloat(raw, bits)"},
+	}
+	for _, p := range parsers {
+		if w.used[p.helper] {
+			w.file.Printf("\nfunc %s (T, error) {\n\t"+p.body+"\n\treturn T(v), err\n}\n", p.signature, w.pkg("strconv"))
+		}
+	}
+}
+
+func bits(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Float64, types.Uintptr:
+		return 64
+	}
+
+	return 0 // int and uint
+}
+
+var statusNames = map[int]string{
+	200: "StatusOK",
+	201: "StatusCreated",
+	202: "StatusAccepted",
+	204: "StatusNoContent",
+}
@@ -0,0 +1,761 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+	"github.com/photowey/parsergo/pkg/validatex"
+)
+
+const (
+	MinAnnotation = "Min"
+	MaxAnnotation = "Max"
+)
+
+func init() {
+	parser.RegisterMarker(
+		&parser.MarkerDefinition{
+			Name:    MinAnnotation,
+			Targets: []parser.Target{parser.TargetField},
+			Help:    "the minimum of a number, or the minimum length of a string or slice, like `validate:\"min=1\"`: `@Min(1)`",
+		},
+		&parser.MarkerDefinition{
+			Name:    MaxAnnotation,
+			Targets: []parser.Target{parser.TargetField},
+			Help:    "the maximum of a number, or the maximum length of a string or slice, like `validate:\"max=8\"`: `@Max(8)`",
+		},
+	)
+}
+
+// Schema is a format-neutral JSON schema, rendered by the OpenAPI and JSON Schema writers.
+type Schema struct {
+	Ref                  string // the id of a definition, see Builder.Names
+	Type                 string
+	Format               string
+	Nullable             bool
+	Description          string
+	Enum                 []interface{}
+	Items                *Schema
+	Properties           map[string]*Schema
+	Required             []string // in declaration order
+	AdditionalProperties *Schema
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     bool
+	ExclusiveMaximum     bool
+	MinLength            *int
+	MaxLength            *int
+	MinItems             *int
+	MaxItems             *int
+	Pattern              string
+}
+
+// Definition is the schema of a named struct or enum type.
+type Definition struct {
+	ID     string // the type string, e.g. `example.com/pkg.Page[example.com/pkg.User]`
+	Type   *types.Named
+	Schema *Schema
+}
+
+// Builder maps Go types to schemas, collecting the definitions of the named types they reference.
+// Docs and tags are read from the parsed specs of the packages added, and of the packages they import.
+type Builder struct {
+	Definitions map[string]*Definition
+
+	packages map[string]*loader.Package
+	specs    map[string]*astx.AstSpec
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		Definitions: make(map[string]*Definition),
+		packages:    make(map[string]*loader.Package),
+		specs:       make(map[string]*astx.AstSpec),
+	}
+}
+
+// AddPackage makes the docs of pkg and of its imports available, spec being the already parsed spec of pkg, if any.
+func (b *Builder) AddPackage(pkg *loader.Package, spec *astx.AstSpec) {
+	if spec != nil {
+		b.specs[pkg.PkgPath] = spec
+	}
+	var visit func(pkg *loader.Package)
+	visit = func(pkg *loader.Package) {
+		if _, ok := b.packages[pkg.PkgPath]; ok {
+			return
+		}
+		b.packages[pkg.PkgPath] = pkg
+		for _, imported := range pkg.Imports() {
+			visit(imported)
+		}
+	}
+	visit(pkg)
+}
+
+// Sorted returns the definitions sorted by id.
+func (b *Builder) Sorted() []*Definition {
+	defs := make([]*Definition, 0, len(b.Definitions))
+	for _, def := range b.Definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].ID < defs[j].ID
+	})
+
+	return defs
+}
+
+// Names maps the definition ids to unique names: the type name, qualified by the package name on collision.
+func (b *Builder) Names() map[string]string {
+	byName := make(map[string][]*Definition)
+	for _, def := range b.Sorted() {
+		name := baseName(def.Type)
+		byName[name] = append(byName[name], def)
+	}
+
+	names := make(map[string]string, len(b.Definitions))
+	for name, defs := range byName {
+		for _, def := range defs {
+			switch pkg := def.Type.Obj().Pkg(); {
+			case len(defs) == 1:
+				names[def.ID] = name
+			case pkg != nil && qualifiedUnique(defs, pkg):
+				names[def.ID] = pkg.Name() + "." + name
+			case pkg != nil:
+				names[def.ID] = strings.NewReplacer("/", ".", "~", ".").Replace(pkg.Path()) + "." + name
+			default:
+				names[def.ID] = name
+			}
+		}
+	}
+
+	return names
+}
+
+func (b *Builder) Schema(t types.Type) *Schema {
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		s := b.Schema(t.Elem())
+		s.Nullable = true
+		return s
+	case *types.Named:
+		return b.named(t)
+	case *types.Basic:
+		return basic(t)
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.Schema(t.Elem())}
+	case *types.Array:
+		n := int(t.Len())
+		return &Schema{Type: "array", Items: b.Schema(t.Elem()), MinItems: &n, MaxItems: &n}
+	case *types.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.Schema(t.Elem())}
+	case *types.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		b.fields(s, t, nil)
+		return s
+	}
+
+	return &Schema{}
+}
+
+func (b *Builder) named(t *types.Named) *Schema {
+	obj := t.Obj()
+	if obj.Pkg() != nil {
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return &Schema{Type: "string", Format: "date-time"}
+		case "time.Duration":
+			return &Schema{Type: "integer", Format: "int64"}
+		}
+	}
+	if hasMethod(t, "MarshalJSON") {
+		return &Schema{}
+	}
+	if hasMethod(t, "MarshalText") {
+		return &Schema{Type: "string"}
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Struct:
+		return b.define(t, func() *Schema {
+			s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			b.fields(s, underlying, b.structSpec(t))
+			return s
+		})
+	case *types.Basic:
+		if enum := b.enum(t); len(enum) > 0 {
+			return b.define(t, func() *Schema {
+				s := basic(underlying)
+				lines := make([]string, 0)
+				for _, c := range enum {
+					s.Enum = append(s.Enum, c.value)
+					if c.doc != "" {
+						lines = append(lines, "- `"+c.text+"`: "+c.doc)
+					}
+				}
+				s.Description = strings.Join(lines, "\n")
+				return s
+			})
+		}
+	}
+
+	return b.Schema(t.Underlying())
+}
+
+// define adds the definition of t once, its schema built after the definition is known so recursive types terminate.
+func (b *Builder) define(t *types.Named, build func() *Schema) *Schema {
+	id := types.TypeString(t, nil)
+	if _, ok := b.Definitions[id]; !ok {
+		def := &Definition{ID: id, Type: t}
+		b.Definitions[id] = def
+		def.Schema = build()
+		if doc := b.typeDoc(t); doc != "" {
+			def.Schema.Description = strings.TrimSpace(doc + "\n\n" + def.Schema.Description)
+		}
+	}
+
+	return &Schema{Ref: id}
+}
+
+// fields adds the properties of st as encoding/json marshals them, embedded structs flattened:
+// of the fields sharing a name, the shallowest one wins, then the only tagged one, otherwise none.
+func (b *Builder) fields(s *Schema, st *types.Struct, ss *astx.StructSpec) {
+	candidates := b.candidates(st, ss, 0, make(map[*types.Named]bool))
+	byName := make(map[string][]*candidate, len(candidates))
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+
+	for _, c := range candidates {
+		if dominant(byName[c.name]) != c {
+			continue
+		}
+
+		fieldSchema := b.Schema(c.field.Type())
+		if hasOption(c.opts, "string") && (fieldSchema.Type == "integer" || fieldSchema.Type == "number" || fieldSchema.Type == "boolean") {
+			fieldSchema.Type, fieldSchema.Format = "string", ""
+		}
+		rules := validatex.Parse(c.tag.Get("validate"))
+		Constrain(fieldSchema, append(annotationRules(c.tag.spec), rules...))
+		if fs := c.tag.spec; fs != nil {
+			fieldSchema.Description = docText(fs.Doc)
+			if fieldSchema.Description == "" {
+				fieldSchema.Description = docText(fs.Comment)
+			}
+		}
+
+		s.Properties[c.name] = fieldSchema
+		if validatex.Find(rules, "required") != nil || !hasOption(c.opts, "omitempty") && validatex.Find(rules, "omitempty") == nil {
+			s.Required = append(s.Required, c.name)
+		}
+	}
+}
+
+// candidate is a field of a struct, or of its embedded structs, marshaled under name.
+type candidate struct {
+	name   string
+	opts   string
+	tagged bool
+	depth  int
+	field  *types.Var
+	tag    tags
+}
+
+// candidates lists the marshaled fields of st in field order, those of its embedded structs in their place.
+// The embedding path guards against recursive embedding.
+func (b *Builder) candidates(st *types.Struct, ss *astx.StructSpec, depth int, path map[*types.Named]bool) []*candidate {
+	candidates := make([]*candidate, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := tags{spec: fieldSpec(ss, field.Name()), raw: reflect.StructTag(st.Tag(i))}
+
+		name, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Embedded() && name == "" {
+			embedded := field.Type()
+			if ptr, ok := embedded.(*types.Pointer); ok {
+				embedded = ptr.Elem()
+			}
+			if named, ok := types.Unalias(embedded).(*types.Named); ok {
+				if est, ok := named.Underlying().(*types.Struct); ok {
+					if !path[named] {
+						path[named] = true
+						candidates = append(candidates, b.candidates(est, b.structSpec(named), depth+1, path)...)
+						delete(path, named)
+					}
+					continue
+				}
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+
+		c := &candidate{name: name, opts: opts, tagged: name != "", depth: depth, field: field, tag: tag}
+		if c.name == "" {
+			c.name = field.Name()
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates
+}
+
+// dominant is the candidate encoding/json marshals among those sharing a name, nil when they conflict.
+func dominant(candidates []*candidate) *candidate {
+	shallowest := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		switch {
+		case len(shallowest) == 0 || c.depth == shallowest[0].depth:
+			shallowest = append(shallowest, c)
+		case c.depth < shallowest[0].depth:
+			shallowest = append(shallowest[:0], c)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0]
+	}
+
+	var found *candidate
+	for _, c := range shallowest {
+		if c.tagged {
+			if found != nil {
+				return nil
+			}
+			found = c
+		}
+	}
+
+	return found
+}
+
+// annotationRules maps the @Min and @Max annotations of a field to their validate rules.
+func annotationRules(fs *astx.FieldSpec) []*validatex.Rule {
+	rules := make([]*validatex.Rule, 0)
+	if fs == nil {
+		return rules
+	}
+	for _, anno := range fs.Annotations {
+		switch {
+		case anno.Marker:
+		case anno.Name == MinAnnotation:
+			rules = append(rules, &validatex.Rule{Name: "min", Param: anno.Value()})
+		case anno.Name == MaxAnnotation:
+			rules = append(rules, &validatex.Rule{Name: "max", Param: anno.Value()})
+		}
+	}
+
+	return rules
+}
+
+// Constrain maps the validate rules onto s, those after `dive` onto its items.
+// The schemas of definitions are left alone, their refs can't carry constraints.
+func Constrain(s *Schema, rules []*validatex.Rule) {
+	if s.Ref != "" {
+		return
+	}
+
+	rules, elem := validatex.Dive(rules)
+	for _, rule := range rules {
+		switch rule.Name {
+		case "min", "gte":
+			bound(s, rule.Param, true, false)
+		case "max", "lte":
+			bound(s, rule.Param, false, false)
+		case "gt":
+			bound(s, rule.Param, true, true)
+		case "lt":
+			bound(s, rule.Param, false, true)
+		case "len":
+			bound(s, rule.Param, true, false)
+			bound(s, rule.Param, false, false)
+		case "oneof":
+			s.Enum = nil
+			for _, value := range rule.Values() {
+				s.Enum = append(s.Enum, typed(s.Type, value))
+			}
+		case "email", "hostname", "ipv4", "ipv6", "uuid":
+			s.Format = rule.Name
+		case "url", "uri":
+			s.Format = "uri"
+		case "uuid3", "uuid4", "uuid5":
+			s.Format = "uuid"
+		case "alpha":
+			s.Pattern = "^[a-zA-Z]+$"
+		case "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]+$"
+		case "numeric":
+			s.Pattern = "^[-+]?[0-9]+(?:\\.[0-9]+)?$"
+		}
+	}
+
+	switch {
+	case elem == nil:
+	case s.Items != nil:
+		Constrain(s.Items, elem)
+	case s.AdditionalProperties != nil:
+		Constrain(s.AdditionalProperties, elem)
+	}
+}
+
+// bound sets a lower or upper bound: a value for numbers, a length for strings and arrays.
+func bound(s *Schema, param string, lower, exclusive bool) {
+	switch s.Type {
+	case "integer", "number":
+		value, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			s.Minimum, s.ExclusiveMinimum = &value, exclusive
+		} else {
+			s.Maximum, s.ExclusiveMaximum = &value, exclusive
+		}
+	case "string", "array":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if exclusive && lower {
+			n++
+		} else if exclusive {
+			n--
+		}
+		switch {
+		case s.Type == "string" && lower:
+			s.MinLength = &n
+		case s.Type == "string":
+			s.MaxLength = &n
+		case lower:
+			s.MinItems = &n
+		default:
+			s.MaxItems = &n
+		}
+	}
+}
+
+func typed(typ, value string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+
+	return value
+}
+
+type enumValue struct {
+	text  string
+	value interface{}
+	doc   string
+}
+
+// enum returns the constants of t declared in its package, in declaration order.
+func (b *Builder) enum(t *types.Named) []*enumValue {
+	pkg := t.Obj().Pkg()
+	if pkg == nil {
+		return nil
+	}
+
+	consts := make([]*types.Const, 0)
+	for _, name := range pkg.Scope().Names() {
+		if c, ok := pkg.Scope().Lookup(name).(*types.Const); ok && types.Identical(c.Type(), t) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	docs := b.constDocs(pkg.Path())
+	enum := make([]*enumValue, 0, len(consts))
+	for _, c := range consts {
+		ev := &enumValue{doc: docs[c.Pos()]}
+		switch c.Val().Kind() {
+		case constant.String:
+			ev.value = constant.StringVal(c.Val())
+			ev.text = constant.StringVal(c.Val())
+		case constant.Int:
+			n, _ := constant.Int64Val(c.Val())
+			ev.value, ev.text = n, c.Val().String()
+		case constant.Float:
+			f, _ := constant.Float64Val(c.Val())
+			ev.value, ev.text = f, c.Val().String()
+		default:
+			continue
+		}
+		enum = append(enum, ev)
+	}
+
+	return enum
+}
+
+// constDocs maps the positions of the constant names of a package to their doc or line comments.
+func (b *Builder) constDocs(pkgPath string) map[token.Pos]string {
+	docs := make(map[token.Pos]string)
+	pkg := b.packages[pkgPath]
+	if pkg == nil {
+		return docs
+	}
+
+	pkg.NeedSyntax()
+	for _, file := range pkg.Syntax {
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				doc := vs.Doc
+				if doc == nil {
+					doc = vs.Comment
+				}
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				for _, name := range vs.Names {
+					docs[name.Pos()] = strings.TrimSpace(doc.Text())
+				}
+			}
+		}
+	}
+
+	return docs
+}
+
+func (b *Builder) spec(pkgPath string) *astx.AstSpec {
+	if spec, ok := b.specs[pkgPath]; ok {
+		return spec
+	}
+
+	var spec *astx.AstSpec
+	if pkg := b.packages[pkgPath]; pkg != nil {
+		spec = parser.Parse(pkg)
+	}
+	b.specs[pkgPath] = spec
+
+	return spec
+}
+
+func (b *Builder) structSpec(t *types.Named) *astx.StructSpec {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		return nil
+	}
+	spec := b.spec(obj.Pkg().Path())
+	if spec == nil || spec.Package == nil {
+		return nil
+	}
+	for _, ss := range spec.Package.Structs {
+		if ss.Name == obj.Name() {
+			return ss
+		}
+	}
+
+	return nil
+}
+
+func (b *Builder) typeDoc(t *types.Named) string {
+	if ss := b.structSpec(t); ss != nil {
+		return docText(ss.Doc)
+	}
+
+	obj := t.Obj()
+	if obj.Pkg() == nil || b.packages[obj.Pkg().Path()] == nil {
+		return ""
+	}
+	pkg := b.packages[obj.Pkg().Path()]
+	pkg.NeedSyntax()
+	for _, file := range pkg.Syntax {
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Pos() != obj.Pos() {
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				return strings.TrimSpace(doc.Text())
+			}
+		}
+	}
+
+	return ""
+}
+
+// tags reads a field tag from the field spec, falling back to the type information.
+type tags struct {
+	spec *astx.FieldSpec
+	raw  reflect.StructTag
+}
+
+func (t tags) Get(key string) string {
+	if t.spec == nil {
+		return t.raw.Get(key)
+	}
+	for _, ts := range t.spec.Tags {
+		for _, tag := range ts.Tags {
+			if tag.Key == key {
+				return tag.Value
+			}
+		}
+	}
+
+	return ""
+}
+
+func fieldSpec(ss *astx.StructSpec, name string) *astx.FieldSpec {
+	if ss == nil {
+		return nil
+	}
+	for _, fs := range ss.Fields {
+		if fs.Name == name {
+			return fs
+		}
+	}
+
+	return nil
+}
+
+func docText(doc *astx.DocSpec) string {
+	if doc == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.Join(append([]string{doc.Summary}, doc.Body...), "\n\n"))
+}
+
+func basic(t *types.Basic) *Schema {
+	info := t.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return &Schema{Type: "boolean"}
+	case info&types.IsInteger != 0:
+		s := &Schema{Type: "integer", Format: "int64"}
+		switch t.Kind() {
+		case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16:
+			s.Format = "int32"
+		}
+		if info&types.IsUnsigned != 0 {
+			zero := 0.0
+			s.Minimum = &zero
+		}
+		return s
+	case info&types.IsFloat != 0:
+		if t.Kind() == types.Float32 {
+			return &Schema{Type: "number", Format: "float"}
+		}
+		return &Schema{Type: "number", Format: "double"}
+	case info&types.IsString != 0:
+		return &Schema{Type: "string"}
+	}
+
+	return &Schema{}
+}
+
+func baseName(t *types.Named) string {
+	name := t.Obj().Name()
+	args := t.TypeArgs()
+	for i := 0; args != nil && i < args.Len(); i++ {
+		arg := types.Unalias(args.At(i))
+		for {
+			if ptr, ok := arg.(*types.Pointer); ok {
+				arg = ptr.Elem()
+				continue
+			}
+			break
+		}
+		switch arg := arg.(type) {
+		case *types.Named:
+			name += baseName(arg)
+		default:
+			r, size := utf8.DecodeRuneInString(arg.String())
+			name += string(unicode.ToUpper(r)) + arg.String()[size:]
+		}
+	}
+
+	return name
+}
+
+func qualifiedUnique(defs []*Definition, pkg *types.Package) bool {
+	for _, def := range defs {
+		if other := def.Type.Obj().Pkg(); other != nil && other != pkg && other.Name() == pkg.Name() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasMethod(t *types.Named, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, t.Obj().Pkg(), name)
+	_, ok := obj.(*types.Func)
+
+	return ok
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isByte(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+
+	return ok && basic.Kind() == types.Byte
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/photowey/parsergo/pkg/validatex"
+)
+
+func TestConstrain(t *testing.T) {
+	one, five, sixtyFour := 1, 5, 64
+	zero, ten := 0.0, 10.0
+	tests := []struct {
+		name   string
+		schema *Schema
+		tag    string
+		want   *Schema
+	}{
+		{
+			name:   "Test constrain string length",
+			schema: &Schema{Type: "string"},
+			tag:    "required,min=1,max=64,email",
+			want:   &Schema{Type: "string", Format: "email", MinLength: &one, MaxLength: &sixtyFour},
+		},
+		{
+			name:   "Test constrain exclusive number bounds",
+			schema: &Schema{Type: "integer"},
+			tag:    "gt=0,lte=10",
+			want:   &Schema{Type: "integer", Minimum: &zero, ExclusiveMinimum: true, Maximum: &ten},
+		},
+		{
+			name:   "Test constrain typed enum",
+			schema: &Schema{Type: "integer"},
+			tag:    "oneof=1 5",
+			want:   &Schema{Type: "integer", Enum: []interface{}{int64(1), int64(5)}},
+		},
+		{
+			name:   "Test constrain items after dive",
+			schema: &Schema{Type: "array", Items: &Schema{Type: "string"}},
+			tag:    "max=5,dive,len=1",
+			want:   &Schema{Type: "array", MaxItems: &five, Items: &Schema{Type: "string", MinLength: &one, MaxLength: &one}},
+		},
+		{
+			name:   "Test constrain skips refs",
+			schema: &Schema{Ref: "example.com/pkg.User"},
+			tag:    "min=1",
+			want:   &Schema{Ref: "example.com/pkg.User"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Constrain(tt.schema, validatex.Parse(tt.tag))
+			if !reflect.DeepEqual(tt.schema, tt.want) {
+				t.Errorf("Constrain() = %+v, want %+v", tt.schema, tt.want)
+			}
+		})
+	}
+}
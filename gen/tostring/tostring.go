@@ -0,0 +1,195 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tostring
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+)
+
+const ToStringAnnotation = "ToString"
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    ToStringAnnotation,
+		Targets: []parser.Target{parser.TargetStruct},
+		Help:    "generates the String method of the struct, leaving out some fields: `@ToString(exclude={Password})`",
+	})
+}
+
+// Generator emits a `String() string` method per @ToString struct, rendering `Name{Field: value, ...}`.
+// The locks are left out.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "tostring"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	file := ctx.NewFile()
+	structs := 0
+	for _, ss := range ctx.Spec.Package.Structs {
+		anno := annotation(ss.Annotations)
+		if anno == nil {
+			continue
+		}
+
+		ctx.Package.NeedTypesInfo()
+		obj, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+		if !ok {
+			ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			ctx.Errorf(ss.Position, "%s: a generic struct can't get a String method", ss.Name)
+			continue
+		}
+		if existing, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), "String"); existing != nil {
+			ctx.Errorf(anno.Position, "%s: String is already declared", ss.Name)
+			continue
+		}
+		st := named.Underlying().(*types.Struct)
+
+		excluded := make(map[string]bool)
+		if arg := anno.Arg("exclude"); arg != nil {
+			values := arg.Values
+			if values == nil {
+				values = []string{arg.Value}
+			}
+			for _, name := range values {
+				excluded[name] = true
+			}
+		}
+		fields := make([]*types.Var, 0, st.NumFields())
+		for i := 0; i < st.NumFields(); i++ {
+			if field := st.Field(i); field.Name() != "_" && !excluded[field.Name()] && !gen.Locked(field.Type()) {
+				fields = append(fields, field)
+			}
+			delete(excluded, st.Field(i).Name())
+		}
+		for name := range excluded {
+			ctx.Errorf(anno.Position, "%s: no field %s to exclude", ss.Name, name)
+		}
+		if len(excluded) > 0 {
+			continue
+		}
+
+		structs++
+		recv := unexported(ss.Name[:1])
+		star := ""
+		if gen.Locked(named) {
+			star = "*" // a value receiver would copy the lock
+		}
+		file.Printf("func (%s %s%s) String() string {\n", recv, star, ss.Name)
+		if len(fields) == 0 {
+			file.Printf("return %q\n}\n\n", ss.Name+"{}")
+			continue
+		}
+
+		fmtPkg := file.Imports.NeedImport("fmt")
+		used := map[string]bool{recv: true, fmtPkg: true}
+		verbs := make([]string, 0, len(fields))
+		args := make([]string, 0, len(fields))
+		for _, field := range fields {
+			x := recv + "." + field.Name()
+			elem := pointerToBasic(field.Type())
+			if elem == nil {
+				verbs = append(verbs, field.Name()+": "+verb(field.Type()))
+				args = append(args, x)
+				continue
+			}
+			// the value rather than the address
+			v := local(field.Name(), used)
+			file.Printf("%s := \"<nil>\"\nif %s != nil {\n%s = %s.Sprintf(%q, *%s)\n}\n", v, x, v, fmtPkg, verb(elem), x)
+			verbs = append(verbs, field.Name()+": %s")
+			args = append(args, v)
+		}
+		file.Printf("return %s.Sprintf(%q, %s)\n}\n\n", fmtPkg, ss.Name+"{"+strings.Join(verbs, ", ")+"}", strings.Join(args, ", "))
+	}
+	if structs == 0 {
+		return nil
+	}
+
+	return ctx.AddFile(file)
+}
+
+// verb quotes the strings, without a String method of their own.
+func verb(t types.Type) string {
+	basic, ok := t.Underlying().(*types.Basic)
+	if ok && basic.Info()&types.IsString != 0 {
+		if existing, _, _ := types.LookupFieldOrMethod(t, true, nil, "String"); existing == nil {
+			return "%q"
+		}
+	}
+
+	return "%v"
+}
+
+// pointerToBasic is the element type of a pointer to a basic type without a String method, otherwise nil.
+func pointerToBasic(t types.Type) types.Type {
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	if _, ok := ptr.Elem().Underlying().(*types.Basic); !ok {
+		return nil
+	}
+	if existing, _, _ := types.LookupFieldOrMethod(t, true, nil, "String"); existing != nil {
+		return nil
+	}
+
+	return ptr.Elem()
+}
+
+// local names the variable of a field, unused so far.
+func local(field string, used map[string]bool) string {
+	name := unexported(field)
+	for token.IsKeyword(name) || used[name] {
+		name += "_"
+	}
+	used[name] = true
+
+	return name
+}
+
+func annotation(annotations []*astx.Annotation) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == ToStringAnnotation && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
@@ -0,0 +1,331 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/pkg/validatex"
+)
+
+type kind int
+
+const (
+	kindOther kind = iota
+	kindString
+	kindInt
+	kindUint
+	kindFloat
+	kindBool
+	kindLen // slices, arrays and maps, checked by their length
+)
+
+func kindOf(t types.Type) kind {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case info&types.IsString != 0:
+			return kindString
+		case info&types.IsUnsigned != 0:
+			return kindUint
+		case info&types.IsInteger != 0:
+			return kindInt
+		case info&types.IsFloat != 0:
+			return kindFloat
+		case info&types.IsBoolean != 0:
+			return kindBool
+		}
+	case *types.Slice, *types.Array, *types.Map:
+		return kindLen
+	}
+
+	return kindOther
+}
+
+// check is a rule rendered as the condition of its failure.
+type check struct {
+	Rule    string
+	Cond    string
+	Message string
+}
+
+// predicates are the string rules checked by a func of validatex.
+var predicates = map[string]struct {
+	fn      string
+	message string
+}{
+	"email":    {fn: "IsEmail", message: "must be a valid email"},
+	"url":      {fn: "IsURL", message: "must be a valid URL"},
+	"uri":      {fn: "IsURI", message: "must be a valid URI"},
+	"uuid":     {fn: "IsUUID", message: "must be a valid UUID"},
+	"alpha":    {fn: "IsAlpha", message: "must contain only letters"},
+	"alphanum": {fn: "IsAlphanumeric", message: "must contain only letters and digits"},
+	"numeric":  {fn: "IsNumeric", message: "must be numeric"},
+	"hostname": {fn: "IsHostname", message: "must be a valid hostname"},
+	"ip":       {fn: "IsIP", message: "must be a valid IP address"},
+	"ipv4":     {fn: "IsIPv4", message: "must be a valid IPv4 address"},
+	"ipv6":     {fn: "IsIPv6", message: "must be a valid IPv6 address"},
+}
+
+// comparisons are the operators failing the bound rules, and their messages by kind: value, characters and items.
+var comparisons = map[string]struct {
+	op       string
+	messages [3]string
+}{
+	"min": {op: "<", messages: [3]string{"must be at least %s", "must be at least %s characters long", "must contain at least %s items"}},
+	"gte": {op: "<", messages: [3]string{"must be at least %s", "must be at least %s characters long", "must contain at least %s items"}},
+	"max": {op: ">", messages: [3]string{"must be at most %s", "must be at most %s characters long", "must contain at most %s items"}},
+	"lte": {op: ">", messages: [3]string{"must be at most %s", "must be at most %s characters long", "must contain at most %s items"}},
+	"gt":  {op: "<=", messages: [3]string{"must be greater than %s", "must be longer than %s characters", "must contain more than %s items"}},
+	"lt":  {op: ">=", messages: [3]string{"must be less than %s", "must be shorter than %s characters", "must contain less than %s items"}},
+	"len": {op: "!=", messages: [3]string{"must be %s", "must be %s characters long", "must contain %s items"}},
+}
+
+// rule renders the check of a rule of x, a value of type t, failing the rules which don't fit the type.
+func (w *writer) rule(r *validatex.Rule, x string, t types.Type) (*check, error) {
+	if strings.Contains(r.Name, "|") {
+		conds := make([]string, 0)
+		messages := make([]string, 0)
+		for _, alternative := range strings.Split(r.Name, "|") {
+			alt := &validatex.Rule{Name: alternative}
+			if eq := strings.IndexByte(alternative, '='); eq > 0 {
+				alt.Name, alt.Param = alternative[:eq], alternative[eq+1:]
+			}
+			if alt.Name == "required" || alt.Name == "omitempty" || alt.Name == "dive" {
+				return nil, fmt.Errorf("%s can't be an alternative", alt.Name)
+			}
+			c, err := w.rule(alt, x, t)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, c.Cond)
+			messages = append(messages, c.Message)
+		}
+		return &check{Rule: r.Name, Cond: strings.Join(conds, " && "), Message: strings.Join(messages, " or ")}, nil
+	}
+
+	k := kindOf(t)
+	unfit := fmt.Errorf("%s doesn't apply to a %s", r.Name, t)
+	switch name := r.Name; {
+	case name == "required":
+		cond, ok := w.zero(x, t)
+		if !ok {
+			return nil, unfit
+		}
+		return &check{Rule: name, Cond: cond, Message: "is required"}, nil
+	case comparisons[name].op != "":
+		cmp := comparisons[name]
+		switch k {
+		case kindInt, kindUint, kindFloat:
+			value, err := number(k, t, r.Param)
+			if err != nil {
+				return nil, err
+			}
+			return &check{Rule: r.String(), Cond: x + " " + cmp.op + " " + value, Message: fmt.Sprintf(cmp.messages[0], value)}, nil
+		case kindString, kindLen:
+			n, err := strconv.Atoi(r.Param)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s needs a length, not %q", name, r.Param)
+			}
+			length := "len(" + x + ")"
+			message := cmp.messages[2]
+			if k == kindString {
+				length = w.file.Imports.NeedImport("unicode/utf8") + ".RuneCountInString(" + w.str(x, t) + ")"
+				message = cmp.messages[1]
+			}
+			return &check{Rule: r.String(), Cond: length + " " + cmp.op + " " + strconv.Itoa(n), Message: fmt.Sprintf(message, strconv.Itoa(n))}, nil
+		}
+		return nil, unfit
+	case name == "eq" || name == "ne":
+		op, message := "!=", "must be %s"
+		if name == "ne" {
+			op, message = "==", "must not be %s"
+		}
+		var value string
+		switch k {
+		case kindInt, kindUint, kindFloat:
+			var err error
+			if value, err = number(k, t, r.Param); err != nil {
+				return nil, err
+			}
+		case kindString:
+			value = strconv.Quote(r.Param)
+		case kindBool:
+			b, err := strconv.ParseBool(r.Param)
+			if err != nil {
+				return nil, fmt.Errorf("%s needs a bool, not %q", name, r.Param)
+			}
+			cond := x
+			if b == (name == "eq") {
+				cond = "!" + x
+			}
+			return &check{Rule: r.String(), Cond: cond, Message: fmt.Sprintf(message, r.Param)}, nil
+		default:
+			return nil, unfit
+		}
+		return &check{Rule: r.String(), Cond: x + " " + op + " " + value, Message: fmt.Sprintf(message, r.Param)}, nil
+	case name == "oneof":
+		values := r.Values()
+		if len(values) == 0 {
+			return nil, fmt.Errorf("oneof needs values")
+		}
+		conds := make([]string, 0, len(values))
+		for _, value := range values {
+			switch k {
+			case kindString:
+				value = strconv.Quote(value)
+			case kindInt, kindUint:
+				var err error
+				if value, err = number(k, t, value); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, unfit
+			}
+			conds = append(conds, x+" != "+value)
+		}
+		return &check{Rule: r.String(), Cond: strings.Join(conds, " && "), Message: "must be one of " + strings.Join(values, ", ")}, nil
+	case predicates[name].fn != "":
+		if k != kindString {
+			return nil, unfit
+		}
+		validatexPkg := w.file.Imports.NeedImport(validatexPath)
+		return &check{Rule: name, Cond: "!" + validatexPkg + "." + predicates[name].fn + "(" + w.str(x, t) + ")", Message: predicates[name].message}, nil
+	case name == "lowercase" || name == "uppercase":
+		if k != kindString {
+			return nil, unfit
+		}
+		fn := "ToLower"
+		if name == "uppercase" {
+			fn = "ToUpper"
+		}
+		return &check{Rule: name, Cond: w.str(x, t) + " != " + w.file.Imports.NeedImport("strings") + "." + fn + "(" + w.str(x, t) + ")", Message: "must be " + name}, nil
+	case name == "contains" || name == "excludes" || name == "startswith" || name == "endswith":
+		if k != kindString {
+			return nil, unfit
+		}
+		fn, not, message := map[string]string{"contains": "Contains", "excludes": "Contains", "startswith": "HasPrefix", "endswith": "HasSuffix"}[name], "!", ""
+		switch name {
+		case "contains":
+			message = "must contain %q"
+		case "excludes":
+			not, message = "", "must not contain %q"
+		case "startswith":
+			message = "must start with %q"
+		case "endswith":
+			message = "must end with %q"
+		}
+		cond := not + w.file.Imports.NeedImport("strings") + "." + fn + "(" + w.str(x, t) + ", " + strconv.Quote(r.Param) + ")"
+		return &check{Rule: r.String(), Cond: cond, Message: fmt.Sprintf(message, r.Param)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rule %q", r.Name)
+}
+
+// zero renders the check of the zero value of x, for the types with one.
+func (w *writer) zero(x string, t types.Type) (string, bool) {
+	switch kindOf(t) {
+	case kindString:
+		return x + ` == ""`, true
+	case kindInt, kindUint, kindFloat:
+		return x + " == 0", true
+	case kindBool:
+		return "!" + x, true
+	}
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return "len(" + x + ") == 0", true
+	case *types.Pointer, *types.Interface, *types.Chan, *types.Signature:
+		return x + " == nil", true
+	case *types.Struct, *types.Array:
+		// e.g. time.Time, zero by its own IsZero
+		if isZeroMethod(t) {
+			return x + ".IsZero()", true
+		}
+		if types.Comparable(t) {
+			return x + " == (" + w.typeName(t) + "{})", true
+		}
+	}
+
+	return "", false
+}
+
+// isZeroMethod reports whether t has an `IsZero() bool` method.
+func isZeroMethod(t types.Type) bool {
+	sel := types.NewMethodSet(t).Lookup(nil, "IsZero")
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool])
+}
+
+func (w *writer) typeName(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return w.file.Imports.NeedImport(pkg.Path())
+	})
+}
+
+// str converts x to a string for the funcs of the strings and validatex packages.
+func (w *writer) str(x string, t types.Type) string {
+	if types.Identical(t, types.Typ[types.String]) {
+		return x
+	}
+
+	return "string(" + x + ")"
+}
+
+// number parses the param of a numeric rule as a constant of type t.
+func number(k kind, t types.Type, param string) (string, error) {
+	bits := 64
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		switch basic.Kind() {
+		case types.Int8, types.Uint8:
+			bits = 8
+		case types.Int16, types.Uint16:
+			bits = 16
+		case types.Int32, types.Uint32, types.Float32:
+			bits = 32
+		}
+	}
+
+	switch k {
+	case kindInt:
+		n, err := strconv.ParseInt(param, 0, bits)
+		if err == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+	case kindUint:
+		n, err := strconv.ParseUint(param, 0, bits)
+		if err == nil {
+			return strconv.FormatUint(n, 10), nil
+		}
+	case kindFloat:
+		f, err := strconv.ParseFloat(param, bits)
+		if err == nil {
+			return strconv.FormatFloat(f, 'g', -1, bits), nil
+		}
+	}
+
+	return "", fmt.Errorf("%q is not a valid %s", param, t)
+}
@@ -0,0 +1,424 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/gen"
+	"github.com/photowey/parsergo/parser"
+	"github.com/photowey/parsergo/pkg/validatex"
+)
+
+const (
+	ValidateAnnotation = "Validate"
+
+	validatexPath = "github.com/photowey/parsergo/pkg/validatex"
+)
+
+func init() {
+	parser.RegisterMarker(&parser.MarkerDefinition{
+		Name:    ValidateAnnotation,
+		Targets: []parser.Target{parser.TargetStruct},
+		Help:    "generates the `Validate() error` method of the struct, checking the `validate` tags of its fields: `@Validate`",
+	})
+}
+
+// Generator emits a `Validate() error` method per @Validate struct, checking the go-playground/validator `validate` tags
+// of its fields. The rules are checked against the field types on generation, unknown or unfit rules failing it.
+// The nested values are validated through the slices, arrays, maps and pointers, the structs of the package needing it
+// getting the method too, the other types by their own Validate method, if any. The errors are a validatex.Errors.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (g *Generator) Name() string {
+	return "validate"
+}
+
+func (g *Generator) Generate(ctx *gen.Context) error {
+	annotated := make([]*astx.StructSpec, 0)
+	for _, ss := range ctx.Spec.Package.Structs {
+		if annotation(ss.Annotations) != nil {
+			annotated = append(annotated, ss)
+		}
+	}
+	if len(annotated) == 0 {
+		return nil
+	}
+
+	ctx.Package.NeedTypesInfo()
+	w := &writer{ctx: ctx, targets: make(map[*types.Named]bool)}
+	for _, ss := range annotated {
+		obj, ok := ctx.Package.Types.Scope().Lookup(ss.Name).(*types.TypeName)
+		if !ok {
+			ctx.Errorf(ss.Position, "%s: missing type information", ss.Name)
+			continue
+		}
+		t, ok := obj.Type().(*types.Named)
+		if !ok || t.TypeParams().Len() > 0 {
+			ctx.Errorf(ss.Position, "%s: a generic struct can't get a Validate method", ss.Name)
+			continue
+		}
+		if validator(t) {
+			ctx.Errorf(ss.Position, "%s: Validate is already declared", ss.Name)
+			continue
+		}
+		w.targets[t] = true
+		w.queue = append(w.queue, t)
+	}
+	if len(w.queue) == 0 {
+		return nil
+	}
+
+	w.file = ctx.NewFile()
+	// the queue grows with the nested structs needing the method
+	for i := 0; i < len(w.queue); i++ {
+		w.generate(w.queue[i])
+	}
+
+	return ctx.AddFile(w.file)
+}
+
+type writer struct {
+	ctx     *gen.Context
+	file    *gen.File
+	targets map[*types.Named]bool // the types getting the method
+	queue   []*types.Named
+	spec    *astx.StructSpec // the struct being generated
+}
+
+func (w *writer) generate(named *types.Named) {
+	w.spec = &astx.StructSpec{Name: named.Obj().Name(), Position: astx.NewPosition(w.ctx.Package.Fset, named.Obj().Pos(), 0)}
+	for _, ss := range w.ctx.Spec.Package.Structs {
+		if ss.Name == named.Obj().Name() {
+			w.spec = ss
+		}
+	}
+
+	recv := unexported(w.spec.Name[:1])
+	switch recv {
+	case "i", "j", "k", "l":
+		recv = "x" // the loop indexes
+	}
+	body := new(strings.Builder)
+	w.fields(body, recv, "", named.Underlying().(*types.Struct))
+
+	w.file.Printf("// Validate checks the `validate` rules of the fields of the %s.\n", w.spec.Name)
+	w.file.Printf("func (%s *%s) Validate() error {\nif %s == nil {\nreturn nil\n}\n\n", recv, w.spec.Name, recv)
+	w.file.Printf("var errs %s.Errors\n%s\nreturn errs.Err()\n}\n\n", w.file.Imports.NeedImport(validatexPath), body)
+}
+
+// fields renders the checks of the fields of x, a struct at path.
+func (w *writer) fields(b *strings.Builder, x, path string, st *types.Struct) {
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("validate")
+		if v.Name() == "_" || tag == "-" {
+			continue
+		}
+		position := w.spec.Position
+		for _, fs := range w.spec.Fields {
+			if fs.Name == v.Name() {
+				position = fs.Position
+			}
+		}
+		if err := w.field(b, x+"."+v.Name(), w.join(path, v.Name()), v.Type(), validatex.Parse(tag), 0); err != nil {
+			w.ctx.Errorf(position, "%s.%s: %v", w.spec.Name, v.Name(), err)
+		}
+	}
+}
+
+// field renders the checks of x, an addressable value of type t at path, against the rules.
+func (w *writer) field(b *strings.Builder, x, path string, t types.Type, rules []*validatex.Rule, depth int) error {
+	outer, inner := validatex.Dive(rules)
+	if inner != nil {
+		switch t.Underlying().(type) {
+		case *types.Pointer, *types.Slice, *types.Array, *types.Map:
+		default:
+			return fmt.Errorf("dive doesn't apply to a %s", t)
+		}
+	}
+
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		elem := new(strings.Builder)
+		if err := w.field(elem, deref(x, ptr.Elem()), path, ptr.Elem(), strip(rules, "required", "omitempty"), depth); err != nil {
+			return err
+		}
+		switch {
+		case validatex.Find(outer, "required") != nil:
+			fmt.Fprintf(b, "if %s == nil {\nerrs.Add(%s, \"required\", \"is required\")\n}", x, path)
+			if elem.Len() > 0 {
+				fmt.Fprintf(b, " else {\n%s}", elem)
+			}
+			b.WriteString("\n")
+		case elem.Len() > 0:
+			fmt.Fprintf(b, "if %s != nil {\n%s}\n", x, elem)
+		}
+		return nil
+	}
+
+	omitempty := ""
+	checks := make([]*check, 0)
+	for _, rule := range outer {
+		if rule.Name == "omitempty" {
+			cond, ok := w.zero(x, t)
+			if !ok {
+				return fmt.Errorf("omitempty doesn't apply to a %s", t)
+			}
+			omitempty = negate(cond)
+			continue
+		}
+		c, err := w.rule(rule, x, t)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, c)
+	}
+
+	body := new(strings.Builder)
+	switch len(checks) {
+	case 0:
+	case 1:
+		fmt.Fprintf(body, "if %s {\n%s}\n", checks[0].Cond, add(path, checks[0]))
+	default:
+		// the first failed rule is reported
+		body.WriteString("switch {\n")
+		for _, c := range checks {
+			fmt.Fprintf(body, "case %s:\n%s", c.Cond, add(path, c))
+		}
+		body.WriteString("}\n")
+	}
+	if err := w.nested(body, x, path, t, inner, depth); err != nil {
+		return err
+	}
+
+	if omitempty != "" && body.Len() > 0 {
+		fmt.Fprintf(b, "if %s {\n%s}\n", omitempty, body)
+		return nil
+	}
+	b.WriteString(body.String())
+
+	return nil
+}
+
+// nested renders the validation of the elements, entries and fields of x, the elements taking the rules after a dive.
+func (w *writer) nested(b *strings.Builder, x, path string, t types.Type, rules []*validatex.Rule, depth int) error {
+	if w.validates(t) {
+		if strings.HasPrefix(x, "*") {
+			x = "(" + x + ")"
+		}
+		fmt.Fprintf(b, "if err := %s.Validate(); err != nil {\nerrs.Nest(%s, err)\n}\n", x, path)
+		return nil
+	}
+
+	validatexPkg := w.file.Imports.NeedImport(validatexPath)
+	elems := new(strings.Builder)
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		elem := u.(interface{ Elem() types.Type }).Elem()
+		i := index(depth)
+		if err := w.field(elems, x+"["+i+"]", validatexPkg+".Index("+path+", "+i+")", elem, rules, depth+1); err != nil {
+			return err
+		}
+		if elems.Len() > 0 {
+			fmt.Fprintf(b, "for %s := range %s {\n%s}\n", i, x, elems)
+		}
+	case *types.Map:
+		n := strconv.Itoa(depth + 1)
+		if err := w.field(elems, "value"+n, validatexPkg+".Key("+path+", key"+n+")", u.Elem(), rules, depth+1); err != nil {
+			return err
+		}
+		if elems.Len() > 0 {
+			fmt.Fprintf(b, "for key%s, value%s := range %s {\n%s}\n", n, n, x, elems)
+		}
+	case *types.Struct:
+		if _, ok := t.(*types.Named); !ok {
+			w.fields(b, x, path, u)
+		}
+	}
+
+	return nil
+}
+
+// validates reports whether t has a Validate method, declared or generated,
+// queuing the structs of the package needing one.
+func (w *writer) validates(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	if w.targets[named] || validator(named) {
+		return true
+	}
+	if named.Obj().Pkg() != w.ctx.Package.Types || named.TypeParams().Len() > 0 {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok || !w.needs(named, make(map[*types.Named]bool)) {
+		return false
+	}
+	w.targets[named] = true
+	w.queue = append(w.queue, named)
+
+	return true
+}
+
+// needs reports whether a value of type t has anything to validate.
+func (w *writer) needs(t types.Type, seen map[*types.Named]bool) bool {
+	if named, ok := t.(*types.Named); ok {
+		if w.targets[named] || validator(named) {
+			return true
+		}
+		if seen[named] || named.Obj().Pkg() != w.ctx.Package.Types {
+			return false
+		}
+		seen[named] = true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return w.needs(u.Elem(), seen)
+	case *types.Slice:
+		return w.needs(u.Elem(), seen)
+	case *types.Array:
+		return w.needs(u.Elem(), seen)
+	case *types.Map:
+		return w.needs(u.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			tag := reflect.StructTag(u.Tag(i)).Get("validate")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" || w.needs(u.Field(i).Type(), seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// join renders the path of a field of the value at path, literal at the top.
+func (w *writer) join(path, name string) string {
+	switch {
+	case path == "":
+		return strconv.Quote(name)
+	case strings.HasPrefix(path, `"`):
+		return strconv.Quote(path[1:len(path)-1] + "." + name)
+	}
+
+	return w.file.Imports.NeedImport(validatexPath) + ".Field(" + path + ", " + strconv.Quote(name) + ")"
+}
+
+// validator reports whether the type has a `Validate() error` method.
+func validator(named *types.Named) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), "Validate")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+
+	return sig.Params().Len() == 0 && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+func add(path string, c *check) string {
+	return fmt.Sprintf("errs.Add(%s, %s, %s)\n", path, strconv.Quote(c.Rule), strconv.Quote(c.Message))
+}
+
+// strip removes the named rules of the value itself, before any dive.
+func strip(rules []*validatex.Rule, names ...string) []*validatex.Rule {
+	stripped := make([]*validatex.Rule, 0, len(rules))
+	diving := false
+	for _, rule := range rules {
+		diving = diving || rule.Name == "dive"
+		if !diving && contains(names, rule.Name) {
+			continue
+		}
+		stripped = append(stripped, rule)
+	}
+
+	return stripped
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deref renders the pointee of x, leaving the pointer of a struct to the selectors and the method calls.
+func deref(x string, elem types.Type) string {
+	switch elem.Underlying().(type) {
+	case *types.Struct:
+		return x
+	case *types.Basic:
+		return "*" + x
+	}
+
+	return "(*" + x + ")"
+}
+
+// negate turns the zero check of a value into the non-zero one.
+func negate(cond string) string {
+	switch {
+	case strings.HasPrefix(cond, "!"):
+		return cond[1:]
+	case strings.Contains(cond, " == "):
+		return strings.Replace(cond, " == ", " != ", 1)
+	}
+
+	return "!" + cond
+}
+
+func index(depth int) string {
+	if depth < 4 {
+		return string("ijkl"[depth])
+	}
+
+	return "i" + strconv.Itoa(depth)
+}
+
+func annotation(annotations []*astx.Annotation) *astx.Annotation {
+	for _, anno := range annotations {
+		if anno.Name == ValidateAnnotation && !anno.Marker {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
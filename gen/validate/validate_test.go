@@ -0,0 +1,64 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/photowey/parsergo/gen/gentest"
+)
+
+func TestGenerator(t *testing.T) {
+	gentest.Run(t, New,
+		gentest.Case{
+			Name:      "checkx",
+			Pkg:       "github.com/photowey/parsergo/tests/checkx",
+			WantFiles: []string{"checkx/zz_generated.validate.go"},
+		},
+		gentest.Case{
+			Name: "invalid",
+			Pkg:  "github.com/photowey/parsergo/tests/checkx/invalidx",
+			WantDiags: []string{
+				"Checked: Validate is already declared",
+				`Form.Name: unknown rule "shiny"`,
+				"Form.Active: min doesn't apply to a bool",
+				`Form.Count: "many" is not a valid int`,
+				"Form.Period: required doesn't apply to a struct{Days []int}",
+				"Form.Level: dive doesn't apply to a int",
+			},
+		},
+	)
+}
+
+func TestNegate(t *testing.T) {
+	tests := []struct {
+		cond string
+		want string
+	}{
+		{cond: "!v.Active", want: "v.Active"},
+		{cond: `v.Name == ""`, want: `v.Name != ""`},
+		{cond: "v.Origin == (Point{})", want: "v.Origin != (Point{})"},
+		{cond: "v.Born.IsZero()", want: "!v.Born.IsZero()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			if got := negate(tt.cond); got != tt.want {
+				t.Errorf("negate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,27 +21,42 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"sync"
 
 	"github.com/photowey/parsergo/sets"
 	"golang.org/x/tools/go/packages"
 )
 
+// IgnoreAutogeneratedTag is the build tag passed to `go list` unless the config carries its own `-tags` flag.
+const IgnoreAutogeneratedTag = "ignore_autogenerated"
+
+const (
+	TestVariantNone     = ""
+	TestVariantInternal = "test"
+	TestVariantExternal = "xtest"
+)
+
 type Package struct {
 	*packages.Package
-	imports map[string]*Package
-	loader  *loader
+	imports  map[string]*Package
+	loader   *loader
+	errorsMu sync.Mutex
+	typesMu  sync.Mutex
 	sync.Mutex
 }
 
 type loader struct {
-	Roots      []*Package
-	conf       *packages.Config
-	packages   map[*packages.Package]*Package
-	packagesMu sync.Mutex
+	Roots        []*Package
+	conf         *packages.Config
+	packages     map[*packages.Package]*Package
+	packagesMu   sync.Mutex
+	importer     types.Importer
+	importerOnce sync.Once
 }
 
 func LoadRoots(rootPaths ...string) ([]*Package, error) {
@@ -53,11 +68,13 @@ func LoadRootsWithConfig(conf *packages.Config, roots ...string) ([]*Package, er
 		conf:     conf,
 		packages: make(map[*packages.Package]*Package),
 	}
-	ldr.conf.Mode |= packages.LoadImports | packages.NeedTypesSizes
+	ldr.conf.Mode |= packages.LoadImports | packages.NeedTypesSizes | packages.NeedModule
 	if ldr.conf.Fset == nil {
 		ldr.conf.Fset = token.NewFileSet()
 	}
-	ldr.conf.BuildFlags = append([]string{"-tags", "ignore_autogenerated"}, ldr.conf.BuildFlags...)
+	if !hasTagsFlag(ldr.conf.BuildFlags) {
+		ldr.conf.BuildFlags = append([]string{"-tags", IgnoreAutogeneratedTag}, ldr.conf.BuildFlags...)
+	}
 
 	uniquePkgIDs := sets.NewString()
 
@@ -193,6 +210,89 @@ func (p *Package) Imports() map[string]*Package {
 	return p.imports
 }
 
+func (p *Package) NeedSyntax() {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.Syntax != nil {
+		return
+	}
+
+	if p.Fset == nil {
+		p.Fset = p.loader.conf.Fset
+	}
+
+	out := make([]*ast.File, len(p.CompiledGoFiles))
+	var wg sync.WaitGroup
+	wg.Add(len(p.CompiledGoFiles))
+	for i, filename := range p.CompiledGoFiles {
+		go func(i int, filename string) {
+			defer wg.Done()
+			src, err := p.loader.readFile(filename)
+			if err != nil {
+				p.AddError(err)
+				return
+			}
+			file, err := p.loader.parseFile(filename, src)
+			if err != nil {
+				p.AddError(err)
+				return
+			}
+			out[i] = file
+		}(i, filename)
+	}
+	wg.Wait()
+
+	p.Syntax = out
+}
+
+func (p *Package) SyntaxFor(filename string) *ast.File {
+	p.NeedSyntax()
+	for i, cf := range p.CompiledGoFiles {
+		if cf == filename && i < len(p.Syntax) {
+			return p.Syntax[i]
+		}
+	}
+
+	return nil
+}
+
+func (p *Package) AddError(err error) {
+	p.errorsMu.Lock()
+	defer p.errorsMu.Unlock()
+
+	p.Errors = append(p.Errors, packages.Error{
+		Msg:  err.Error(),
+		Kind: packages.UnknownError,
+	})
+}
+
+// TestVariant reports whether the package is the in-package test variant `p [p.test]`,
+// the external test package `p_test [p.test]`, or neither.
+func (p *Package) TestVariant() string {
+	if !strings.HasSuffix(p.ID, ".test]") {
+		return TestVariantNone
+	}
+	if strings.HasSuffix(p.PkgPath, "_test") {
+		return TestVariantExternal
+	}
+
+	return TestVariantInternal
+}
+
+// IsTestMain reports whether the package is the synthesized `p.test` main package.
+func (p *Package) IsTestMain() bool {
+	return p.Name == "main" && strings.HasSuffix(p.ID, ".test")
+}
+
+func (l *loader) readFile(filename string) ([]byte, error) {
+	if src, ok := l.conf.Overlay[filename]; ok {
+		return src, nil
+	}
+
+	return os.ReadFile(filename)
+}
+
 func (l *loader) parseFile(filename string, src []byte) (*ast.File, error) {
 	file, err := parser.ParseFile(l.conf.Fset, filename, src, parser.AllErrors|parser.ParseComments)
 	if err != nil {
@@ -222,3 +322,13 @@ func (l *loader) packagesFor(pkgsRaw map[string]*packages.Package) map[string]*P
 	}
 	return out
 }
+
+func hasTagsFlag(flags []string) bool {
+	for _, flag := range flags {
+		if flag == "-tags" || flag == "--tags" || strings.HasPrefix(flag, "-tags=") || strings.HasPrefix(flag, "--tags=") {
+			return true
+		}
+	}
+
+	return false
+}
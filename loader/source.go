@@ -0,0 +1,94 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadFile loads a single Go file as a standalone package without running `go list`.
+// The overlay, if any, takes precedence over the content on disk.
+func LoadFile(filename string, overlay map[string][]byte) (*Package, error) {
+	return LoadSource(filename, nil, overlay)
+}
+
+// LoadSource loads the in-memory src as a standalone package named after the file's package clause.
+// When src is nil, the content is read from the overlay or from disk.
+func LoadSource(filename string, src []byte, overlay map[string][]byte) (*Package, error) {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+
+	ldr := &loader{
+		conf: &packages.Config{
+			Fset:    token.NewFileSet(),
+			Overlay: overlay,
+		},
+		packages: make(map[*packages.Package]*Package),
+	}
+
+	if src == nil {
+		bytes, err := ldr.readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		src = bytes
+	}
+
+	file, err := ldr.parseFile(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	rawPkg := &packages.Package{
+		ID:              filename,
+		Name:            file.Name.Name,
+		PkgPath:         file.Name.Name,
+		GoFiles:         []string{filename},
+		CompiledGoFiles: []string{filename},
+		Fset:            ldr.conf.Fset,
+		Syntax:          []*ast.File{file},
+	}
+
+	pkg := ldr.packageFor(rawPkg)
+	ldr.Roots = append(ldr.Roots, pkg)
+
+	return pkg, nil
+}
+
+// FromPackage wraps a package whose Syntax, and possibly Types and TypesInfo, are already populated,
+// e.g. one built from a go/analysis pass. Its imports are resolved from rawPkg.Imports only.
+func FromPackage(rawPkg *packages.Package) *Package {
+	ldr := &loader{
+		conf: &packages.Config{
+			Fset: rawPkg.Fset,
+		},
+		packages: make(map[*packages.Package]*Package),
+	}
+	if ldr.conf.Fset == nil {
+		ldr.conf.Fset = token.NewFileSet()
+	}
+
+	pkg := ldr.packageFor(rawPkg)
+	ldr.Roots = append(ldr.Roots, pkg)
+
+	return pkg
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019-2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/types"
+	"runtime"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// NeedTypesInfo type-checks the package from source, its dependencies included,
+// so Types and TypesInfo are available. Function bodies are only checked for root packages.
+func (p *Package) NeedTypesInfo() {
+	p.typesMu.Lock()
+	defer p.typesMu.Unlock()
+
+	if p.TypesInfo != nil {
+		return
+	}
+
+	p.NeedSyntax()
+	p.loader.typeCheck(p)
+}
+
+func (l *loader) typeCheck(pkg *Package) {
+	imports := pkg.Imports()
+	importer := importerFunc(func(path string) (*types.Package, error) {
+		if path == "unsafe" {
+			return types.Unsafe, nil
+		}
+
+		importedPkg := imports[path]
+		if importedPkg == nil {
+			return l.fallbackImporter().Import(path)
+		}
+
+		importedPkg.NeedTypesInfo()
+		if importedPkg.Types == nil {
+			return nil, fmt.Errorf("no type information for package %s", path)
+		}
+
+		return importedPkg.Types, nil
+	})
+
+	files := make([]*ast.File, 0, len(pkg.Syntax))
+	for _, file := range pkg.Syntax {
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	typeErrors := 0
+	cfg := &types.Config{
+		Importer:         importer,
+		IgnoreFuncBodies: !l.isRoot(pkg),
+		Sizes:            sizesOf(pkg),
+		Error: func(err error) {
+			pkg.errorsMu.Lock()
+			defer pkg.errorsMu.Unlock()
+
+			typeErrors++
+			pkg.Errors = append(pkg.Errors, packages.Error{
+				Msg:  err.Error(),
+				Kind: packages.TypeError,
+			})
+		},
+	}
+
+	pkg.TypesInfo = &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	pkg.Types = types.NewPackage(pkg.PkgPath, pkg.Name)
+
+	// type errors were recorded above, the partial information is still useful
+	_ = types.NewChecker(cfg, pkg.Fset, pkg.Types, pkg.TypesInfo).Files(files)
+	pkg.IllTyped = typeErrors > 0
+}
+
+func sizesOf(pkg *Package) types.Sizes {
+	if std, ok := pkg.TypesSizes.(*types.StdSizes); pkg.TypesSizes == nil || ok && std == nil {
+		return types.SizesFor("gc", runtime.GOARCH)
+	}
+
+	return pkg.TypesSizes
+}
+
+func (l *loader) isRoot(pkg *Package) bool {
+	for _, root := range l.Roots {
+		if root == pkg {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fallbackImporter resolves imports the loader knows nothing about, e.g. those of a standalone file.
+func (l *loader) fallbackImporter() types.Importer {
+	l.importerOnce.Do(func() {
+		l.importer = importer.ForCompiler(l.conf.Fset, "source", nil)
+	})
+
+	return l.importer
+}
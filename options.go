@@ -0,0 +1,123 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parsergo
+
+import (
+	"os"
+	"strings"
+
+	"github.com/photowey/parsergo/loader"
+	"github.com/photowey/parsergo/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+type Option func(scr *scanner)
+
+func WithPaths(rootPaths ...string) Option {
+	return func(scr *scanner) {
+		scr.Paths = append(scr.Paths, rootPaths...)
+	}
+}
+
+func WithBuildTags(tags ...string) Option {
+	return func(scr *scanner) {
+		scr.tags = append(scr.tags, tags...)
+	}
+}
+
+// WithIgnoreAutogenerated toggles the `ignore_autogenerated` build tag, which is on by default.
+func WithIgnoreAutogenerated(ignore bool) Option {
+	return func(scr *scanner) {
+		scr.scanAutogenerated = !ignore
+	}
+}
+
+func WithGOOS(goos string) Option {
+	return func(scr *scanner) {
+		scr.goos = goos
+	}
+}
+
+func WithGOARCH(goarch string) Option {
+	return func(scr *scanner) {
+		scr.goarch = goarch
+	}
+}
+
+// WithEnv appends `KEY=value` entries to the environment of the underlying `go list`.
+func WithEnv(env ...string) Option {
+	return func(scr *scanner) {
+		scr.env = append(scr.env, env...)
+	}
+}
+
+func WithDir(dir string) Option {
+	return func(scr *scanner) {
+		scr.dir = dir
+	}
+}
+
+func WithTests(tests bool) Option {
+	return func(scr *scanner) {
+		scr.tests = tests
+	}
+}
+
+func WithOverlay(overlay map[string][]byte) Option {
+	return func(scr *scanner) {
+		if scr.overlay == nil {
+			scr.overlay = make(map[string][]byte, len(overlay))
+		}
+		for name, content := range overlay {
+			scr.overlay[name] = content
+		}
+	}
+}
+
+// WithParser scans with psr, e.g. a parser.NewParser() with extra handlers, instead of the process-wide parser.Default().
+func WithParser(psr parser.Parser) Option {
+	return func(scr *scanner) {
+		scr.parser = psr
+	}
+}
+
+func (scr *scanner) config() *packages.Config {
+	tags := make([]string, 0, len(scr.tags)+1)
+	if !scr.scanAutogenerated {
+		tags = append(tags, loader.IgnoreAutogeneratedTag)
+	}
+	tags = append(tags, scr.tags...)
+
+	conf := &packages.Config{
+		Dir:        scr.dir,
+		Tests:      scr.tests,
+		Overlay:    scr.overlay,
+		BuildFlags: []string{"-tags=" + strings.Join(tags, ",")},
+	}
+
+	if len(scr.env) > 0 || scr.goos != "" || scr.goarch != "" {
+		conf.Env = append(os.Environ(), scr.env...)
+		if scr.goos != "" {
+			conf.Env = append(conf.Env, "GOOS="+scr.goos)
+		}
+		if scr.goarch != "" {
+			conf.Env = append(conf.Env, "GOARCH="+scr.goarch)
+		}
+	}
+
+	return conf
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+func init() {
+	RegisterMarker(
+		&MarkerDefinition{
+			Name:    "Component",
+			Targets: []Target{TargetStruct},
+			Help:    "a managed component, optionally named: `@Component(\"name\")`",
+		},
+		&MarkerDefinition{
+			Name:    "Service",
+			Targets: []Target{TargetStruct},
+			Help:    "a service component, optionally named and checked against its interface: `@Service(\"name\", interface=Api)`",
+		},
+		&MarkerDefinition{
+			Name:    "Repository",
+			Targets: []Target{TargetStruct},
+			Help:    "a repository component",
+		},
+		&MarkerDefinition{
+			Name:    "ComponentScan",
+			Targets: []Target{TargetPackage, TargetStruct},
+			Help:    "the packages to scan for components",
+		},
+		&MarkerDefinition{
+			Name:    "Module",
+			Targets: []Target{TargetPackage},
+			Help:    "the module the package belongs to: `@Module(\"name\")`",
+		},
+		&MarkerDefinition{
+			Name:    "Version",
+			Targets: []Target{TargetPackage},
+			Help:    "the version of the package: `@Version(\"1\")`",
+		},
+		&MarkerDefinition{
+			Name:    "Layer",
+			Targets: []Target{TargetPackage},
+			Help:    "the architecture layer of the package: `@Layer(domain)`",
+		},
+	)
+}
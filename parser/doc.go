@@ -0,0 +1,253 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/photowey/parsergo/astx"
+)
+
+var directiveRx = regexp.MustCompile(`^(?P<tool>[a-z0-9]+):(?P<name>[a-z0-9_\-]+)(?:\s+(?P<args>.*))?$`)
+
+type docLine struct {
+	text     string
+	raw      string
+	position astx.Position
+}
+
+func parseDoc(aw *astx.Astx, groups ...*ast.CommentGroup) *astx.DocSpec {
+	var ds *astx.DocSpec
+	paragraphs := make([]string, 0)
+	paragraph := make([]string, 0)
+	flush := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+			paragraph = paragraph[:0]
+		}
+	}
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		if ds == nil {
+			ds = &astx.DocSpec{
+				Raw:         make([]string, 0, len(group.List)),
+				Body:        make([]string, 0),
+				Directives:  make([]*astx.Directive, 0),
+				Markers:     make([]*astx.Annotation, 0),
+				Annotations: make([]*astx.Annotation, 0),
+				Position:    aw.Position(group),
+			}
+		}
+
+		for _, comment := range group.List {
+			if constraint.IsGoBuild(comment.Text) || constraint.IsPlusBuild(comment.Text) {
+				continue
+			}
+			ds.Raw = append(ds.Raw, comment.Text)
+
+			if directive := parseDirective(comment.Text); directive != nil {
+				directive.Position = aw.Position(comment)
+				ds.Directives = append(ds.Directives, directive)
+				continue
+			}
+
+			for _, line := range commentLines(aw, comment) {
+				switch {
+				case isAnnotationLine(line.text):
+					anno := parseAnnotation(line.text)
+					anno.Anno = line.raw
+					anno.Position = line.position
+					ds.Annotations = append(ds.Annotations, anno)
+				case isMarkerLine(line.text):
+					marker := _markers_.Parse(line.text)
+					marker.Anno = line.raw
+					marker.Position = line.position
+					ds.Markers = append(ds.Markers, marker)
+				case line.text == "":
+					flush()
+				default:
+					paragraph = append(paragraph, line.text)
+				}
+			}
+		}
+		flush()
+	}
+
+	if ds == nil {
+		return nil
+	}
+
+	if len(paragraphs) > 0 {
+		summary, rest := firstSentence(paragraphs[0])
+		ds.Summary = summary
+		if rest != "" {
+			ds.Body = append(ds.Body, rest)
+		}
+		ds.Body = append(ds.Body, paragraphs[1:]...)
+	}
+
+	return ds
+}
+
+// commentLines splits `// ...` and `/* ... */` comments into trimmed lines.
+func commentLines(aw *astx.Astx, comment *ast.Comment) []docLine {
+	base := aw.Position(comment)
+	if strings.HasPrefix(comment.Text, "//") {
+		return []docLine{{
+			text:     strings.TrimSpace(comment.Text[2:]),
+			raw:      comment.Text,
+			position: base,
+		}}
+	}
+
+	body := strings.TrimSuffix(strings.TrimPrefix(comment.Text, "/*"), "*/")
+	rows := strings.Split(body, "\n")
+	lines := make([]docLine, 0, len(rows))
+	for i, row := range rows {
+		text := strings.TrimSpace(row)
+		if strings.HasPrefix(text, "*") {
+			text = strings.TrimSpace(text[1:])
+		}
+
+		position := base
+		if i > 0 {
+			position.Line += i
+			position.Column = 1
+		}
+		lines = append(lines, docLine{
+			text:     text,
+			raw:      row,
+			position: position,
+		})
+	}
+
+	// drop the blank lines right after `/*` and right before `*/`
+	for len(lines) > 0 && lines[0].text == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1].text == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// parseDirective recognizes `//go:generate ...` and `//parsergo:skip`, no space after the slashes.
+func parseDirective(text string) *astx.Directive {
+	if !strings.HasPrefix(text, "//") {
+		return nil
+	}
+
+	body := text[2:]
+	if !directiveRx.MatchString(body) {
+		return nil
+	}
+
+	match := directiveRx.FindStringSubmatch(body)
+
+	return &astx.Directive{
+		Tool: match[directiveRx.SubexpIndex("tool")],
+		Name: match[directiveRx.SubexpIndex("name")],
+		Args: strings.TrimSpace(match[directiveRx.SubexpIndex("args")]),
+		Text: text,
+	}
+}
+
+func isAnnotationLine(text string) bool {
+	return len(text) > 1 && text[0] == '@' && unicode.IsLetter(rune(text[1]))
+}
+
+func isMarkerLine(text string) bool {
+	return len(text) > 1 && text[0] == '+' && unicode.IsLetter(rune(text[1]))
+}
+
+// parseAnnotation parses `@Name` and `@Name(values)`.
+func parseAnnotation(text string) *astx.Annotation {
+	body := text[1:]
+	anno := &astx.Annotation{
+		Name: body,
+	}
+
+	end := strings.IndexFunc(body, func(r rune) bool {
+		return !isNameRune(r)
+	})
+	if end < 0 {
+		return anno
+	}
+
+	anno.Name = body[:end]
+	rest := strings.TrimSpace(body[end:])
+	if strings.HasPrefix(rest, "(") {
+		if last := strings.LastIndex(rest, ")"); last > 0 {
+			anno.Values = strings.TrimSpace(rest[1:last])
+			anno.Args = parseArgs(anno.Values)
+		}
+	}
+
+	return anno
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == ':' || r == '-'
+}
+
+func firstSentence(paragraph string) (string, string) {
+	if i := strings.Index(paragraph, ". "); i >= 0 {
+		return paragraph[:i+1], strings.TrimSpace(paragraph[i+2:])
+	}
+
+	return paragraph, ""
+}
+
+// parsePackageDoc parses the package doc comment of the file, together with the markers,
+// annotations and directives of the detached comments above the package clause and on its line.
+// The prose of detached comments, e.g. a license header, is dropped.
+func parsePackageDoc(aw *astx.Astx) *astx.DocSpec {
+	af := aw.Ast
+	doc := parseDoc(aw, af.Doc)
+
+	packageLine := aw.Position(af.Name).Line
+	for _, group := range af.Comments {
+		if group == af.Doc {
+			continue
+		}
+		if group.Pos() >= af.Package && aw.Position(group).Line != packageLine {
+			break
+		}
+
+		detached := parseDoc(aw, group)
+		if doc == nil {
+			doc = detached
+			doc.Summary = ""
+			doc.Body = doc.Body[:0]
+			continue
+		}
+		doc.Raw = append(doc.Raw, detached.Raw...)
+		doc.Directives = append(doc.Directives, detached.Directives...)
+		doc.Markers = append(doc.Markers, detached.Markers...)
+		doc.Annotations = append(doc.Annotations, detached.Annotations...)
+	}
+
+	return doc
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+const docSource = `package doc
+
+// Account holds the balance of a user. It is persisted by the repository.
+//
+// Accounts are never deleted, only closed.
+//
+//go:generate parsergo gen
+//parsergo:skip
+// +kubebuilder:object:root=true
+//@Entity
+// @Table("accounts")
+type Account struct {
+	/*
+	 * ID the primary key
+	 * @Id
+	 */
+	ID int64
+
+	Balance int64 // @Column("balance")
+}
+`
+
+func TestParseDoc(t *testing.T) {
+	ps, err := ParseSource("doc.go", []byte(docSource), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	ss := ps.Structs[0]
+	doc := ss.Doc
+	if doc.Summary != "Account holds the balance of a user." {
+		t.Errorf("Summary = %q", doc.Summary)
+	}
+	wantBody := []string{"It is persisted by the repository.", "Accounts are never deleted, only closed."}
+	if !reflect.DeepEqual(doc.Body, wantBody) {
+		t.Errorf("Body = %q, want %q", doc.Body, wantBody)
+	}
+	if d := doc.Directive("go", "generate"); d == nil || d.Args != "parsergo gen" {
+		t.Errorf("Directive(go, generate) = %+v", d)
+	}
+	if doc.Directive("parsergo", "skip") == nil {
+		t.Errorf("Directive(parsergo, skip) not found")
+	}
+	if len(doc.Markers) != 1 || doc.Markers[0].Name != "kubebuilder:object:root" || doc.Markers[0].Values != "true" {
+		t.Errorf("Markers = %+v", doc.Markers)
+	}
+
+	names := make([]string, 0)
+	for _, anno := range ss.Annotations {
+		names = append(names, anno.Name+"="+anno.Values)
+	}
+	if want := []string{"kubebuilder:object:root=true", "Entity=", `Table="accounts"`}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Annotations = %v, want %v", names, want)
+	}
+
+	id, balance := ss.Fields[0], ss.Fields[1]
+	if id.Doc.Summary != "ID the primary key" || len(id.Annotations) != 1 || id.Annotations[0].Name != "Id" {
+		t.Errorf("block comment doc = %+v, annotations = %v", id.Doc, id.Annotations)
+	}
+	if id.Annotations[0].Position.Line != 15 {
+		t.Errorf("block comment annotation line = %v, want %v", id.Annotations[0].Position.Line, 15)
+	}
+	if len(balance.Annotations) != 1 || balance.Annotations[0].Values != `"balance"` {
+		t.Errorf("line comment annotations = %v", balance.Annotations)
+	}
+}
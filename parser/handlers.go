@@ -0,0 +1,152 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"go/ast"
+	"sync"
+
+	"github.com/photowey/parsergo/astx"
+)
+
+// Context is handed to every handler with the file being parsed.
+type Context struct {
+	Astx *astx.Astx
+	File *astx.FileSpec
+}
+
+type (
+	StructHandler    func(ctx *Context, ss *astx.StructSpec)
+	InterfaceHandler func(ctx *Context, is *astx.InterfaceSpec)
+	MethodHandler    func(ctx *Context, ss *astx.StructSpec, ms *astx.MethodSpec)
+	FuncHandler      func(ctx *Context, fns *astx.FuncSpec)
+	FieldHandler     func(ctx *Context, ss *astx.StructSpec, fds *astx.FieldSpec)
+	CommentHandler   func(ctx *Context, group *ast.CommentGroup, doc *astx.DocSpec)
+)
+
+type handlers struct {
+	sync.RWMutex
+	structs    []StructHandler
+	interfaces []InterfaceHandler
+	methods    []MethodHandler
+	funcs      []FuncHandler
+	fields     []FieldHandler
+	comments   []CommentHandler
+}
+
+func (psr parser) OnStruct(handlers ...StructHandler) {
+	psr.handlers.Lock()
+	defer psr.handlers.Unlock()
+
+	psr.handlers.structs = append(psr.handlers.structs, handlers...)
+}
+
+func (psr parser) OnInterface(handlers ...InterfaceHandler) {
+	psr.handlers.Lock()
+	defer psr.handlers.Unlock()
+
+	psr.handlers.interfaces = append(psr.handlers.interfaces, handlers...)
+}
+
+func (psr parser) OnMethod(handlers ...MethodHandler) {
+	psr.handlers.Lock()
+	defer psr.handlers.Unlock()
+
+	psr.handlers.methods = append(psr.handlers.methods, handlers...)
+}
+
+func (psr parser) OnFunc(handlers ...FuncHandler) {
+	psr.handlers.Lock()
+	defer psr.handlers.Unlock()
+
+	psr.handlers.funcs = append(psr.handlers.funcs, handlers...)
+}
+
+func (psr parser) OnField(handlers ...FieldHandler) {
+	psr.handlers.Lock()
+	defer psr.handlers.Unlock()
+
+	psr.handlers.fields = append(psr.handlers.fields, handlers...)
+}
+
+func (psr parser) OnComment(handlers ...CommentHandler) {
+	psr.handlers.Lock()
+	defer psr.handlers.Unlock()
+
+	psr.handlers.comments = append(psr.handlers.comments, handlers...)
+}
+
+// snapshot copies the handlers registered so far, so registering more while parsing is safe.
+func (hs *handlers) snapshot() *handlers {
+	hs.RLock()
+	defer hs.RUnlock()
+
+	return &handlers{
+		structs:    hs.structs,
+		interfaces: hs.interfaces,
+		methods:    hs.methods,
+		funcs:      hs.funcs,
+		fields:     hs.fields,
+		comments:   hs.comments,
+	}
+}
+
+func (hs *handlers) dispatch(aw *astx.Astx, fs *astx.FileSpec) {
+	if hs == nil {
+		return
+	}
+	hs = hs.snapshot()
+
+	ctx := &Context{
+		Astx: aw,
+		File: fs,
+	}
+
+	for _, ss := range fs.Structs {
+		for _, handler := range hs.structs {
+			handler(ctx, ss)
+		}
+		for _, fds := range ss.Fields {
+			for _, handler := range hs.fields {
+				handler(ctx, ss, fds)
+			}
+		}
+		for _, ms := range ss.Methods {
+			for _, handler := range hs.methods {
+				handler(ctx, ss, ms)
+			}
+		}
+	}
+	for _, is := range fs.Interfaces {
+		for _, handler := range hs.interfaces {
+			handler(ctx, is)
+		}
+	}
+	for _, fns := range fs.Funcs {
+		for _, handler := range hs.funcs {
+			handler(ctx, fns)
+		}
+	}
+	if len(hs.comments) > 0 {
+		for _, group := range aw.Ast.Comments {
+			doc := parseDoc(aw, group)
+			for _, handler := range hs.comments {
+				handler(ctx, group, doc)
+			}
+		}
+	}
+}
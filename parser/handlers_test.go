@@ -0,0 +1,119 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+)
+
+var columnsKey = astx.NewExtensionKey[[]string]("test.columns")
+
+func TestParser_Handlers(t *testing.T) {
+	psr := NewParser()
+	psr.OnField(func(ctx *Context, ss *astx.StructSpec, fds *astx.FieldSpec) {
+		columns, _ := columnsKey.Get(&ss.Extensions)
+		columnsKey.Set(&ss.Extensions, append(columns, strings.ToLower(fds.Name)))
+	})
+	funcs := 0
+	psr.OnFunc(func(ctx *Context, fns *astx.FuncSpec) {
+		if _, ok := fns.Node.(*ast.FuncDecl); ok {
+			funcs++
+		}
+	})
+	comments := 0
+	psr.OnComment(func(ctx *Context, group *ast.CommentGroup, doc *astx.DocSpec) {
+		comments++
+	})
+
+	lpkg, err := loader.LoadSource("hello.go", []byte(helloSource+"\n// NewHelloService creates a service\nfunc NewHelloService() *HelloServiceImpl { return nil }\n"), nil)
+	if err != nil {
+		t.Fatalf("LoadSource() error = %v", err)
+	}
+	as := psr.Parse(lpkg)
+
+	ss := as.Package.Structs[0]
+	columns, ok := columnsKey.Get(&ss.Extensions)
+	if !ok || len(columns) != 1 || columns[0] != "name" {
+		t.Errorf("extension %s = %v, want [name]", columnsKey.Name, columns)
+	}
+	if funcs != 1 {
+		t.Errorf("OnFunc() called %v times, want 1", funcs)
+	}
+	if comments != 2 {
+		t.Errorf("OnComment() called %v times, want 2", comments)
+	}
+	if _, ok := columnsKey.Get(&as.Package.Structs[0].Methods[0].Extensions); ok {
+		t.Errorf("extension %s leaked onto a method", columnsKey.Name)
+	}
+}
+
+func TestParser_HandlersConcurrently(t *testing.T) {
+	lpkg, err := loader.LoadSource("hello.go", []byte(helloSource), nil)
+	if err != nil {
+		t.Fatalf("LoadSource() error = %v", err)
+	}
+
+	psr := NewParser()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			psr.OnStruct(func(ctx *Context, ss *astx.StructSpec) {})
+		}()
+		go func() {
+			defer wg.Done()
+			psr.Parse(lpkg)
+		}()
+	}
+	wg.Wait()
+
+	structs := 0
+	psr.OnStruct(func(ctx *Context, ss *astx.StructSpec) {
+		structs++
+	})
+	psr.Parse(lpkg)
+	if structs != 1 {
+		t.Errorf("OnStruct() called %v times, want 1", structs)
+	}
+}
+
+func TestParser_HandlersAttachedMethods(t *testing.T) {
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/tests/typex")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+
+	psr := NewParser()
+	methods := make([]string, 0)
+	psr.OnMethod(func(ctx *Context, ss *astx.StructSpec, ms *astx.MethodSpec) {
+		methods = append(methods, ss.Name+"."+ms.Name)
+	})
+	psr.Parse(roots[0])
+
+	sort.Strings(methods)
+	if got, want := strings.Join(methods, ","), "Page.Append,Page.First,Schedule.After"; got != want {
+		t.Errorf("OnMethod() called with %v, want %v", got, want)
+	}
+}
@@ -19,41 +19,74 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/build/constraint"
+	"sort"
 	"strings"
 
 	"github.com/photowey/parsergo/astx"
 	"github.com/photowey/parsergo/loader"
-	"github.com/photowey/parsergo/pkg/regexpx"
 )
 
-type parser struct{}
+type parser struct {
+	handlers *handlers
+}
 
 func (psr parser) Parse(pkg *loader.Package) *astx.AstSpec {
-	pkgs := make([]*astx.PackageSpec, 0, len(pkg.CompiledGoFiles))
+	variant := pkg.TestVariant()
+	ps := populatePackageSpec(pkg)
+	aws := make([]*astx.Astx, 0, len(pkg.CompiledGoFiles))
+	fss := make([]*astx.FileSpec, 0, len(pkg.CompiledGoFiles))
 	for _, cf := range pkg.CompiledGoFiles {
-		aw := astx.NewAstx(cf, pkg)
-		if aw.Ast.Comments == nil {
+		if variant == loader.TestVariantInternal && !strings.HasSuffix(cf, "_test.go") {
+			// the non-test files are already covered by the package itself
 			continue
 		}
 
-		ps := psr.ParseStructs(aw)
-		if len(ps.Structs) == 0 {
+		aw := astx.NewAstx(cf, pkg)
+		if aw.Ast == nil {
+			// unreadable or unparsable, see pkg.Errors
 			continue
 		}
+		fs := psr.parseFileSpec(aw)
+		aws = append(aws, aw)
+		fss = append(fss, fs)
+		appendFileSpec(ps, fs)
+	}
+	psr.attachMethods(ps, aws)
 
-		pkgs = append(pkgs, ps)
+	// dispatched once every method is attached to its struct
+	for i, aw := range aws {
+		psr.handlers.dispatch(aw, fss[i])
 	}
 
 	return &astx.AstSpec{
 		ID:      pkg.ID,
 		Name:    pkg.Name,
 		PkgPath: pkg.PkgPath,
-		Pkgs:    pkgs,
+		Test:    variant,
+		Package: ps,
 	}
 }
 
-func (psr parser) ParseStructs(aw *astx.Astx) *astx.PackageSpec {
-	ps := populatePackageSpec(aw)
+func (psr parser) ParseFileSpec(aw *astx.Astx) *astx.FileSpec {
+	fs := psr.parseFileSpec(aw)
+	psr.handlers.dispatch(aw, fs)
+
+	return fs
+}
+
+func (psr parser) parseFileSpec(aw *astx.Astx) *astx.FileSpec {
+	fs := psr.ParseStructs(aw)
+	fs.Test = aw.Package.TestVariant()
+	psr.ParseInterfaces(aw, fs)
+	psr.ParseFuncs(aw, fs)
+	psr.ParseAnnotations(aw, fs)
+
+	return fs
+}
+
+func (psr parser) ParseStructs(aw *astx.Astx) *astx.FileSpec {
+	fs := populateFileSpec(aw)
 	for _, d := range aw.Ast.Decls {
 		switch decl := d.(type) {
 		case *ast.GenDecl:
@@ -62,26 +95,28 @@ func (psr parser) ParseStructs(aw *astx.Astx) *astx.PackageSpec {
 				switch specVal := spec.(type) {
 				case *ast.TypeSpec:
 					if st, ok := specVal.Type.(*ast.StructType); ok {
-						if decl.Doc == nil {
+						doc := typeDoc(decl, specVal)
+						if doc == nil {
 							continue SPEC
 						}
-						ss := psr.parserStruct(aw, decl, specVal, st)
+						ss := psr.parserStruct(aw, fs, doc, specVal, st)
 
 						ss.Type = st.Struct
-						ps.Structs = append(ps.Structs, ss)
+						ss.Position = aw.Position(specVal)
+						ss.Node = specVal
+						fs.Structs = append(fs.Structs, ss)
 					}
 				}
 			}
 		}
 	}
 
-	psr.ParseMethods(aw, ps)
-	psr.ParseAnnotations(aw, ps)
+	psr.ParseMethods(aw, fs)
 
-	return ps
+	return fs
 }
 
-func (psr parser) ParseInterfaces(aw *astx.Astx, ps *astx.PackageSpec) {
+func (psr parser) ParseInterfaces(aw *astx.Astx, fs *astx.FileSpec) {
 	for _, d := range aw.Ast.Decls {
 		switch decl := d.(type) {
 		case *ast.GenDecl:
@@ -90,25 +125,28 @@ func (psr parser) ParseInterfaces(aw *astx.Astx, ps *astx.PackageSpec) {
 				switch specVal := spec.(type) {
 				case *ast.TypeSpec:
 					if it, ok := specVal.Type.(*ast.InterfaceType); ok {
-						if decl.Doc == nil {
+						doc := typeDoc(decl, specVal)
+						if doc == nil {
 							continue SPEC
 						}
-						comments := make([]string, 0, len(decl.Doc.List))
-						if decl.Doc != nil {
-							for _, comment := range decl.Doc.List {
-								comments = append(comments, comment.Text)
-							}
+						comments := make([]string, 0, len(doc.List))
+						for _, comment := range doc.List {
+							comments = append(comments, comment.Text)
 						}
 
 						is := &astx.InterfaceSpec{
-							Pkg:         ps.Pkg,
+							Pkg:         fs.Pkg,
 							Name:        specVal.Name.String(),
+							Position:    aw.Position(specVal),
 							Comments:    comments,
+							Doc:         parseDoc(aw, doc),
+							Node:        specVal,
 							Methods:     make([]*astx.MethodSpec, 0),
 							Annotations: make([]*astx.Annotation, 0),
 						}
 						is.Type = it.Interface
-						ps.Interfaces = append(ps.Interfaces, is)
+						psr.interfaceMethods(aw, fs, is, it)
+						fs.Interfaces = append(fs.Interfaces, is)
 					}
 				}
 			}
@@ -116,50 +154,56 @@ func (psr parser) ParseInterfaces(aw *astx.Astx, ps *astx.PackageSpec) {
 	}
 }
 
-func (psr parser) ParseMethods(aw *astx.Astx, ps *astx.PackageSpec) {
-	for _, d := range aw.Ast.Decls {
-		switch funcDecl := d.(type) {
-		case *ast.FuncDecl:
-			comments := make([]string, 0)
-			if funcDecl.Doc != nil {
-				for _, comment := range funcDecl.Doc.List {
+// interfaceMethods adds the methods declared by an interface, its embedded interfaces and constraint terms to Embeds.
+func (psr parser) interfaceMethods(aw *astx.Astx, fs *astx.FileSpec, is *astx.InterfaceSpec, it *ast.InterfaceType) {
+	if it.Methods == nil {
+		return
+	}
+	for _, field := range it.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			is.Embeds = append(is.Embeds, resolveType(fs, field.Type))
+			continue
+		}
+
+		comments := make([]string, 0)
+		for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+			if group != nil {
+				for _, comment := range group.List {
 					comments = append(comments, comment.Text)
 				}
 			}
+		}
+		for _, name := range field.Names {
+			is.Methods = append(is.Methods, &astx.MethodSpec{
+				Pkg:      fs.Pkg,
+				Struct:   is.Name,
+				Name:     name.Name,
+				Position: aw.Position(name),
+				Comments: comments,
+				Doc:      parseDoc(aw, field.Doc, field.Comment),
+				Node:     field,
+				Params:   psr.handleParams(aw, name.Name, funcType, fs),
+				Returns:  psr.handleResults(aw, name.Name, funcType, fs),
+			})
+		}
+	}
+}
+
+func (psr parser) ParseMethods(aw *astx.Astx, fs *astx.FileSpec) {
+	for _, d := range aw.Ast.Decls {
+		switch funcDecl := d.(type) {
+		case *ast.FuncDecl:
 			if funcDecl.Recv != nil {
 				for _, field := range funcDecl.Recv.List {
 
-					for _, spec := range ps.Structs {
+					for _, spec := range fs.Structs {
 						structName := spec.Name
 
-						stn := ""
-						switch ft := field.Type.(type) {
-						case *ast.Ident:
-							// func (x Xxx) MethodName(...) {}
-							stn = ft.Name
-						case *ast.StarExpr:
-							// func (x *Xxx) MethodName(...) {}
-							switch xt := ft.X.(type) {
-							case *ast.Ident:
-								stn = xt.Name
-							case *ast.SelectorExpr:
-								// do nothing
-							}
-						}
-
+						// func (x Xxx), (x *Xxx) or (x *Xxx[T]) MethodName(...) {}
+						stn := receiverName(field.Type)
 						if structName == stn {
-							ms := &astx.MethodSpec{
-								Pkg:      ps.Pkg,
-								Struct:   structName,
-								Name:     funcDecl.Name.String(),
-								Comments: comments,
-								Params:   make([]*astx.ParamSpec, 0),
-								Returns:  make([]*astx.ReturnSpec, 0),
-							}
-
-							psr.handleParams(funcDecl, ps, ms)
-							psr.handleResults(funcDecl, ps, ms)
-
+							ms := psr.methodSpec(aw, fs, funcDecl, structName)
 							spec.Methods = append(spec.Methods, ms)
 						}
 					}
@@ -169,7 +213,90 @@ func (psr parser) ParseMethods(aw *astx.Astx, ps *astx.PackageSpec) {
 	}
 }
 
-func (psr parser) ParseFuncs(aw *astx.Astx, ps *astx.PackageSpec) {
+func (psr parser) methodSpec(aw *astx.Astx, fs *astx.FileSpec, funcDecl *ast.FuncDecl, structName string) *astx.MethodSpec {
+	typeParams := receiverTypeParams(funcDecl.Recv.List[0].Type)
+	comments := make([]string, 0)
+	if funcDecl.Doc != nil {
+		for _, comment := range funcDecl.Doc.List {
+			comments = append(comments, comment.Text)
+		}
+	}
+
+	return &astx.MethodSpec{
+		Pkg:      fs.Pkg,
+		Struct:   structName,
+		Name:     funcDecl.Name.String(),
+		Position: aw.Position(funcDecl),
+		Comments: comments,
+		Doc:      parseDoc(aw, funcDecl.Doc),
+		Node:     funcDecl,
+		Params:   psr.handleParams(aw, funcDecl.Name.String(), funcDecl.Type, fs, typeParams...),
+		Returns:  psr.handleResults(aw, funcDecl.Name.String(), funcDecl.Type, fs, typeParams...),
+	}
+}
+
+// attachMethods attaches the methods declared in another file than their struct,
+// which ParseMethods can't see file by file.
+func (psr parser) attachMethods(ps *astx.PackageSpec, aws []*astx.Astx) {
+	structs := make(map[string]*astx.StructSpec, len(ps.Structs))
+	for _, ss := range ps.Structs {
+		structs[ss.Name] = ss
+	}
+
+	for _, aw := range aws {
+		var fs *astx.FileSpec
+		for _, d := range aw.Ast.Decls {
+			funcDecl, ok := d.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+			ss := structs[receiverName(funcDecl.Recv.List[0].Type)]
+			if ss == nil || ss.Position.File == aw.Path {
+				continue
+			}
+			if fs == nil {
+				fs = populateFileSpec(aw)
+			}
+
+			ms := psr.methodSpec(aw, fs, funcDecl, ss.Name)
+			ms.Annotations = collectAnnotations(ss.Pkg, ss.Alias, ms.Doc)
+			ss.Methods = append(ss.Methods, ms)
+		}
+	}
+}
+
+// typeDoc is the doc comment of a type spec, the one of its declaration only when it declares nothing else:
+// the doc of a `type ( ... )` group doesn't document each type.
+func typeDoc(decl *ast.GenDecl, ts *ast.TypeSpec) *ast.CommentGroup {
+	if ts.Doc != nil {
+		return ts.Doc
+	}
+	if len(decl.Specs) == 1 {
+		return decl.Doc
+	}
+
+	return nil
+}
+
+// receiverName is the base type name of a receiver: `T`, `*T`, `T[K]` or `*T[K, V]`.
+func receiverName(expr ast.Expr) string {
+	switch rt := expr.(type) {
+	case *ast.Ident:
+		return rt.Name
+	case *ast.StarExpr:
+		return receiverName(rt.X)
+	case *ast.ParenExpr:
+		return receiverName(rt.X)
+	case *ast.IndexExpr:
+		return receiverName(rt.X)
+	case *ast.IndexListExpr:
+		return receiverName(rt.X)
+	}
+
+	return ""
+}
+
+func (psr parser) ParseFuncs(aw *astx.Astx, fs *astx.FileSpec) {
 	for _, d := range aw.Ast.Decls {
 		switch decl := d.(type) {
 		case *ast.FuncDecl:
@@ -180,51 +307,52 @@ func (psr parser) ParseFuncs(aw *astx.Astx, ps *astx.PackageSpec) {
 				}
 			}
 			if decl.Recv == nil {
-				fs := &astx.FuncSpec{
+				fns := &astx.FuncSpec{
 					Pkg:      aw.Pkg,
 					Name:     decl.Name.String(),
+					Position: aw.Position(decl),
 					Comments: comments,
-					Params:   make([]*astx.ParamSpec, 0),
-					Returns:  make([]*astx.ReturnSpec, 0),
+					Doc:      parseDoc(aw, decl.Doc),
+					Node:     decl,
 				}
-				ps.Funcs = append(ps.Funcs, fs)
+				fns.Params = psr.handleParams(aw, decl.Name.String(), decl.Type, fs)
+				fns.Returns = psr.handleResults(aw, decl.Name.String(), decl.Type, fs)
+
+				fs.Funcs = append(fs.Funcs, fns)
 			}
 		}
 	}
 }
 
-func (psr parser) ParseAnnotations(aw *astx.Astx, ps *astx.PackageSpec) {
-	for _, spec := range ps.Structs {
-		comments := spec.Comments
-		for _, comment := range comments {
-			// `// @Service`
-			// `// @Service("helloService")`
-			// `// @ComponentScan({"path":"github.com/photowey/parsergo/tests","excludes":["github.com/photowey/parsergo/tests/structx"]})`
-			cmt := comment[3:]
-			if ok := strings.HasPrefix(cmt, "@"); ok {
-				anno := &astx.Annotation{
-					Pkg:   spec.Pkg,
-					Alias: spec.Alias,
-					Anno:  comment,
-				}
-				anno.Name = regexpx.RegexpExtract(`^@(?P<annotation>[\S]+)[\(.*\)]?`, cmt, "$annotation")
-				if strings.Contains(cmt, "(") && strings.Contains(cmt, ")") {
-					// `// @Service("helloService")`
-					anno.Values = regexpx.RegexpExtract(`^@.*\((?P<value>[\S]+)\)`, cmt, "$value")
-				}
-
-				spec.Annotations = append(spec.Annotations, anno)
-			}
+func (psr parser) ParseAnnotations(aw *astx.Astx, fs *astx.FileSpec) {
+	fs.Annotations = collectAnnotations(fs.Pkg, fs.Alias, fs.Doc)
+	for _, spec := range fs.Structs {
+		// `// @Service`
+		// `// @Service("helloService")`
+		// `// @ComponentScan({"path":"github.com/photowey/parsergo/tests","excludes":["github.com/photowey/parsergo/tests/structx"]})`
+		spec.Annotations = collectAnnotations(spec.Pkg, spec.Alias, spec.Doc)
+		for _, field := range spec.Fields {
+			field.Annotations = collectAnnotations(spec.Pkg, spec.Alias, field.Doc, field.Comment)
+		}
+		for _, method := range spec.Methods {
+			method.Annotations = collectAnnotations(spec.Pkg, spec.Alias, method.Doc)
 		}
 	}
+	for _, spec := range fs.Interfaces {
+		spec.Annotations = collectAnnotations(fs.Pkg, fs.Alias, spec.Doc)
+		for _, method := range spec.Methods {
+			method.Annotations = collectAnnotations(fs.Pkg, fs.Alias, method.Doc)
+		}
+	}
+	for _, spec := range fs.Funcs {
+		spec.Annotations = collectAnnotations(fs.Pkg, fs.Alias, spec.Doc)
+	}
 }
 
-func (psr parser) parserStruct(aw *astx.Astx, decl *ast.GenDecl, specVal *ast.TypeSpec, st *ast.StructType) *astx.StructSpec {
-	comments := make([]string, 0, len(decl.Doc.List))
-	if decl.Doc != nil {
-		for _, comment := range decl.Doc.List {
-			comments = append(comments, comment.Text)
-		}
+func (psr parser) parserStruct(aw *astx.Astx, fs *astx.FileSpec, doc *ast.CommentGroup, specVal *ast.TypeSpec, st *ast.StructType) *astx.StructSpec {
+	comments := make([]string, 0, len(doc.List))
+	for _, comment := range doc.List {
+		comments = append(comments, comment.Text)
 	}
 
 	ss := &astx.StructSpec{
@@ -232,6 +360,7 @@ func (psr parser) parserStruct(aw *astx.Astx, decl *ast.GenDecl, specVal *ast.Ty
 		Alias:       aw.Package.Name,
 		Name:        specVal.Name.String(),
 		Comments:    comments,
+		Doc:         parseDoc(aw, doc),
 		Fields:      make([]*astx.FieldSpec, 0),
 		Methods:     make([]*astx.MethodSpec, 0),
 		Annotations: make([]*astx.Annotation, 0),
@@ -239,169 +368,276 @@ func (psr parser) parserStruct(aw *astx.Astx, decl *ast.GenDecl, specVal *ast.Ty
 
 	if fields := st.Fields; fields != nil && fields.List != nil {
 		for _, field := range fields.List {
-			fs := &astx.FieldSpec{
-				Struct: specVal.Name.String(),
-				Name:   field.Names[0].Name,
-				Tags:   make([]*astx.TagSpec, 0),
+			names := make([]string, 0, len(field.Names))
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+			embedded := len(names) == 0
+			if embedded {
+				// Xxx `k:"v"` | *Xxx `k:"v"` | yyy.Zzz `k:"v"`
+				names = append(names, resolveType(fs, field.Type).Deref().Name)
 			}
 
-			// handle field's type
-			psr.handleFieldType(field, fs)
-			// handle field's tag
-			psr.handleFieldTag(field, fs)
+			for _, name := range names {
+				fds := &astx.FieldSpec{
+					Struct:   specVal.Name.String(),
+					Name:     name,
+					Embedded: embedded,
+					Position: aw.Position(field),
+					Doc:      parseDoc(aw, field.Doc),
+					Comment:  parseDoc(aw, field.Comment),
+					Node:     field,
+					Tags:     make([]*astx.TagSpec, 0),
+				}
+
+				// handle field's type
+				psr.handleFieldType(fs, field, fds)
+				// handle field's tag
+				psr.handleFieldTag(aw, field, fds)
 
-			ss.Fields = append(ss.Fields, fs)
+				ss.Fields = append(ss.Fields, fds)
+			}
 		}
 	}
 
 	return ss
 }
 
-func (psr parser) handleFieldType(field *ast.Field, fs *astx.FieldSpec) {
-	switch expr := field.Type.(type) {
-	case *ast.Ident: // Xxx Yyy `k:"v"`
-		fs.Type = expr.Name
-	case *ast.StarExpr: // Xxx *Yyy `k:"v"`
-		switch starExpr := expr.X.(type) {
-		case *ast.SelectorExpr:
-			if sx, oke := starExpr.X.(*ast.Ident); oke {
-				fs.Type = fmt.Sprintf("*%s.%s", sx.Name, starExpr.Sel.Name)
-			} else {
-				fs.Type = fmt.Sprintf("*%s", starExpr.Sel.Name)
-			}
-		case *ast.Ident:
-			fs.Type = fmt.Sprintf("*%s", starExpr.Name)
-		}
-
-		fs.Ptr = true
-	case *ast.SelectorExpr: //  Xxx yyy.Zzz `k:"v"`
-		if sx, oke := expr.X.(*ast.Ident); oke {
-			fs.Type = fmt.Sprintf("%s.%s", sx.Name, expr.Sel.Name)
-		} else {
-			fs.Type = expr.Sel.Name
-		}
-	}
+func (psr parser) handleFieldType(fs *astx.FileSpec, field *ast.Field, fds *astx.FieldSpec) {
+	fds.TypeRef = resolveType(fs, field.Type)
+	fds.Type = fds.TypeRef.Expr
+	fds.Ptr = fds.TypeRef.IsPtr()
 }
 
-func (psr parser) handleFieldTag(field *ast.Field, fs *astx.FieldSpec) {
+func (psr parser) handleFieldTag(aw *astx.Astx, field *ast.Field, fs *astx.FieldSpec) {
 	if fieldTag := field.Tag; fieldTag != nil {
-		tagValue := fieldTag.Value               // `xxx:"xv" yyy:"yv"`
-		tagValue = tagValue[1 : len(tagValue)-1] // xxx:"xv" yyy:"yv"
+		// malformed tags keep their well-formed leading pairs, the analysis package reports the rest
+		tags, _ := ParseTag(fieldTag.Value)
 		ts := &astx.TagSpec{
-			Field: fs.Name,
-			Tags:  make([]*astx.Tag, 0),
-		}
-		tvs := strings.Split(tagValue, " ")
-		for _, tv := range tvs {
-			kvs := strings.Split(tv, ":")
-			k := kvs[0]                    // xxx | yyy
-			v := kvs[1][1 : len(kvs[1])-1] // xv | yv
-			tag := &astx.Tag{
-				Name:  fieldTag.Value,
-				Key:   k,
-				Value: v,
-			}
-			ts.Tags = append(ts.Tags, tag)
+			Field:    fs.Name,
+			Position: aw.Position(fieldTag),
+			Tags:     tags,
 		}
 
 		fs.Tags = append(fs.Tags, ts)
 	}
 }
 
-func (psr parser) handleResults(funcDecl *ast.FuncDecl, ps *astx.PackageSpec, ms *astx.MethodSpec) {
-	hasResults := funcDecl.Type != nil && funcDecl.Type.Results != nil && funcDecl.Type.Results.List != nil
+func (psr parser) handleResults(aw *astx.Astx, funcName string, funcType *ast.FuncType, fs *astx.FileSpec, typeParams ...string) []*astx.ReturnSpec {
+	returns := make([]*astx.ReturnSpec, 0)
+	hasResults := funcType != nil && funcType.Results != nil && funcType.Results.List != nil
 	if hasResults {
-		for _, rvt := range funcDecl.Type.Results.List {
-			rs := &astx.ReturnSpec{
-				Pkg:      ps.Pkg,
-				FuncName: funcDecl.Name.String(),
-				Ptr:      false,
-			}
-			if names := rvt.Names; names != nil {
-				rs.Name = rvt.Names[0].Name
-			}
-
-			switch rvtType := rvt.Type.(type) {
-			case *ast.Ident:
-				rs.Type = rvtType.Name
-			case *ast.StarExpr:
-				switch xt := rvtType.X.(type) {
-				case *ast.Ident:
-					rs.Type = fmt.Sprintf("*%s", xt.Name)
-				case *ast.SelectorExpr:
-					if x, okx := xt.X.(*ast.Ident); okx {
-						rs.Type = fmt.Sprintf("*%s.%s", x.Name, xt.Sel.Name)
-					} else {
-						rs.Type = fmt.Sprintf("*%s", xt.Sel.Name)
-					}
+		for _, rvt := range funcType.Results.List {
+			names := fieldNames(rvt)
+			for _, name := range names {
+				rs := &astx.ReturnSpec{
+					Pkg:      fs.Pkg,
+					FuncName: funcName,
+					Name:     name,
+					TypeRef:  resolveType(fs, rvt.Type, typeParams...),
+					Position: aw.Position(rvt),
 				}
-				rs.Ptr = true
-			}
+				rs.Type = rs.TypeRef.Expr
+				rs.Ptr = rs.TypeRef.IsPtr()
 
-			ms.Returns = append(ms.Returns, rs)
+				returns = append(returns, rs)
+			}
 		}
 	}
+
+	return returns
 }
 
-func (psr parser) handleParams(funcDecl *ast.FuncDecl, ps *astx.PackageSpec, ms *astx.MethodSpec) {
-	hasParams := funcDecl.Type != nil && funcDecl.Type.Params != nil && funcDecl.Type.Params.List != nil
+func (psr parser) handleParams(aw *astx.Astx, funcName string, funcType *ast.FuncType, fs *astx.FileSpec, typeParams ...string) []*astx.ParamSpec {
+	params := make([]*astx.ParamSpec, 0)
+	hasParams := funcType != nil && funcType.Params != nil && funcType.Params.List != nil
 	if hasParams {
-		for _, param := range funcDecl.Type.Params.List {
-			for _, pn := range param.Names {
+		for _, param := range funcType.Params.List {
+			names := fieldNames(param)
+			for i, name := range names {
 				pms := &astx.ParamSpec{
-					Pkg:      ps.Pkg,
-					FuncName: funcDecl.Name.String(),
-					Name:     pn.Name,
-					Ptr:      false,
+					Pkg:      fs.Pkg,
+					FuncName: funcName,
+					Name:     name,
+					TypeRef:  resolveType(fs, param.Type, typeParams...),
+					Position: aw.Position(param),
 				}
-
-				switch expr := param.Type.(type) {
-				case *ast.Ident:
-					pms.Type = expr.Name
-				case *ast.StarExpr:
-					switch starExpr := expr.X.(type) {
-					case *ast.Ident:
-						pms.Type = starExpr.Name
-					case *ast.SelectorExpr:
-						if x, okx := starExpr.X.(*ast.Ident); okx {
-							pt := fmt.Sprintf("*%s.%s", x.Name, starExpr.Sel.Name)
-							pms.Type = pt
-						} else {
-							pt := fmt.Sprintf("*%s", starExpr.Sel.Name)
-							pms.Type = pt
-						}
-					}
-					pms.Ptr = true
-				case *ast.SelectorExpr:
-					if x, okx := expr.X.(*ast.Ident); okx {
-						pms.Type = fmt.Sprintf("%s.%s", x.Name, expr.Sel.Name)
-					} else {
-						pms.Type = expr.Sel.Name
-					}
+				if len(param.Names) > 0 {
+					pms.Position = aw.Position(param.Names[i])
 				}
+				pms.Type = pms.TypeRef.Expr
+				pms.Ptr = pms.TypeRef.IsPtr()
 
-				ms.Params = append(ms.Params, pms)
+				params = append(params, pms)
 			}
 		}
 	}
+
+	return params
+}
+
+// fieldNames lists the names declared by a param or result, a single "" for an unnamed one.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		return []string{""}
+	}
+
+	names := make([]string, 0, len(field.Names))
+	for _, name := range field.Names {
+		names = append(names, name.Name)
+	}
+
+	return names
 }
 
 func NewParser() Parser {
-	return &parser{}
+	return &parser{
+		handlers: &handlers{},
+	}
+}
+
+// Default is the parser behind the package-level Parse, ParseFile and ParseSource, shared by the whole process.
+func Default() Parser {
+	return _parser_
 }
 
 func Parse(pkg *loader.Package) *astx.AstSpec {
 	return _parser_.Parse(pkg)
 }
 
-func populatePackageSpec(aw *astx.Astx) *astx.PackageSpec {
+func populatePackageSpec(pkg *loader.Package) *astx.PackageSpec {
 	ps := &astx.PackageSpec{
-		Pkg:        aw.Package.PkgPath,
-		Alias:      aw.Package.Name,
-		Structs:    make([]*astx.StructSpec, 0),
-		Interfaces: make([]*astx.InterfaceSpec, 0),
-		Funcs:      make([]*astx.FuncSpec, 0),
+		Pkg:         pkg.PkgPath,
+		Alias:       pkg.Name,
+		Test:        pkg.TestVariant(),
+		Annotations: make([]*astx.Annotation, 0),
+		Diagnostics: make([]*astx.Diagnostic, 0),
+		Files:       make([]*astx.FileSpec, 0, len(pkg.CompiledGoFiles)),
+		Structs:     make([]*astx.StructSpec, 0),
+		Interfaces:  make([]*astx.InterfaceSpec, 0),
+		Funcs:       make([]*astx.FuncSpec, 0),
 	}
 
 	return ps
 }
+
+func appendFileSpec(ps *astx.PackageSpec, fs *astx.FileSpec) {
+	mergePackageDoc(ps, fs)
+	ps.Files = append(ps.Files, fs)
+	ps.Structs = append(ps.Structs, fs.Structs...)
+	ps.Interfaces = append(ps.Interfaces, fs.Interfaces...)
+	ps.Funcs = append(ps.Funcs, fs.Funcs...)
+}
+
+// mergePackageDoc merges the package doc and annotations of fs into ps.
+// The same annotation declared with different values in different files is reported as a conflict.
+func mergePackageDoc(ps *astx.PackageSpec, fs *astx.FileSpec) {
+	if fs.Doc != nil && fs.Doc.Summary != "" && (ps.Doc == nil || fs.Name == "doc.go") {
+		ps.Doc = fs.Doc
+	}
+
+	for _, anno := range fs.Annotations {
+		var conflict *astx.Annotation
+		duplicated := false
+		for _, merged := range ps.Annotations {
+			if merged.Name != anno.Name || merged.Marker != anno.Marker || merged.Position.File == anno.Position.File {
+				continue
+			}
+			if merged.Values == anno.Values {
+				duplicated = true
+				break
+			}
+			if def := _markers_.Lookup(anno.Name); anno.Marker && def != nil && def.Repeatable {
+				continue
+			}
+			conflict = merged
+		}
+
+		switch {
+		case duplicated:
+		case conflict != nil:
+			ps.Diagnostics = append(ps.Diagnostics, &astx.Diagnostic{
+				Severity: astx.SeverityError,
+				Message: fmt.Sprintf("conflicting package annotation %s: %s here, %s at %s",
+					anno.Name, anno.Values, conflict.Values, conflict.Position),
+				Position: anno.Position,
+				Related:  []astx.Position{conflict.Position},
+			})
+		default:
+			ps.Annotations = append(ps.Annotations, anno)
+		}
+	}
+}
+
+func populateFileSpec(aw *astx.Astx) *astx.FileSpec {
+	fs := &astx.FileSpec{
+		Pkg:         aw.Package.PkgPath,
+		Alias:       aw.Package.Name,
+		Path:        aw.Path,
+		Name:        aw.Name,
+		Constraints: buildConstraints(aw.Ast),
+		Imports:     make([]*astx.ImportSpec, 0, len(aw.Ast.Imports)),
+		Position:    aw.Position(aw.Ast),
+		Doc:         parsePackageDoc(aw),
+		Annotations: make([]*astx.Annotation, 0),
+		Structs:     make([]*astx.StructSpec, 0),
+		Interfaces:  make([]*astx.InterfaceSpec, 0),
+		Funcs:       make([]*astx.FuncSpec, 0),
+	}
+
+	for _, spec := range aw.Ast.Imports {
+		is := resolveImport(aw.Package, spec)
+		is.Position = aw.Position(spec)
+		fs.Imports = append(fs.Imports, is)
+	}
+
+	return fs
+}
+
+func buildConstraints(af *ast.File) []string {
+	goBuild := make([]string, 0)
+	plusBuild := make([]string, 0)
+	for _, group := range af.Comments {
+		if group.Pos() >= af.Package {
+			break
+		}
+		for _, comment := range group.List {
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+			if constraint.IsGoBuild(comment.Text) {
+				goBuild = append(goBuild, expr.String())
+			} else {
+				plusBuild = append(plusBuild, expr.String())
+			}
+		}
+	}
+
+	// `//go:build` supersedes the legacy `// +build` lines
+	if len(goBuild) > 0 {
+		return goBuild
+	}
+
+	return plusBuild
+}
+
+func collectAnnotations(pkg, alias string, docs ...*astx.DocSpec) []*astx.Annotation {
+	annotations := make([]*astx.Annotation, 0)
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		annos := append(append(make([]*astx.Annotation, 0), doc.Annotations...), doc.Markers...)
+		sort.SliceStable(annos, func(i, j int) bool {
+			return annos[i].Position.Offset < annos[j].Position.Offset
+		})
+		for _, anno := range annos {
+			anno.Pkg = pkg
+			anno.Alias = alias
+			annotations = append(annotations, anno)
+		}
+	}
+
+	return annotations
+}
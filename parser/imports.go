@@ -18,7 +18,11 @@ package parser
 
 import (
 	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
 	"path"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -27,37 +31,115 @@ import (
 	"github.com/photowey/parsergo/sets"
 )
 
+const (
+	importGroupStd = iota
+	importGroupThirdParty
+	importGroupLocal
+)
+
 type Imports struct {
 	byPath  sets.StringMap
 	byAlias sets.StringMap
 
-	pkg *loader.Package
+	pkgPath string
+	module  string
+	pkg     *loader.Package
 }
 
-func (its *Imports) ImportSpecs() []string {
-	res := make([]string, 0, len(its.byPath))
-	for importPath, alias := range its.byPath {
-		pkg := its.pkg.Imports()[importPath]
-		if pkg != nil && pkg.Name == alias {
-			res = append(res, fmt.Sprintf("%q", importPath))
-		} else {
-			res = append(res, fmt.Sprintf("%s %q", alias, importPath))
+// NewImports creates an import manager for a file generated into the package pkgPath.
+// The reserved names, e.g. the package's own identifiers, are never handed out as aliases.
+func NewImports(pkgPath string, reserved ...string) *Imports {
+	its := &Imports{
+		byPath:  make(sets.StringMap),
+		byAlias: make(sets.StringMap),
+		pkgPath: pkgPath,
+	}
+
+	for _, name := range types.Universe.Names() {
+		its.Reserve(name)
+	}
+	for tok := token.BREAK; tok <= token.VAR; tok++ {
+		its.Reserve(tok.String())
+	}
+	its.Reserve(reserved...)
+
+	return its
+}
+
+// NewPackageImports creates an import manager for a file generated into pkg,
+// reserving every package-level identifier of pkg.
+func NewPackageImports(pkg *loader.Package) *Imports {
+	its := NewImports(pkg.PkgPath, packageIdents(pkg)...)
+	its.pkg = pkg
+	if pkg.Module != nil {
+		its.module = pkg.Module.Path
+	}
+
+	return its
+}
+
+// WithModule sets the module path used to group local imports last.
+func (its *Imports) WithModule(module string) *Imports {
+	its.module = module
+
+	return its
+}
+
+func (its *Imports) Reserve(names ...string) {
+	for _, name := range names {
+		if _, exists := its.byAlias[name]; !exists {
+			its.byAlias[name] = ""
 		}
 	}
+}
+
+func (its *Imports) ImportSpecs() []string {
+	paths := its.sortedPaths()
+	res := make([]string, 0, len(paths))
+	for _, importPath := range paths {
+		res = append(res, its.importSpec(importPath))
+	}
+
 	return res
 }
 
+// ImportBlock renders the import declaration grouped as stdlib, third-party and local module,
+// each group sorted by path. It is empty when nothing was imported.
+func (its *Imports) ImportBlock() string {
+	paths := its.sortedPaths()
+	if len(paths) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for i, importPath := range paths {
+		if i > 0 && its.group(paths[i-1]) != its.group(importPath) {
+			b.WriteString("\n")
+		}
+		b.WriteString("\t")
+		b.WriteString(its.importSpec(importPath))
+		b.WriteString("\n")
+	}
+	b.WriteString(")\n")
+
+	return b.String()
+}
+
 func (its *Imports) NeedImport(importPath string) string {
 	if ind := strings.LastIndex(importPath, "/vendor/"); ind != -1 {
-		importPath = importPath[ind+8:/* len("/vendor/") */ ]
+		importPath = importPath[ind+8: /* len("/vendor/") */]
+	}
+	if importPath == its.pkgPath {
+		return ""
 	}
 	alias, exists := its.byPath[importPath]
 	if exists {
 		return alias
 	}
-	restPath, nextWord := path.Split(importPath)
+	restPath, nextWord := path.Split(trimMajorVersion(importPath))
 	for otherPath, exists := "", true; exists && otherPath != importPath; otherPath, exists = its.byAlias[alias] {
-		if restPath == "" {
+		if restPath == "" && alias != "" {
 			alias += "x"
 		}
 		for firstRune, runeLen := utf8.DecodeRuneInString(nextWord); unicode.IsDigit(firstRune); firstRune, runeLen = utf8.DecodeRuneInString(nextWord) {
@@ -81,3 +163,100 @@ func (its *Imports) NeedImport(importPath string) string {
 
 	return alias
 }
+
+func (its *Imports) importSpec(importPath string) string {
+	alias := its.byPath[importPath]
+	if its.packageName(importPath) == alias {
+		return fmt.Sprintf("%q", importPath)
+	}
+
+	return fmt.Sprintf("%s %q", alias, importPath)
+}
+
+// packageName is the name of the imported package when it is known for sure, otherwise empty.
+func (its *Imports) packageName(importPath string) string {
+	if its.pkg != nil {
+		if pkg := its.pkg.Imports()[importPath]; pkg != nil {
+			return pkg.Name
+		}
+	}
+	if its.group(importPath) == importGroupStd {
+		return path.Base(trimMajorVersion(importPath))
+	}
+
+	return ""
+}
+
+// trimMajorVersion chops off the major version suffix of a module path, e.g. `/v2` of `math/rand/v2`,
+// which is not the name of the package.
+func trimMajorVersion(importPath string) string {
+	rest, last := path.Split(importPath)
+	if rest == "" || len(last) < 2 || last[0] != 'v' {
+		return importPath
+	}
+	for _, r := range last[1:] {
+		if !unicode.IsDigit(r) {
+			return importPath
+		}
+	}
+
+	return rest[:len(rest)-1]
+}
+
+func (its *Imports) group(importPath string) int {
+	if its.module != "" && (importPath == its.module || strings.HasPrefix(importPath, its.module+"/")) {
+		return importGroupLocal
+	}
+	if first := strings.SplitN(importPath, "/", 2)[0]; !strings.Contains(first, ".") {
+		return importGroupStd
+	}
+
+	return importGroupThirdParty
+}
+
+func (its *Imports) sortedPaths() []string {
+	paths := make([]string, 0, len(its.byPath))
+	for importPath := range its.byPath {
+		paths = append(paths, importPath)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		gi, gj := its.group(paths[i]), its.group(paths[j])
+		if gi != gj {
+			return gi < gj
+		}
+		return paths[i] < paths[j]
+	})
+
+	return paths
+}
+
+func packageIdents(pkg *loader.Package) []string {
+	pkg.NeedSyntax()
+	idents := make([]string, 0)
+	for _, file := range pkg.Syntax {
+		if file == nil {
+			continue
+		}
+		for _, d := range file.Decls {
+			switch decl := d.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					idents = append(idents, decl.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch specVal := spec.(type) {
+					case *ast.TypeSpec:
+						idents = append(idents, specVal.Name.Name)
+					case *ast.ValueSpec:
+						for _, name := range specVal.Names {
+							idents = append(idents, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return idents
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImports_NeedImport(t *testing.T) {
+	its := NewImports("github.com/photowey/parsergo/tests/structx", "structx", "HelloService")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "github.com/photowey/parsergo/tests/structx", want: ""},
+		{path: "strings", want: "strings"},
+		{path: "github.com/other/structx", want: "otherstructx"},
+		{path: "github.com/photowey/parsergo/tests/testx", want: "testx"},
+		{path: "github.com/another/testx", want: "anothertestx"},
+		{path: "example.com/pkg/type", want: "pkgtype"},
+		{path: "math/rand/v2", want: "rand"},
+		{path: "math/rand", want: "mathrandx"},
+		{path: "github.com/other/rand/v3", want: "otherrand"},
+		{path: "example.com/pkg/v2x", want: "v2x"},
+		{path: "strings", want: "strings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := its.NeedImport(tt.path); got != tt.want {
+				t.Errorf("NeedImport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImports_ImportBlock(t *testing.T) {
+	its := NewImports("github.com/photowey/parsergo/gen").WithModule("github.com/photowey/parsergo")
+	for _, importPath := range []string{
+		"github.com/photowey/parsergo/astx",
+		"gopkg.in/yaml.v3",
+		"strings",
+		"golang.org/x/tools/go/packages",
+		"fmt",
+		"math/rand/v2",
+	} {
+		its.NeedImport(importPath)
+	}
+
+	wantSpecs := []string{
+		`"fmt"`,
+		`"math/rand/v2"`,
+		`"strings"`,
+		`packages "golang.org/x/tools/go/packages"`,
+		`yaml_v3 "gopkg.in/yaml.v3"`,
+		`astx "github.com/photowey/parsergo/astx"`,
+	}
+	if got := its.ImportSpecs(); !reflect.DeepEqual(got, wantSpecs) {
+		t.Errorf("ImportSpecs() = %v, want %v", got, wantSpecs)
+	}
+
+	want := "import (\n" +
+		"\t\"fmt\"\n" +
+		"\t\"math/rand/v2\"\n" +
+		"\t\"strings\"\n" +
+		"\n" +
+		"\tpackages \"golang.org/x/tools/go/packages\"\n" +
+		"\tyaml_v3 \"gopkg.in/yaml.v3\"\n" +
+		"\n" +
+		"\tastx \"github.com/photowey/parsergo/astx\"\n" +
+		")\n"
+	if got := its.ImportBlock(); got != want {
+		t.Errorf("ImportBlock() = %v, want %v", got, want)
+	}
+}
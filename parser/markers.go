@@ -0,0 +1,304 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/photowey/parsergo/astx"
+)
+
+type Target string
+
+const (
+	TargetPackage   Target = "package"
+	TargetStruct    Target = "struct"
+	TargetInterface Target = "interface"
+	TargetField     Target = "field"
+	TargetMethod    Target = "method"
+	TargetFunc      Target = "func"
+)
+
+// MarkerDefinition declares a kubebuilder-style marker, e.g. `kubebuilder:validation:Minimum`.
+// Registered names let the parser split `+name:arg=value` into the marker name and its args.
+type MarkerDefinition struct {
+	Name       string
+	Targets    []Target
+	Repeatable bool // e.g. `kubebuilder:rbac`, never reported as conflicting
+	Help       string
+}
+
+// Allows reports whether the marker may annotate target, any target when Targets is empty.
+func (def *MarkerDefinition) Allows(target Target) bool {
+	if len(def.Targets) == 0 {
+		return true
+	}
+	for _, allowed := range def.Targets {
+		if allowed == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+type MarkerRegistry struct {
+	definitions map[string]*MarkerDefinition
+	sync.RWMutex
+}
+
+var _markers_ = NewMarkerRegistry()
+
+func NewMarkerRegistry() *MarkerRegistry {
+	return &MarkerRegistry{
+		definitions: make(map[string]*MarkerDefinition),
+	}
+}
+
+// RegisterMarker adds the definitions to the default registry.
+func RegisterMarker(defs ...*MarkerDefinition) {
+	_markers_.Register(defs...)
+}
+
+func LookupMarker(name string) *MarkerDefinition {
+	return _markers_.Lookup(name)
+}
+
+// Definitions returns the definitions of the default registry, sorted by name.
+func Definitions() []*MarkerDefinition {
+	return _markers_.Definitions()
+}
+
+func (reg *MarkerRegistry) Register(defs ...*MarkerDefinition) {
+	reg.Lock()
+	defer reg.Unlock()
+
+	for _, def := range defs {
+		if registered := reg.definitions[def.Name]; registered != nil {
+			def = merge(registered, def)
+		}
+		reg.definitions[def.Name] = def
+	}
+}
+
+// merge combines the definitions of a name shared by several generators,
+// e.g. `@Options` of a builder struct and of an OPTIONS route method.
+func merge(registered, def *MarkerDefinition) *MarkerDefinition {
+	merged := &MarkerDefinition{
+		Name:       def.Name,
+		Repeatable: registered.Repeatable || def.Repeatable,
+		Help:       registered.Help,
+	}
+	switch {
+	case merged.Help == "":
+		merged.Help = def.Help
+	case def.Help != "" && def.Help != registered.Help:
+		merged.Help += "; " + def.Help
+	}
+	if len(registered.Targets) > 0 && len(def.Targets) > 0 {
+		merged.Targets = append(merged.Targets, registered.Targets...)
+		for _, target := range def.Targets {
+			if !registered.Allows(target) {
+				merged.Targets = append(merged.Targets, target)
+			}
+		}
+	}
+
+	return merged
+}
+
+func (reg *MarkerRegistry) Lookup(name string) *MarkerDefinition {
+	reg.RLock()
+	defer reg.RUnlock()
+
+	return reg.definitions[name]
+}
+
+func (reg *MarkerRegistry) Definitions() []*MarkerDefinition {
+	reg.RLock()
+	defer reg.RUnlock()
+
+	defs := make([]*MarkerDefinition, 0, len(reg.definitions))
+	for _, def := range reg.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Name < defs[j].Name
+	})
+
+	return defs
+}
+
+// Parse parses `+name`, `+name=value` and `+name:arg=value,arg2={a,b}`.
+// The longest registered name wins; unregistered markers fall back to the text before `=`.
+func (reg *MarkerRegistry) Parse(text string) *astx.Annotation {
+	body := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), "+"))
+	marker := &astx.Annotation{
+		Name:   body,
+		Marker: true,
+	}
+
+	head := body
+	if eq := strings.Index(body, "="); eq >= 0 {
+		head = body[:eq]
+	}
+	segments := strings.Split(head, ":")
+
+	for i := len(segments); i > 0; i-- {
+		name := strings.Join(segments[:i], ":")
+		if reg.Lookup(name) == nil {
+			continue
+		}
+
+		marker.Name = name
+		rest := body[len(name):]
+		switch {
+		case strings.HasPrefix(rest, "="):
+			marker.Values = rest[1:]
+			marker.Args = parseArgs(marker.Values)
+		case strings.HasPrefix(rest, ":"):
+			marker.Values = rest[1:]
+			marker.Args = parseArgs(marker.Values)
+		}
+
+		return marker
+	}
+
+	if head == body {
+		return marker
+	}
+
+	marker.Name = head
+	marker.Values = body[len(head)+1:]
+	marker.Args = parseArgs(marker.Values)
+
+	// `+name:arg=value,arg2=value2`: the last segment is the first arg's name
+	if len(segments) > 1 && len(marker.Args) > 1 && marker.Args[1].Name != "" {
+		last := segments[len(segments)-1]
+		marker.Name = strings.Join(segments[:len(segments)-1], ":")
+		marker.Values = last + "=" + marker.Values
+		marker.Args[0].Name = last
+	}
+
+	return marker
+}
+
+// parseArgs splits `value`, `a=1,b="x"` or `{a,b}` at top-level commas.
+// Positional values get an empty name.
+func parseArgs(values string) []*astx.AnnotationArg {
+	values = strings.TrimSpace(values)
+	if values == "" {
+		return nil
+	}
+
+	args := make([]*astx.AnnotationArg, 0)
+	for _, piece := range splitTopLevel(values, ',') {
+		piece = strings.TrimSpace(piece)
+		arg := &astx.AnnotationArg{}
+		if eq := strings.Index(piece, "="); eq > 0 && isArgName(piece[:eq]) {
+			arg.Name = strings.TrimSpace(piece[:eq])
+			piece = strings.TrimSpace(piece[eq+1:])
+		}
+		arg.Value = unquote(piece)
+		arg.Values = parseList(piece)
+		args = append(args, arg)
+	}
+
+	return args
+}
+
+// parseList parses `{a,b}` and `{a;b}` lists, JSON objects are left alone.
+func parseList(value string) []string {
+	if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+		return nil
+	}
+
+	inner := value[1 : len(value)-1]
+	if len(splitTopLevel(inner, ':')) > 1 {
+		return nil
+	}
+
+	sep := ','
+	if !strings.Contains(inner, ",") && strings.Contains(inner, ";") {
+		sep = ';'
+	}
+	items := make([]string, 0)
+	for _, item := range splitTopLevel(inner, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, unquote(item))
+		}
+	}
+
+	return items
+}
+
+func splitTopLevel(text string, sep rune) []string {
+	pieces := make([]string, 0)
+	depth := 0
+	var quote rune
+	start := 0
+	for i, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote && (i == 0 || text[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '`' || r == '\'':
+			quote = r
+		case r == '{' || r == '[' || r == '(':
+			depth++
+		case r == '}' || r == ']' || r == ')':
+			depth--
+		case r == sep && depth == 0:
+			pieces = append(pieces, text[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(pieces, text[start:])
+}
+
+func isArgName(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '.' || r == '-'))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '`' || value[0] == '\'') {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		if value[0] == '\'' && value[len(value)-1] == '\'' {
+			return value[1 : len(value)-1]
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarkerRegistry_Parse(t *testing.T) {
+	reg := NewMarkerRegistry()
+	reg.Register(
+		&MarkerDefinition{Name: "kubebuilder:validation:Minimum", Targets: []Target{TargetField}},
+		&MarkerDefinition{Name: "kubebuilder:printcolumn", Targets: []Target{TargetStruct}},
+	)
+
+	type arg struct {
+		name   string
+		value  string
+		values []string
+	}
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs []arg
+	}{
+		{text: "+genclient", wantName: "genclient"},
+		{text: "+kubebuilder:validation:Minimum=1", wantName: "kubebuilder:validation:Minimum", wantArgs: []arg{{value: "1"}}},
+		{
+			text:     `+kubebuilder:printcolumn:name="Age",type=date,priority=0`,
+			wantName: "kubebuilder:printcolumn",
+			wantArgs: []arg{{name: "name", value: "Age"}, {name: "type", value: "date"}, {name: "priority", value: "0"}},
+		},
+		{
+			text:     "+kubebuilder:validation:Enum={a,b}",
+			wantName: "kubebuilder:validation:Enum",
+			wantArgs: []arg{{value: "{a,b}", values: []string{"a", "b"}}},
+		},
+		{
+			text:     "+unregistered:group:arg=value,arg2={a,b}",
+			wantName: "unregistered:group",
+			wantArgs: []arg{{name: "arg", value: "value"}, {name: "arg2", value: "{a,b}", values: []string{"a", "b"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			marker := reg.Parse(tt.text)
+			if !marker.Marker || marker.Name != tt.wantName {
+				t.Errorf("Parse() name = %v, want %v", marker.Name, tt.wantName)
+			}
+			got := make([]arg, 0)
+			for _, a := range marker.Args {
+				got = append(got, arg{name: a.Name, value: a.Value, values: a.Values})
+			}
+			if len(got) != len(tt.wantArgs) || len(got) > 0 && !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("Parse() args = %+v, want %+v", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestMarkerRegistry_Register(t *testing.T) {
+	reg := NewMarkerRegistry()
+	reg.Register(
+		&MarkerDefinition{Name: "Options", Targets: []Target{TargetMethod}, Help: "an OPTIONS route"},
+		&MarkerDefinition{Name: "Options", Targets: []Target{TargetStruct}, Help: "option funcs"},
+		&MarkerDefinition{Name: "Any"},
+		&MarkerDefinition{Name: "Any", Targets: []Target{TargetField}},
+	)
+
+	options := reg.Lookup("Options")
+	if !options.Allows(TargetMethod) || !options.Allows(TargetStruct) || options.Allows(TargetField) {
+		t.Errorf("Register() options targets = %v, want method and struct", options.Targets)
+	}
+	if options.Help != "an OPTIONS route; option funcs" {
+		t.Errorf("Register() options help = %q", options.Help)
+	}
+	if !reg.Lookup("Any").Allows(TargetPackage) {
+		t.Errorf("Register() any targets = %v, want any target", reg.Lookup("Any").Targets)
+	}
+}
+
+func TestParseSource_PackageMarkers(t *testing.T) {
+	src := `// +groupName=apps.photowey.com
+// +kubebuilder:object:generate=true
+
+// Package v1 contains the API types.
+package v1
+`
+	ps, err := ParseSource("doc.go", []byte(src), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	fs := ps.Files[0]
+	if fs.Doc.Summary != "Package v1 contains the API types." {
+		t.Errorf("package doc summary = %q", fs.Doc.Summary)
+	}
+	names := make([]string, 0)
+	for _, anno := range fs.Annotations {
+		names = append(names, anno.Name+"="+anno.Value())
+	}
+	if want := []string{"groupName=apps.photowey.com", "kubebuilder:object:generate=true"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("package markers = %v, want %v", names, want)
+	}
+}
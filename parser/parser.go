@@ -23,29 +23,48 @@ import (
 
 type Parser interface {
 	Parse(pkg *loader.Package) *astx.AstSpec
+	FileParser
 	StructParser
 	InterfaceParser
 	MethodParser
 	FuncParser
 	AnnotationParser
+	HandlerRegistry
 }
 
+type FileParser interface {
+	ParseFileSpec(aw *astx.Astx) *astx.FileSpec
+}
+
+// StructParser parses the structs of a file and their methods, ParseAnnotations then collects their annotations.
 type StructParser interface {
-	ParseStructs(aw *astx.Astx) *astx.PackageSpec
+	ParseStructs(aw *astx.Astx) *astx.FileSpec
 }
 
 type InterfaceParser interface {
-	ParseInterfaces(aw *astx.Astx, ps *astx.PackageSpec)
+	ParseInterfaces(aw *astx.Astx, fs *astx.FileSpec)
 }
 
 type MethodParser interface {
-	ParseMethods(aw *astx.Astx, ps *astx.PackageSpec)
+	ParseMethods(aw *astx.Astx, fs *astx.FileSpec)
 }
 
 type FuncParser interface {
-	ParseFuncs(aw *astx.Astx, ps *astx.PackageSpec)
+	ParseFuncs(aw *astx.Astx, fs *astx.FileSpec)
 }
 
 type AnnotationParser interface {
-	ParseAnnotations(aw *astx.Astx, ps *astx.PackageSpec)
+	ParseAnnotations(aw *astx.Astx, fs *astx.FileSpec)
+}
+
+// HandlerRegistry registers extra extraction steps, run per node kind once a file is parsed.
+// Handlers registered on the Default parser run on every later parse of the process,
+// register them on a NewParser handed to the scanner with parsergo.WithParser instead.
+type HandlerRegistry interface {
+	OnStruct(handlers ...StructHandler)
+	OnInterface(handlers ...InterfaceHandler)
+	OnMethod(handlers ...MethodHandler)
+	OnFunc(handlers ...FuncHandler)
+	OnField(handlers ...FieldHandler)
+	OnComment(handlers ...CommentHandler)
 }
@@ -0,0 +1,63 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+)
+
+func ParseFile(path string, overlay map[string][]byte) (*astx.PackageSpec, error) {
+	lpkg, err := loader.LoadFile(path, overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSingle(lpkg)
+}
+
+func ParseSource(filename string, src []byte, overlay map[string][]byte) (*astx.PackageSpec, error) {
+	lpkg, err := loader.LoadSource(filename, src, overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSingle(lpkg)
+}
+
+func parseSingle(lpkg *loader.Package) (*astx.PackageSpec, error) {
+	aw := astx.NewAstx(lpkg.CompiledGoFiles[0], lpkg)
+	if aw.Ast == nil {
+		errs := make([]error, 0, len(lpkg.Errors))
+		for _, err := range lpkg.Errors {
+			errs = append(errs, err)
+		}
+		if len(errs) == 0 {
+			return nil, fmt.Errorf("parser: no syntax for %s", aw.Path)
+		}
+
+		return nil, errors.Join(errs...)
+	}
+
+	ps := populatePackageSpec(lpkg)
+	appendFileSpec(ps, _parser_.ParseFileSpec(aw))
+
+	return ps, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+const helloSource = `package hello
+
+// HelloServiceImpl An implementation of the HelloService interface
+// @Service("helloService")
+type HelloServiceImpl struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func (s *HelloServiceImpl) SayHello(name string) string {
+	return "Hello " + name
+}
+`
+
+func TestParseSource(t *testing.T) {
+	ps, err := ParseSource("hello.go", []byte(helloSource), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	if ps.Alias != "hello" {
+		t.Errorf("ParseSource() alias = %v, want %v", ps.Alias, "hello")
+	}
+	if len(ps.Structs) != 1 {
+		t.Fatalf("ParseSource() structs = %v, want %v", len(ps.Structs), 1)
+	}
+
+	ss := ps.Structs[0]
+	if ss.Name != "HelloServiceImpl" || len(ss.Fields) != 1 || len(ss.Methods) != 1 {
+		t.Errorf("ParseSource() struct = %s fields:%d methods:%d", ss.Name, len(ss.Fields), len(ss.Methods))
+	}
+	if len(ss.Annotations) != 1 || ss.Annotations[0].Name != "Service" || ss.Annotations[0].Values != `"helloService"` {
+		t.Errorf("ParseSource() annotations = %v", ss.Annotations)
+	}
+}
+
+func TestParseFile_Overlay(t *testing.T) {
+	path := "/virtual/unsaved/hello.go"
+	ps, err := ParseFile(path, map[string][]byte{
+		path: []byte(helloSource),
+	})
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	if len(ps.Structs) != 1 {
+		t.Errorf("ParseFile() structs = %v, want %v", len(ps.Structs), 1)
+	}
+}
+
+func TestParseSource_Positions(t *testing.T) {
+	ps, err := ParseSource("hello.go", []byte("//go:build linux\n\n"+helloSource), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	if len(ps.Files) != 1 {
+		t.Fatalf("ParseSource() files = %v, want %v", len(ps.Files), 1)
+	}
+
+	fs := ps.Files[0]
+	if len(fs.Constraints) != 1 || fs.Constraints[0] != "linux" {
+		t.Errorf("ParseSource() constraints = %v, want [linux]", fs.Constraints)
+	}
+
+	ss := fs.Structs[0]
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "struct", got: ss.Position.Line, want: 7},
+		{name: "annotation", got: ss.Annotations[0].Position.Line, want: 6},
+		{name: "field", got: ss.Fields[0].Position.Line, want: 8},
+		{name: "method", got: ss.Methods[0].Position.Line, want: 11},
+		{name: "param", got: ss.Methods[0].Params[0].Position.Line, want: 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("position of %s: got line %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+	if ss.Position.File != fs.Path {
+		t.Errorf("position file = %v, want %v", ss.Position.File, fs.Path)
+	}
+}
+
+const storeSource = `package store
+
+import (
+	"context"
+	"io"
+)
+
+// Store is a generic key-value store.
+type Store[K comparable, V any] interface {
+	// Get returns the value of a key.
+	Get(ctx context.Context, key K) (V, error)
+	Put(ctx context.Context, key K, values ...V) error // Put appends the values.
+	io.Closer
+}
+`
+
+func TestParseSource_InterfaceMethods(t *testing.T) {
+	ps, err := ParseSource("store.go", []byte(storeSource), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	if len(ps.Interfaces) != 1 {
+		t.Fatalf("ParseSource() interfaces = %v, want %v", len(ps.Interfaces), 1)
+	}
+
+	is := ps.Interfaces[0]
+	if len(is.Methods) != 2 || len(is.Embeds) != 1 || is.Embeds[0].Render(nil) != "Closer" || is.Embeds[0].PkgPath != "io" {
+		t.Fatalf("ParseSource() interface = %s methods:%d embeds:%v", is.Name, len(is.Methods), is.Embeds)
+	}
+
+	get, put := is.Methods[0], is.Methods[1]
+	if get.Name != "Get" || get.Struct != "Store" || len(get.Params) != 2 || len(get.Returns) != 2 || get.Doc.Summary != "Get returns the value of a key." {
+		t.Errorf("ParseSource() method = %s params:%d returns:%d doc:%v", get.Name, len(get.Params), len(get.Returns), get.Doc)
+	}
+	if put.Params[2].Name != "values" || put.Params[2].Type != "...V" || put.Doc.Summary != "Put appends the values." {
+		t.Errorf("ParseSource() method = %s params:%v doc:%v", put.Name, put.Params, put.Doc)
+	}
+}
+
+func TestParseSource_SyntaxError(t *testing.T) {
+	broken := []byte("package hello\n\nfunc Broken( {\n")
+	if _, err := ParseSource("hello.go", broken, nil); err == nil {
+		t.Errorf("ParseSource() error = nil, want the syntax error")
+	}
+
+	path := "/virtual/unsaved/broken.go"
+	if _, err := ParseFile(path, map[string][]byte{path: broken}); err == nil {
+		t.Errorf("ParseFile() error = nil, want the syntax error")
+	}
+}
+
+func TestParseSource_GroupedTypes(t *testing.T) {
+	src := `package group
+
+// @Builder
+type (
+	// A documented in the group
+	// @Getter
+	A struct{}
+
+	B struct{}
+)
+
+// @Builder
+type C struct{}
+`
+	ps, err := ParseSource("group.go", []byte(src), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, ss := range ps.Structs {
+		names := make([]string, 0)
+		for _, anno := range ss.Annotations {
+			names = append(names, anno.Name)
+		}
+		got[ss.Name] = names
+	}
+	want := map[string][]string{
+		"A": {"Getter"},
+		"C": {"Builder"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSource() annotations = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+)
+
+// ParseTag parses a struct tag literal, back-quoted or double-quoted, into its `key:"value"` pairs
+// following the conventions of reflect.StructTag. The pairs before the first malformed one are returned with the error.
+func ParseTag(literal string) ([]*astx.Tag, error) {
+	tag, err := strconv.Unquote(literal)
+	if err != nil {
+		return make([]*astx.Tag, 0), fmt.Errorf("malformed struct tag literal %s", literal)
+	}
+
+	tags := make([]*astx.Tag, 0)
+	seen := make(map[string]bool)
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return tags, fmt.Errorf("bad syntax for struct tag pair %q", tag)
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return tags, fmt.Errorf("bad syntax for struct tag value of %s", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return tags, fmt.Errorf("bad syntax for struct tag value of %s", key)
+		}
+		tag = tag[i+1:]
+
+		if seen[key] {
+			return tags, fmt.Errorf("duplicate struct tag key %s", key)
+		}
+		seen[key] = true
+		tags = append(tags, &astx.Tag{
+			Name:  literal,
+			Key:   key,
+			Value: value,
+		})
+
+		if tag != "" && tag[0] != ' ' {
+			return tags, fmt.Errorf("missing space after struct tag value of %s", key)
+		}
+	}
+
+	return tags, nil
+}
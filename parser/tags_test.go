@@ -0,0 +1,96 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		literal  string
+		wantKeys []string
+		wantLast string
+		wantErr  bool
+	}{
+		{
+			name:     "pairs",
+			literal:  "`json:\"name,omitempty\" validate:\"min=1, max=10\"`",
+			wantKeys: []string{"json", "validate"},
+			wantLast: "min=1, max=10",
+		},
+		{
+			name:     "double-quoted",
+			literal:  `"json:\"name\""`,
+			wantKeys: []string{"json"},
+			wantLast: "name",
+		},
+		{
+			name:     "escaped quote",
+			literal:  "`default:\"a\\\"b\"`",
+			wantKeys: []string{"default"},
+			wantLast: `a"b`,
+		},
+		{
+			name:     "space after colon",
+			literal:  "`json: \"name\"`",
+			wantKeys: []string{},
+			wantErr:  true,
+		},
+		{
+			name:     "unquoted value",
+			literal:  "`json:\"name\" db:id`",
+			wantKeys: []string{"json"},
+			wantLast: "name",
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate",
+			literal:  "`json:\"a\" json:\"b\"`",
+			wantKeys: []string{"json"},
+			wantLast: "a",
+			wantErr:  true,
+		},
+		{
+			name:     "missing space",
+			literal:  "`json:\"a\"db:\"b\"`",
+			wantKeys: []string{"json"},
+			wantLast: "a",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := ParseTag(tt.literal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tags) != len(tt.wantKeys) {
+				t.Fatalf("ParseTag() = %d tags, want %v", len(tags), tt.wantKeys)
+			}
+			for i, tag := range tags {
+				if tag.Key != tt.wantKeys[i] {
+					t.Errorf("ParseTag()[%d].Key = %s, want %s", i, tag.Key, tt.wantKeys[i])
+				}
+			}
+			if len(tags) > 0 && tags[len(tags)-1].Value != tt.wantLast {
+				t.Errorf("ParseTag() last value = %q, want %q", tags[len(tags)-1].Value, tt.wantLast)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+)
+
+// resolveType resolves expr in the scope of the file,
+// typeParams naming the type parameters the parser doesn't resolve, i.e. those of a method receiver.
+func resolveType(fs *astx.FileSpec, expr ast.Expr, typeParams ...string) *astx.TypeRef {
+	ref := &astx.TypeRef{
+		Kind: astx.KindUnknown,
+		Expr: types.ExprString(expr),
+	}
+
+	switch x := expr.(type) {
+	case *ast.Ident:
+		ref.Name = x.Name
+		if obj := types.Universe.Lookup(x.Name); obj != nil {
+			if _, ok := obj.(*types.TypeName); ok {
+				ref.Kind = astx.KindBasic
+				break
+			}
+		}
+		if isTypeParam(x, typeParams) {
+			ref.Kind = astx.KindTypeParam
+			break
+		}
+		ref.Kind = astx.KindNamed
+		ref.PkgPath = identPkgPath(fs, x)
+	case *ast.SelectorExpr:
+		ref.Kind = astx.KindNamed
+		ref.Name = x.Sel.Name
+		if qualifier, ok := x.X.(*ast.Ident); ok {
+			ref.Alias = qualifier.Name
+			if is := fs.ImportByAlias(qualifier.Name); is != nil {
+				ref.PkgPath = is.Path
+			}
+		}
+	case *ast.StarExpr:
+		ref.Kind = astx.KindPointer
+		ref.Elem = resolveType(fs, x.X, typeParams...)
+	case *ast.ArrayType:
+		ref.Elem = resolveType(fs, x.Elt, typeParams...)
+		if x.Len == nil {
+			ref.Kind = astx.KindSlice
+		} else {
+			ref.Kind = astx.KindArray
+			ref.Len = types.ExprString(x.Len)
+		}
+	case *ast.MapType:
+		ref.Kind = astx.KindMap
+		ref.Key = resolveType(fs, x.Key, typeParams...)
+		ref.Elem = resolveType(fs, x.Value, typeParams...)
+	case *ast.ChanType:
+		ref.Kind = astx.KindChan
+		ref.Elem = resolveType(fs, x.Value, typeParams...)
+	case *ast.Ellipsis:
+		ref.Kind = astx.KindEllipsis
+		ref.Elem = resolveType(fs, x.Elt, typeParams...)
+	case *ast.ParenExpr:
+		return resolveType(fs, x.X, typeParams...)
+	case *ast.IndexExpr:
+		ref = resolveGeneric(fs, ref, typeParams, x.X, x.Index)
+	case *ast.IndexListExpr:
+		ref = resolveGeneric(fs, ref, typeParams, x.X, x.Indices...)
+	case *ast.FuncType:
+		ref.Kind = astx.KindFunc
+	case *ast.StructType:
+		ref.Kind = astx.KindStruct
+	case *ast.InterfaceType:
+		ref.Kind = astx.KindInterface
+	}
+
+	return ref
+}
+
+func resolveGeneric(fs *astx.FileSpec, ref *astx.TypeRef, typeParams []string, base ast.Expr, indices ...ast.Expr) *astx.TypeRef {
+	generic := resolveType(fs, base, typeParams...)
+	generic.Expr = ref.Expr
+	for _, index := range indices {
+		generic.TypeArgs = append(generic.TypeArgs, resolveType(fs, index, typeParams...))
+	}
+
+	return generic
+}
+
+// isTypeParam reports whether the ident refers to a type parameter of its declaration.
+func isTypeParam(ident *ast.Ident, typeParams []string) bool {
+	if ident.Obj != nil {
+		_, field := ident.Obj.Decl.(*ast.Field)
+		return ident.Obj.Kind == ast.Typ && field
+	}
+	for _, name := range typeParams {
+		if name == ident.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// identPkgPath is the import path of an unqualified type name: the package of the file,
+// the package of a dot import declaring it, or "" when a dot import of an unloaded package may declare it.
+func identPkgPath(fs *astx.FileSpec, ident *ast.Ident) string {
+	if ident.Obj != nil {
+		return fs.Pkg
+	}
+
+	resolved := true
+	for _, is := range fs.Imports {
+		if !is.Dot {
+			continue
+		}
+		if is.Names == nil {
+			resolved = false
+			continue
+		}
+		for _, name := range is.Names {
+			if name == ident.Name {
+				return is.Path
+			}
+		}
+	}
+	if !resolved {
+		return ""
+	}
+
+	return fs.Pkg
+}
+
+// receiverTypeParams names the type parameters of a method receiver, e.g. `K` and `V` of `*Cache[K, V]`.
+func receiverTypeParams(expr ast.Expr) []string {
+	names := make([]string, 0)
+	switch rt := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeParams(rt.X)
+	case *ast.ParenExpr:
+		return receiverTypeParams(rt.X)
+	case *ast.IndexExpr:
+		if ident, ok := rt.Index.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	case *ast.IndexListExpr:
+		for _, index := range rt.Indices {
+			if ident, ok := index.(*ast.Ident); ok {
+				names = append(names, ident.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func resolveImport(lpkg *loader.Package, spec *ast.ImportSpec) *astx.ImportSpec {
+	importPath, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		importPath = strings.Trim(spec.Path.Value, "`\"")
+	}
+
+	is := &astx.ImportSpec{
+		Path: importPath,
+	}
+	if spec.Name != nil {
+		is.Name = spec.Name.Name
+	}
+
+	switch is.Name {
+	case ".":
+		is.Dot = true
+		is.Names = exportedTypes(lpkg, importPath)
+	case "_":
+		is.Blank = true
+	case "":
+		is.Alias = importedName(lpkg, importPath)
+	default:
+		is.Alias = is.Name
+	}
+
+	return is
+}
+
+// importedName is the package name of importPath,
+// guessed from the path when the package was not loaded.
+func importedName(lpkg *loader.Package, importPath string) string {
+	if lpkg != nil {
+		if ipkg := lpkg.Imports()[importPath]; ipkg != nil && ipkg.Name != "" {
+			return ipkg.Name
+		}
+	}
+
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		// major version suffix, e.g. `github.com/x/y/v2`
+		name = path.Base(path.Dir(importPath))
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexAny(name, ".-"); i > 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// exportedTypes lists the exported type names of an imported package, nil when it was not loaded.
+func exportedTypes(lpkg *loader.Package, importPath string) []string {
+	if lpkg == nil {
+		return nil
+	}
+	ipkg := lpkg.Imports()[importPath]
+	if ipkg == nil || len(ipkg.CompiledGoFiles) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0)
+	if ipkg.Types != nil {
+		scope := ipkg.Types.Scope()
+		for _, name := range scope.Names() {
+			if _, ok := scope.Lookup(name).(*types.TypeName); ok && ast.IsExported(name) {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	ipkg.NeedSyntax()
+	for _, file := range ipkg.Syntax {
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					if ts := spec.(*ast.TypeSpec); ts.Name.IsExported() {
+						names = append(names, ts.Name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/photowey/parsergo/astx"
+	"github.com/photowey/parsergo/loader"
+)
+
+const importsSource = `package dto
+
+import (
+	_ "embed"
+	. "strings"
+	"time"
+
+	sx "github.com/photowey/parsergo/tests/structx"
+	"gopkg.in/yaml.v3"
+)
+
+// Order the order dto
+// @Dto
+type Order struct {
+	Service  *sx.HelloServiceImpl
+	Services []sx.HelloServiceImpl
+	Index    map[string]*sx.HelloServiceImpl
+	Created  time.Time
+	Node     yaml.Node
+	Local    Item
+	Builder  Builder
+	Count    int
+}
+
+type Item struct{}
+`
+
+func TestResolveImports(t *testing.T) {
+	ps, err := ParseSource("dto.go", []byte(importsSource), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	fs := ps.Files[0]
+	tests := []struct {
+		path  string
+		alias string
+		dot   bool
+		blank bool
+	}{
+		{path: "embed", blank: true},
+		{path: "strings", dot: true},
+		{path: "time", alias: "time"},
+		{path: "github.com/photowey/parsergo/tests/structx", alias: "sx"},
+		{path: "gopkg.in/yaml.v3", alias: "yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			is := fs.ImportByPath(tt.path)
+			if is == nil {
+				t.Fatalf("import %s not found", tt.path)
+			}
+			if is.Alias != tt.alias || is.Dot != tt.dot || is.Blank != tt.blank {
+				t.Errorf("import %s = %+v", tt.path, is)
+			}
+		})
+	}
+}
+
+func TestResolveType(t *testing.T) {
+	ps, err := ParseSource("dto.go", []byte(importsSource), nil)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	qualifier := func(pkgPath string) string {
+		switch pkgPath {
+		case "github.com/photowey/parsergo/tests/structx":
+			return "structx"
+		case "dto":
+			return "dto"
+		case "gopkg.in/yaml.v3":
+			return "yaml"
+		}
+		return pkgPath
+	}
+	tests := []struct {
+		field   string
+		pkgPath string
+		want    string
+	}{
+		{field: "Service", pkgPath: "github.com/photowey/parsergo/tests/structx", want: "*structx.HelloServiceImpl"},
+		{field: "Services", pkgPath: "github.com/photowey/parsergo/tests/structx", want: "[]structx.HelloServiceImpl"},
+		{field: "Index", pkgPath: "github.com/photowey/parsergo/tests/structx", want: "map[string]*structx.HelloServiceImpl"},
+		{field: "Created", pkgPath: "time", want: "time.Time"},
+		{field: "Node", pkgPath: "gopkg.in/yaml.v3", want: "yaml.Node"},
+		{field: "Local", pkgPath: "dto", want: "dto.Item"},
+		{field: "Builder", pkgPath: "", want: "Builder"},
+		{field: "Count", pkgPath: "", want: "int"},
+	}
+
+	fields := ps.Structs[0].Fields
+	for i, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			fds := fields[i]
+			paths := fds.TypeRef.PkgPaths()
+			if tt.pkgPath == "" && len(paths) != 0 || tt.pkgPath != "" && (len(paths) != 1 || paths[0] != tt.pkgPath) {
+				t.Errorf("field %s package paths = %v, want %v", tt.field, paths, tt.pkgPath)
+			}
+			if got := fds.TypeRef.Render(qualifier); got != tt.want {
+				t.Errorf("field %s rendered = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveType_Scope(t *testing.T) {
+	roots, err := loader.LoadRoots("github.com/photowey/parsergo/tests/typex")
+	if err != nil {
+		t.Fatalf("LoadRoots() error = %v", err)
+	}
+	ps := Parse(roots[0]).Package
+
+	qualifier := func(pkgPath string) string {
+		return pkgPath
+	}
+	refs := make(map[string]*astx.TypeRef)
+	for _, ss := range ps.Structs {
+		for _, fds := range ss.Fields {
+			refs[ss.Name+"."+fds.Name] = fds.TypeRef
+		}
+		for _, ms := range ss.Methods {
+			for _, pms := range ms.Params {
+				refs[ss.Name+"."+ms.Name+"("+pms.Name+")"] = pms.TypeRef
+			}
+			for _, rs := range ms.Returns {
+				refs[ss.Name+"."+ms.Name+"()"] = rs.TypeRef
+			}
+		}
+	}
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "Schedule.Every", want: "time.Duration"},
+		{ref: "Schedule.At", want: "time.Month"},
+		{ref: "Schedule.Next", want: "*github.com/photowey/parsergo/tests/typex.Schedule"},
+		{ref: "Page.Items", want: "[]T"},
+		{ref: "Page.Total", want: "int"},
+		{ref: "Page.First()", want: "E"},
+		{ref: "Page.Append(items)", want: "...E"},
+		{ref: "Page.Append()", want: "*github.com/photowey/parsergo/tests/typex.Page[E]"},
+		{ref: "Schedule.After(d)", want: "time.Duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			ref := refs[tt.ref]
+			if ref == nil {
+				t.Fatalf("%s not found in %v", tt.ref, refs)
+			}
+			if got := ref.Render(qualifier); got != tt.want {
+				t.Errorf("%s rendered = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
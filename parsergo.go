@@ -17,55 +17,93 @@
 package parsergo
 
 import (
+	"errors"
+
 	"github.com/photowey/parsergo/astx"
 	"github.com/photowey/parsergo/loader"
 	"github.com/photowey/parsergo/parser"
+	"golang.org/x/tools/go/packages"
 )
 
 var _ PackageScanner = (*scanner)(nil)
 
 type Scanner interface {
-	Scan() []*astx.AstSpec
+	Scan() ([]*astx.AstSpec, error)
 }
 
 type PackageScanner interface {
 	Scanner
-	ScanPackages(rootPaths ...string) []*astx.AstSpec
+	// ScanPackages fails on the load and syntax errors of the packages, their type errors aside.
+	ScanPackages(rootPaths ...string) ([]*astx.AstSpec, error)
 }
 
 type scanner struct {
 	Paths []string
+
+	tags              []string
+	scanAutogenerated bool
+	goos              string
+	goarch            string
+	env               []string
+	dir               string
+	tests             bool
+	overlay           map[string][]byte
+	parser            parser.Parser
 }
 
-func (scr *scanner) Scan() []*astx.AstSpec {
+func (scr *scanner) Scan() ([]*astx.AstSpec, error) {
 	return scr.ScanPackages(scr.Paths...)
 }
 
-func (scr *scanner) ScanPackages(rootPaths ...string) []*astx.AstSpec {
+func (scr *scanner) ScanPackages(rootPaths ...string) ([]*astx.AstSpec, error) {
 	paths := toSlice(rootPaths...)
 	if len(paths) == 0 {
 		paths = append(paths, "./...")
 	}
 
-	roots, err := loader.LoadRoots(paths...)
+	roots, err := loader.LoadRootsWithConfig(scr.config(), paths...)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	ass := make([]*astx.AstSpec, 0, len(roots))
+	psr := scr.parser
+	if psr == nil {
+		psr = parser.Default()
+	}
 
+	ass := make([]*astx.AstSpec, 0, len(roots))
+	errs := make([]error, 0)
 	for _, root := range roots {
-		as := parser.Parse(root)
+		if root.IsTestMain() {
+			continue
+		}
+		as := psr.Parse(root)
 		ass = append(ass, as)
+		for _, err := range root.Errors {
+			if err.Kind != packages.TypeError {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	return ass
+	return ass, nil
 }
 
 func NewScanner(rootPaths ...string) PackageScanner {
-	return &scanner{
-		Paths: toSlice(rootPaths...),
+	return NewScannerWithOptions(WithPaths(rootPaths...))
+}
+
+// NewScannerWithOptions configures the scanner, and its underlying packages.Config, with the opts.
+func NewScannerWithOptions(opts ...Option) PackageScanner {
+	scr := &scanner{}
+	for _, opt := range opts {
+		opt(scr)
 	}
+
+	return scr
 }
 
 func toSlice(rootPaths ...string) []string {
@@ -17,6 +17,9 @@
 package parsergo
 
 import (
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -42,7 +45,10 @@ func Test_scanner_Scan(t *testing.T) {
 			scr := &scanner{
 				Paths: []string{"./tests/structx"},
 			}
-			got := scr.Scan()
+			got, err := scr.Scan()
+			if err != nil {
+				t.Fatalf("scan the path:%s error: %v", scr.Paths, err)
+			}
 			if len(got) != tt.want {
 				t.Errorf("scan the path:%s error: got %v, want %v", scr.Paths, len(got), tt.want)
 			}
@@ -53,3 +59,182 @@ func Test_scanner_Scan(t *testing.T) {
 		})
 	}
 }
+
+func TestNewScannerWithOptions(t *testing.T) {
+	overlay, _ := filepath.Abs(filepath.Join("tests", "tagx", "tagx.go"))
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "test scanner without build tags",
+			opts: []Option{WithPaths("./tests/tagx")},
+			want: 1,
+		},
+		{
+			name: "test scanner with build tags",
+			opts: []Option{WithPaths("./tests/tagx"), WithBuildTags("parsergo_extra")},
+			want: 2,
+		},
+		{
+			name: "test scanner with GOOS only",
+			opts: []Option{WithPaths("./tests/tagx"), WithGOOS("windows"), WithGOARCH("amd64")},
+			want: 1,
+		},
+		{
+			name: "test scanner with GOOS and GOARCH",
+			opts: []Option{WithPaths("./tests/tagx"), WithGOOS("windows"), WithGOARCH("arm64")},
+			want: 2,
+		},
+		{
+			name: "test scanner with env",
+			opts: []Option{WithPaths("./tests/tagx"), WithEnv("GOOS=windows", "GOARCH=arm64")},
+			want: 2,
+		},
+		{
+			name: "test scanner with dir",
+			opts: []Option{WithDir("./tests/tagx"), WithPaths(".")},
+			want: 1,
+		},
+		{
+			name: "test scanner with overlay",
+			opts: []Option{WithPaths("./tests/tagx"), WithOverlay(map[string][]byte{
+				overlay: []byte("package tagx\n\n// Plain is overlaid\ntype Plain struct{}\n\n// Overlaid only exists in the overlay\ntype Overlaid struct{}\n"),
+			})},
+			want: 2,
+		},
+		{
+			name: "test scanner with a syntax error in the overlay",
+			opts: []Option{WithPaths("./tests/tagx"), WithOverlay(map[string][]byte{
+				overlay: []byte("package tagx\n\ntype Plain struct{\n"),
+			})},
+			wantErr: true,
+		},
+		{
+			name:    "test scanner with a missing package",
+			opts:    []Option{WithPaths("./tests/missingx")},
+			wantErr: true,
+		},
+		{
+			name: "test scanner ignoring autogenerated files",
+			opts: []Option{WithPaths("./tests/builderx")},
+			want: 2,
+		},
+		{
+			name: "test scanner with autogenerated files",
+			opts: []Option{WithPaths("./tests/builderx"), WithIgnoreAutogenerated(false)},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewScannerWithOptions(tt.opts...).Scan()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scan with options error: %v, wantErr %v", err, tt.wantErr)
+			}
+			structs := 0
+			for _, as := range got {
+				structs += len(as.Package.Structs)
+			}
+			if structs != tt.want {
+				t.Errorf("scan with options error: got %v, want %v", structs, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewScanner(t *testing.T) {
+	got, err := NewScanner("./tests/tagx").Scan()
+	if err != nil {
+		t.Fatalf("scan the paths error: %v", err)
+	}
+	if len(got) != 1 || len(got[0].Package.Structs) != 1 {
+		t.Errorf("scan the paths error: got %v", got)
+	}
+}
+
+func TestNewScanner_WithTests(t *testing.T) {
+	ass, err := NewScannerWithOptions(WithPaths("./tests/testx"), WithTests(true)).Scan()
+	if err != nil {
+		t.Fatalf("scan with tests error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, as := range ass {
+		for _, fs := range as.Package.Files {
+			for _, ss := range fs.Structs {
+				got[ss.Name] = fs.Test
+			}
+		}
+	}
+
+	want := map[string]string{
+		"Repository":        "",
+		"MockRepository":    "test",
+		"RepositoryFixture": "xtest",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("scan with tests error: got %v, want %v", got, want)
+	}
+	for name, variant := range want {
+		if got[name] != variant {
+			t.Errorf("scan with tests error: struct %s got variant %q, want %q", name, got[name], variant)
+		}
+	}
+}
+
+func TestNewScanner_PackageAnnotations(t *testing.T) {
+	got, err := NewScanner("./tests/pkgx").Scan()
+	if err != nil {
+		t.Fatalf("scan the package annotations error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("scan the package annotations error: got %v packages, want 1", len(got))
+	}
+
+	ps := got[0].Package
+	if ps.Doc == nil || ps.Doc.Summary != "Package pkgx the package-level annotations fixture." {
+		t.Errorf("scan the package doc error: got %+v", ps.Doc)
+	}
+
+	names := make([]string, 0, len(ps.Annotations))
+	for _, anno := range ps.Annotations {
+		names = append(names, anno.Name)
+	}
+	want := []string{"Module", "Version", "ComponentScan"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("scan the package annotations error: got %v, want %v", names, want)
+	}
+	if len(ps.Diagnostics) != 1 {
+		t.Fatalf("scan the package annotations error: got diagnostics %v, want 1 conflict", ps.Diagnostics)
+	}
+	if got := ps.Diagnostics[0].Message; !strings.HasPrefix(got, `conflicting package annotation Version: "2" here, "1" at `) {
+		t.Errorf("scan the package annotations error: got the conflict %s", got)
+	}
+}
+
+func TestNewScanner_FileSpecs(t *testing.T) {
+	// a file without comments, structs nor funcs still gets its FileSpec
+	plain, _ := filepath.Abs(filepath.Join("tests", "tagx", "plain.go"))
+	got, err := NewScannerWithOptions(WithPaths("./tests/tagx"), WithOverlay(map[string][]byte{
+		plain: []byte("package tagx\n\nimport \"strings\"\n\nvar upper = strings.ToUpper\n"),
+	})).Scan()
+	if err != nil {
+		t.Fatalf("scan the file specs error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("scan the file specs error: got %v packages, want 1", len(got))
+	}
+
+	names := make([]string, 0)
+	for _, fs := range got[0].Package.Files {
+		names = append(names, fs.Name)
+		if fs.Name == "plain.go" && (len(fs.Imports) != 1 || fs.Imports[0].Path != "strings") {
+			t.Errorf("scan the file specs error: got imports %v, want strings", fs.Imports)
+		}
+	}
+	if want := []string{"plain.go", "tagx.go"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("scan the file specs error: got files %v, want %v", names, want)
+	}
+}
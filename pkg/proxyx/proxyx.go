@@ -0,0 +1,120 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxyx
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Annotation is a method annotation, as written: `@Retry(3)` or `@Cached(ttl=5m)`.
+type Annotation struct {
+	Name  string
+	Value string            // the positional arg, if any
+	Args  map[string]string // the named args
+}
+
+// Invocation is a call of a proxied method.
+type Invocation struct {
+	// Context is the first param of the method when it is a context, context.Background() otherwise.
+	Context     context.Context
+	Type        string // the proxied type, e.g. `UserService`
+	Method      string
+	Annotations []*Annotation
+	Args        []interface{}
+	// Results points to the results of the method, e.g. for a cache to read and set them.
+	Results []interface{}
+}
+
+func (inv *Invocation) Annotation(name string) *Annotation {
+	for _, anno := range inv.Annotations {
+		if anno.Name == name {
+			return anno
+		}
+	}
+
+	return nil
+}
+
+// Interceptor runs around a proxied method, calling next to proceed, any number of times.
+// next returns the error result of the method, if any.
+type Interceptor interface {
+	Intercept(inv *Invocation, next func() error) error
+}
+
+type InterceptorFunc func(inv *Invocation, next func() error) error
+
+func (fn InterceptorFunc) Intercept(inv *Invocation, next func() error) error {
+	return fn(inv, next)
+}
+
+// Interceptors maps the annotation names to their interceptors.
+type Interceptors map[string]Interceptor
+
+// Invoke runs the interceptors of the annotations of inv around call, the first annotation outermost.
+// Annotations without an interceptor are skipped.
+func Invoke(inv *Invocation, interceptors Interceptors, call func() error) error {
+	chain := call
+	for i := len(inv.Annotations) - 1; i >= 0; i-- {
+		interceptor := interceptors[inv.Annotations[i].Name]
+		if interceptor == nil {
+			continue
+		}
+		next := chain
+		chain = func() error {
+			return interceptor.Intercept(inv, next)
+		}
+	}
+
+	return chain()
+}
+
+// Retry calls the method up to the number of attempts of `@Retry(n)`, until it succeeds or the context is done.
+func Retry() Interceptor {
+	return InterceptorFunc(func(inv *Invocation, next func() error) error {
+		attempts := 1
+		if anno := inv.Annotation("Retry"); anno != nil {
+			if n, err := strconv.Atoi(anno.Value); err == nil && n > 0 {
+				attempts = n
+			}
+		}
+
+		var err error
+		for i := 0; i < attempts; i++ {
+			if err = next(); err == nil {
+				return nil
+			}
+			if inv.Context.Err() != nil {
+				return err
+			}
+		}
+
+		return err
+	})
+}
+
+// Timed reports the duration and the error of every call to observe.
+func Timed(observe func(inv *Invocation, elapsed time.Duration, err error)) Interceptor {
+	return InterceptorFunc(func(inv *Invocation, next func() error) error {
+		start := time.Now()
+		err := next()
+		observe(inv, time.Since(start), err)
+
+		return err
+	})
+}
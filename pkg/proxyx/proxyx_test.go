@@ -0,0 +1,106 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxyx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvoke(t *testing.T) {
+	var trace []string
+	tracing := func(name string) Interceptor {
+		return InterceptorFunc(func(inv *Invocation, next func() error) error {
+			trace = append(trace, name)
+			return next()
+		})
+	}
+	interceptors := Interceptors{"A": tracing("a"), "B": tracing("b")}
+
+	tests := []struct {
+		name        string
+		annotations []*Annotation
+		want        string
+	}{
+		{name: "none", want: "call"},
+		{name: "first outermost", annotations: []*Annotation{{Name: "B"}, {Name: "A"}}, want: "b,a,call"},
+		{name: "unknown skipped", annotations: []*Annotation{{Name: "A"}, {Name: "Cached"}}, want: "a,call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace = nil
+			inv := &Invocation{Context: context.Background(), Annotations: tt.annotations}
+			_ = Invoke(inv, interceptors, func() error {
+				trace = append(trace, "call")
+				return nil
+			})
+			if got := strings.Join(trace, ","); got != tt.want {
+				t.Errorf("Invoke() trace = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		value     string
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "succeeds", ctx: context.Background(), value: "3", failures: 2, wantCalls: 3},
+		{name: "exhausted", ctx: context.Background(), value: "3", failures: 5, wantCalls: 3, wantErr: true},
+		{name: "invalid once", ctx: context.Background(), value: "x", failures: 5, wantCalls: 1, wantErr: true},
+		{name: "canceled", ctx: canceled, value: "3", failures: 5, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			inv := &Invocation{Context: tt.ctx, Annotations: []*Annotation{{Name: "Retry", Value: tt.value}}}
+			err := Invoke(inv, Interceptors{"Retry": Retry()}, func() error {
+				calls++
+				if calls <= tt.failures {
+					return errors.New("failed")
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr || calls != tt.wantCalls {
+				t.Errorf("Invoke() error = %v after %d calls, want error %v after %d", err, calls, tt.wantErr, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTimed(t *testing.T) {
+	want := errors.New("failed")
+	var observed error
+	inv := &Invocation{Context: context.Background(), Method: "Get", Annotations: []*Annotation{{Name: "Timed"}}}
+	timed := Timed(func(inv *Invocation, elapsed time.Duration, err error) {
+		observed = err
+	})
+	if err := Invoke(inv, Interceptors{"Timed": timed}, func() error { return want }); err != want || observed != want {
+		t.Errorf("Invoke() error = %v, observed %v, want %v", err, observed, want)
+	}
+}
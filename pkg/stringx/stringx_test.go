@@ -0,0 +1,40 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stringx
+
+import (
+	"testing"
+)
+
+func TestSnake(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "ServerConfig", want: "server_config"},
+		{name: "HTTPServer", want: "http_server"},
+		{name: "UserID", want: "user_id"},
+		{name: "config", want: "config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snake(tt.name); got != tt.want {
+				t.Errorf("Snake() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
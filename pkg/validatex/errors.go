@@ -0,0 +1,98 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validatex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a failed rule of a field, at its path such as `Address.Lines[0]`.
+type FieldError struct {
+	Path    string
+	Rule    string // e.g. `min=1`
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+// Errors aggregates the field errors of a struct, in field order.
+type Errors []*FieldError
+
+func (errs Errors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Err returns the errors, or nil without any.
+func (errs Errors) Err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+func (errs *Errors) Add(path, rule, message string) {
+	*errs = append(*errs, &FieldError{Path: path, Rule: rule, Message: message})
+}
+
+// Nest adds the errors of a nested value at path, e.g. those returned by its Validate method.
+func (errs *Errors) Nest(path string, err error) {
+	var nested Errors
+	if !errors.As(err, &nested) {
+		errs.Add(path, "", err.Error())
+		return
+	}
+	for _, fe := range nested {
+		errs.Add(Field(path, fe.Path), fe.Rule, fe.Message)
+	}
+}
+
+// Field joins the path of a struct and the path of one of its fields.
+func Field(path, field string) string {
+	switch {
+	case path == "":
+		return field
+	case field == "" || strings.HasPrefix(field, "["):
+		return path + field
+	}
+
+	return path + "." + field
+}
+
+// Index is the path of an element of a slice or array.
+func Index(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// Key is the path of an entry of a map.
+func Key(path string, key interface{}) string {
+	return fmt.Sprintf("%s[%v]", path, key)
+}
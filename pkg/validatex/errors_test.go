@@ -0,0 +1,72 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validatex
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors_Nest(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want string
+	}{
+		{
+			name: "Test nest field errors",
+			path: "Address",
+			err:  Errors{{Path: "Street", Rule: "required", Message: "is required"}, {Path: "Lines[0]", Rule: "min=1", Message: "must be at least 1"}},
+			want: "Address.Street: is required; Address.Lines[0]: must be at least 1",
+		},
+		{
+			name: "Test nest element errors",
+			path: "Tags",
+			err:  Errors{{Path: "[1]", Rule: "lowercase", Message: "must be lowercase"}},
+			want: "Tags[1]: must be lowercase",
+		},
+		{
+			name: "Test nest a plain error",
+			path: "Zip",
+			err:  errors.New("must have 5 digits"),
+			want: "Zip: must have 5 digits",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs Errors
+			errs.Nest(tt.path, tt.err)
+			if got := errs.Error(); got != tt.want {
+				t.Errorf("Nest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrors_Err(t *testing.T) {
+	var errs Errors
+	if err := errs.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+
+	errs.Add(Key(Index("Groups", 0), "home"), "required", "is required")
+	var got Errors
+	if !errors.As(errs.Err(), &got) || len(got) != 1 || got[0].Path != "Groups[0][home]" {
+		t.Errorf("Err() = %v, want the error at Groups[0][home]", errs.Err())
+	}
+}
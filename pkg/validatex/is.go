@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validatex
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+)
+
+var (
+	uuidRegexp         = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	alphaRegexp        = regexp.MustCompile(`^[a-zA-Z]+$`)
+	alphanumericRegexp = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	numericRegexp      = regexp.MustCompile(`^[-+]?[0-9]+(?:\.[0-9]+)?$`)
+	hostnameRegexp     = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9]))*$`)
+)
+
+// IsEmail reports whether s is a bare address, such as `alice@example.com`.
+func IsEmail(s string) bool {
+	address, err := mail.ParseAddress(s)
+
+	return err == nil && address.Address == s
+}
+
+// IsURL reports whether s is an absolute URL.
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
+
+func IsURI(s string) bool {
+	_, err := url.ParseRequestURI(s)
+
+	return err == nil
+}
+
+func IsUUID(s string) bool {
+	return uuidRegexp.MatchString(s)
+}
+
+func IsAlpha(s string) bool {
+	return alphaRegexp.MatchString(s)
+}
+
+func IsAlphanumeric(s string) bool {
+	return alphanumericRegexp.MatchString(s)
+}
+
+func IsNumeric(s string) bool {
+	return numericRegexp.MatchString(s)
+}
+
+// IsHostname reports whether s is an RFC 1123 hostname.
+func IsHostname(s string) bool {
+	return len(s) <= 253 && hostnameRegexp.MatchString(s)
+}
+
+func IsIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+func IsIPv4(s string) bool {
+	ip := net.ParseIP(s)
+
+	return ip != nil && ip.To4() != nil
+}
+
+func IsIPv6(s string) bool {
+	ip := net.ParseIP(s)
+
+	return ip != nil && ip.To4() == nil
+}
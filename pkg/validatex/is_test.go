@@ -0,0 +1,56 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validatex
+
+import (
+	"testing"
+)
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		name  string
+		is    func(string) bool
+		value string
+		want  bool
+	}{
+		{name: "Test email", is: IsEmail, value: "alice@example.com", want: true},
+		{name: "Test email with a display name", is: IsEmail, value: "Alice <alice@example.com>", want: false},
+		{name: "Test URL", is: IsURL, value: "https://example.com/a?b=c", want: true},
+		{name: "Test relative URL", is: IsURL, value: "/a/b", want: false},
+		{name: "Test URI", is: IsURI, value: "/a/b", want: true},
+		{name: "Test UUID", is: IsUUID, value: "123e4567-e89b-12d3-a456-426614174000", want: true},
+		{name: "Test short UUID", is: IsUUID, value: "123e4567-e89b-12d3-a456", want: false},
+		{name: "Test alpha", is: IsAlpha, value: "abc", want: true},
+		{name: "Test alpha with digits", is: IsAlpha, value: "abc1", want: false},
+		{name: "Test alphanumeric", is: IsAlphanumeric, value: "abc1", want: true},
+		{name: "Test numeric", is: IsNumeric, value: "-12.5", want: true},
+		{name: "Test numeric with letters", is: IsNumeric, value: "12a", want: false},
+		{name: "Test hostname", is: IsHostname, value: "api.example.com", want: true},
+		{name: "Test hostname with a leading hyphen", is: IsHostname, value: "-api.example.com", want: false},
+		{name: "Test IPv4", is: IsIPv4, value: "10.0.0.1", want: true},
+		{name: "Test IPv4 given an IPv6", is: IsIPv4, value: "::1", want: false},
+		{name: "Test IPv6", is: IsIPv6, value: "::1", want: true},
+		{name: "Test IP", is: IsIP, value: "10.0.0.256", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.is(tt.value); got != tt.want {
+				t.Errorf("Is(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validatex
+
+import (
+	"strings"
+)
+
+// Rule is a rule of a go-playground/validator `validate` tag, e.g. `required`, `min=1` or `oneof=red green`.
+type Rule struct {
+	Name  string
+	Param string
+}
+
+func (r *Rule) String() string {
+	if r.Param == "" {
+		return r.Name
+	}
+
+	return r.Name + "=" + r.Param
+}
+
+// Values splits the param at its spaces, single-quoted values may hold spaces, e.g. `oneof='a b' c`.
+func (r *Rule) Values() []string {
+	values := make([]string, 0)
+	param := strings.TrimSpace(r.Param)
+	for param != "" {
+		if param[0] == '\'' {
+			if end := strings.IndexByte(param[1:], '\''); end >= 0 {
+				values = append(values, param[1:end+1])
+				param = strings.TrimSpace(param[end+2:])
+				continue
+			}
+		}
+		end := strings.IndexByte(param, ' ')
+		if end < 0 {
+			end = len(param)
+		}
+		values = append(values, param[:end])
+		param = strings.TrimSpace(param[end:])
+	}
+
+	return values
+}
+
+// Parse splits a `validate` tag into its rules, in order.
+// `a|b` alternatives are kept as one rule named by the whole text, `0x2C` and `0x7C` are unescaped in params.
+func Parse(tag string) []*Rule {
+	rules := make([]*Rule, 0)
+	for _, text := range strings.Split(tag, ",") {
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+		rule := &Rule{Name: text}
+		if !strings.Contains(text, "|") {
+			if eq := strings.IndexByte(text, '='); eq > 0 {
+				rule.Name = text[:eq]
+				rule.Param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(text[eq+1:])
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// Dive splits the rules at the first `dive`, the rules after it apply to the elements.
+func Dive(rules []*Rule) ([]*Rule, []*Rule) {
+	for i, rule := range rules {
+		if rule.Name == "dive" {
+			return rules[:i], rules[i+1:]
+		}
+	}
+
+	return rules, nil
+}
+
+func Find(rules []*Rule, name string) *Rule {
+	for _, rule := range rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+
+	return nil
+}
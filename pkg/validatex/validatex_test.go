@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validatex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want []string
+	}{
+		{
+			name: "Test parse rules with params",
+			tag:  "required,min=1,max=64",
+			want: []string{"required", "min=1", "max=64"},
+		},
+		{
+			name: "Test parse dive and alternatives",
+			tag:  "omitempty,dive,hexcolor|rgb",
+			want: []string{"omitempty", "dive", "hexcolor|rgb"},
+		},
+		{
+			name: "Test parse escaped comma",
+			tag:  "contains=a0x2Cb",
+			want: []string{"contains=a,b"},
+		},
+		{
+			name: "Test parse empty tag",
+			tag:  "",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, rule := range Parse(tt.tag) {
+				got = append(got, rule.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_Values(t *testing.T) {
+	tests := []struct {
+		name  string
+		param string
+		want  []string
+	}{
+		{
+			name:  "Test split plain values",
+			param: "red green  blue",
+			want:  []string{"red", "green", "blue"},
+		},
+		{
+			name:  "Test split quoted values",
+			param: "'light blue' red",
+			want:  []string{"light blue", "red"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &Rule{Name: "oneof", Param: tt.param}
+			if got := rule.Values(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Values() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
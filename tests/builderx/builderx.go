@@ -0,0 +1,51 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builderx
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Server is an HTTP server config.
+//
+// @Builder
+// @Options
+type Server struct {
+	Name    string        `validate:"required"`
+	Host    string        `default:"localhost"`
+	Port    uint16        `default:"8080" validate:"required,min=1"`
+	TLS     bool          `default:"true"`
+	Timeout time.Duration `default:"1m30s"`
+	Ratio   float64       `default:"0.75"`
+	Tags    []string      `validate:"required,dive,required"`
+	Labels  map[string]string
+	Proxy   *url.URL
+	Handler func(path string) error
+	mu      sync.Mutex `builder:"-"`
+	limit   int        `default:"0x10"`
+}
+
+// retryPolicy is built with unexported options.
+//
+// @Options(prefix="WithRetry")
+type retryPolicy struct {
+	attempts int           `default:"3"`
+	backoff  time.Duration `default:"500ms"`
+	codes    []int
+}
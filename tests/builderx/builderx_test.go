@@ -0,0 +1,51 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builderx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServerBuilder(t *testing.T) {
+	if _, err := NewServerBuilder().Build(); err == nil || err.Error() != "Server: missing the required Name, Tags" {
+		t.Errorf("Build() error = %v, want the missing Name, Tags", err)
+	}
+
+	server, err := NewServerBuilder().Name("api").Port(9090).AddTags("a").AddTags("b").PutLabels("env", "dev").Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if server.Host != "localhost" || server.Port != 9090 || server.Timeout != 90*time.Second || len(server.Tags) != 2 || server.Labels["env"] != "dev" {
+		t.Errorf("Build() = %+v", server)
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	server, err := NewServer(WithName("api"), WithTags("a", "b"), WithLabels("env", "dev"), WithTLS(false))
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if server.Port != 8080 || server.TLS || server.limit != 16 || len(server.Tags) != 2 || server.Labels["env"] != "dev" {
+		t.Errorf("NewServer() = %+v", server)
+	}
+
+	policy, _ := newRetryPolicy(withRetryCodes(502, 503))
+	if policy.attempts != 3 || policy.backoff != 500*time.Millisecond || len(policy.codes) != 2 {
+		t.Errorf("newRetryPolicy() = %+v", policy)
+	}
+}
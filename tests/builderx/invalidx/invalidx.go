@@ -0,0 +1,38 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package invalidx
+
+// Config has invalid tags.
+//
+// @Builder
+type Config struct {
+	Port    int                `default:"http"`
+	Limits  [2]int             `default:"1"`
+	Hooks   map[string]int     `default:"x"`
+	Options struct{ F func() } `validate:"required"`
+}
+
+// Client declares its constructor already.
+//
+// @Options
+type Client struct {
+	Name string
+}
+
+func NewClient() *Client {
+	return &Client{}
+}
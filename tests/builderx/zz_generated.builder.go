@@ -0,0 +1,257 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo builder. DO NOT EDIT.
+
+package builderx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ServerBuilder builds a Server.
+type ServerBuilder struct {
+	target *Server
+}
+
+func NewServerBuilder() *ServerBuilder {
+	return &ServerBuilder{
+		target: &Server{
+			Host:    "localhost",
+			Port:    8080,
+			TLS:     true,
+			Timeout: 90 * time.Second,
+			Ratio:   0.75,
+			limit:   16,
+		},
+	}
+}
+
+func (b *ServerBuilder) Name(name string) *ServerBuilder {
+	b.target.Name = name
+	return b
+}
+
+func (b *ServerBuilder) Host(host string) *ServerBuilder {
+	b.target.Host = host
+	return b
+}
+
+func (b *ServerBuilder) Port(port uint16) *ServerBuilder {
+	b.target.Port = port
+	return b
+}
+
+func (b *ServerBuilder) TLS(tls bool) *ServerBuilder {
+	b.target.TLS = tls
+	return b
+}
+
+func (b *ServerBuilder) Timeout(timeout time.Duration) *ServerBuilder {
+	b.target.Timeout = timeout
+	return b
+}
+
+func (b *ServerBuilder) Ratio(ratio float64) *ServerBuilder {
+	b.target.Ratio = ratio
+	return b
+}
+
+func (b *ServerBuilder) Tags(tags []string) *ServerBuilder {
+	b.target.Tags = tags
+	return b
+}
+
+func (b *ServerBuilder) AddTags(tags ...string) *ServerBuilder {
+	b.target.Tags = append(b.target.Tags, tags...)
+	return b
+}
+
+func (b *ServerBuilder) Labels(labels map[string]string) *ServerBuilder {
+	b.target.Labels = labels
+	return b
+}
+
+func (b *ServerBuilder) PutLabels(key string, value string) *ServerBuilder {
+	if b.target.Labels == nil {
+		b.target.Labels = make(map[string]string)
+	}
+	b.target.Labels[key] = value
+	return b
+}
+
+func (b *ServerBuilder) Proxy(proxy *url.URL) *ServerBuilder {
+	b.target.Proxy = proxy
+	return b
+}
+
+func (b *ServerBuilder) Handler(handler func(path string) error) *ServerBuilder {
+	b.target.Handler = handler
+	return b
+}
+
+func (b *ServerBuilder) Limit(limit int) *ServerBuilder {
+	b.target.limit = limit
+	return b
+}
+
+// Build returns the Server, or an error if a required field is zero.
+func (b *ServerBuilder) Build() (*Server, error) {
+	missing := make([]string, 0)
+	if b.target.Name == "" {
+		missing = append(missing, "Name")
+	}
+	if b.target.Port == 0 {
+		missing = append(missing, "Port")
+	}
+	if len(b.target.Tags) == 0 {
+		missing = append(missing, "Tags")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Server: missing the required %s", strings.Join(missing, ", "))
+	}
+
+	return b.target, nil
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// NewServer returns a Server with its defaults and the opts, or an error if a required field is zero.
+func NewServer(opts ...ServerOption) (*Server, error) {
+	target := &Server{
+		Host:    "localhost",
+		Port:    8080,
+		TLS:     true,
+		Timeout: 90 * time.Second,
+		Ratio:   0.75,
+		limit:   16,
+	}
+	for _, opt := range opts {
+		opt(target)
+	}
+
+	missing := make([]string, 0)
+	if target.Name == "" {
+		missing = append(missing, "Name")
+	}
+	if target.Port == 0 {
+		missing = append(missing, "Port")
+	}
+	if len(target.Tags) == 0 {
+		missing = append(missing, "Tags")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Server: missing the required %s", strings.Join(missing, ", "))
+	}
+
+	return target, nil
+}
+
+func WithName(name string) ServerOption {
+	return func(target *Server) {
+		target.Name = name
+	}
+}
+
+func WithHost(host string) ServerOption {
+	return func(target *Server) {
+		target.Host = host
+	}
+}
+
+func WithPort(port uint16) ServerOption {
+	return func(target *Server) {
+		target.Port = port
+	}
+}
+
+func WithTLS(tls bool) ServerOption {
+	return func(target *Server) {
+		target.TLS = tls
+	}
+}
+
+func WithTimeout(timeout time.Duration) ServerOption {
+	return func(target *Server) {
+		target.Timeout = timeout
+	}
+}
+
+func WithRatio(ratio float64) ServerOption {
+	return func(target *Server) {
+		target.Ratio = ratio
+	}
+}
+
+// WithTags appends to the Tags.
+func WithTags(tags ...string) ServerOption {
+	return func(target *Server) {
+		target.Tags = append(target.Tags, tags...)
+	}
+}
+
+// WithLabels puts an entry of the Labels.
+func WithLabels(key string, value string) ServerOption {
+	return func(target *Server) {
+		if target.Labels == nil {
+			target.Labels = make(map[string]string)
+		}
+		target.Labels[key] = value
+	}
+}
+
+func WithProxy(proxy *url.URL) ServerOption {
+	return func(target *Server) {
+		target.Proxy = proxy
+	}
+}
+
+func WithHandler(handler func(path string) error) ServerOption {
+	return func(target *Server) {
+		target.Handler = handler
+	}
+}
+
+func WithLimit(limit int) ServerOption {
+	return func(target *Server) {
+		target.limit = limit
+	}
+}
+
+// retryPolicyOption configures a retryPolicy.
+type retryPolicyOption func(*retryPolicy)
+
+// newRetryPolicy returns a retryPolicy with its defaults and the opts, or an error if a required field is zero.
+func newRetryPolicy(opts ...retryPolicyOption) (*retryPolicy, error) {
+	target := &retryPolicy{
+		attempts: 3,
+		backoff:  500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(target)
+	}
+
+	return target, nil
+}
+
+func withRetryAttempts(attempts int) retryPolicyOption {
+	return func(target *retryPolicy) {
+		target.attempts = attempts
+	}
+}
+
+func withRetryBackoff(backoff time.Duration) retryPolicyOption {
+	return func(target *retryPolicy) {
+		target.backoff = backoff
+	}
+}
+
+// withRetryCodes appends to the codes.
+func withRetryCodes(codes ...int) retryPolicyOption {
+	return func(target *retryPolicy) {
+		target.codes = append(target.codes, codes...)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apix
+
+import (
+	"github.com/photowey/parsergo/tests/callx"
+)
+
+// GreetingHandler calls into the callx package
+// @Controller
+type GreetingHandler struct {
+	service *callx.GreetingService
+	greeter callx.Greeter
+}
+
+// Hello welcomes and greets
+// @Get
+func (h *GreetingHandler) Hello(name string) string {
+	return h.service.Welcome(name) + h.greeter.Greet(name)
+}
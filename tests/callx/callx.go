@@ -0,0 +1,53 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callx
+
+import (
+	"strings"
+)
+
+type Greeter interface {
+	Greet(name string) string
+}
+
+type EnglishGreeter struct{}
+
+func (g EnglishGreeter) Greet(name string) string {
+	return "Hello " + name
+}
+
+type LoudGreeter struct{}
+
+func (g *LoudGreeter) Greet(name string) string {
+	return strings.ToUpper(name)
+}
+
+// GreetingService greets people
+// @Service
+type GreetingService struct {
+	greeter Greeter
+}
+
+// Welcome welcomes a new user
+// @Audit
+func (s *GreetingService) Welcome(name string) string {
+	return s.greeter.Greet(normalize(name))
+}
+
+func normalize(name string) string {
+	return strings.TrimSpace(name)
+}
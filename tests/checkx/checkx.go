@@ -0,0 +1,92 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkx
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+type Level int
+
+// Signup is a sign-up form.
+//
+// @Validate
+type Signup struct {
+	Name     string            `validate:"required,min=2,max=32"`
+	Email    string            `validate:"required,email"`
+	Website  string            `validate:"omitempty,url"`
+	Age      uint8             `validate:"gte=18,lte=130"`
+	Level    Level             `validate:"oneof=1 2 3"`
+	Role     string            `validate:"oneof=admin user"`
+	Nickname *string           `validate:"omitempty,min=3"`
+	Tags     []string          `validate:"required,max=3,dive,lowercase,min=1"`
+	Labels   map[string]string `validate:"dive,required"`
+	Address  *Address          `validate:"required"`
+	Previous []Address
+	Contacts map[string]*Contact
+	Matrix   [][]int `validate:"dive,dive,gt=0"`
+	Accepted bool    `validate:"eq=true"`
+	Source   string  `validate:"startswith=web|startswith=app"`
+	Proxy    *url.URL
+	Born     time.Time `validate:"required"`
+	Origin   Point     `validate:"required"`
+	Extra    struct {
+		Code string `validate:"len=6,numeric"`
+	}
+	Ignored string `validate:"-"`
+	note    string
+}
+
+// Address is validated along with the Signup.
+type Address struct {
+	Street string   `validate:"required"`
+	Lines  []string `validate:"max=2"`
+	Zip    Zip
+}
+
+// Point is comparable, zero when both coordinates are.
+type Point struct {
+	X, Y int
+}
+
+type Contact struct {
+	Phone string `validate:"required,numeric"`
+	IP    string `validate:"omitempty,ip"`
+}
+
+// Zip validates itself.
+type Zip string
+
+func (z Zip) Validate() error {
+	if len(z) != 0 && len(z) != 5 {
+		return errInvalidZip
+	}
+
+	return nil
+}
+
+// Node is recursive.
+//
+// @Validate
+type Node struct {
+	ID       string  `validate:"uuid"`
+	Children []*Node `validate:"max=8"`
+}
+
+var errInvalidZip = errors.New("must have 5 digits")
@@ -0,0 +1,143 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/photowey/parsergo/pkg/validatex"
+)
+
+func newSignup() *Signup {
+	signup := &Signup{
+		Born:     time.Date(1990, time.May, 1, 0, 0, 0, 0, time.UTC),
+		Origin:   Point{X: 1},
+		Name:     "alice",
+		Email:    "alice@example.com",
+		Age:      30,
+		Level:    2,
+		Role:     "admin",
+		Tags:     []string{"go"},
+		Address:  &Address{Street: "Main St", Zip: "12345"},
+		Accepted: true,
+		Source:   "web",
+	}
+	signup.Extra.Code = "123456"
+
+	return signup
+}
+
+func TestSignup_Validate(t *testing.T) {
+	nickname := "al"
+	tests := []struct {
+		name   string
+		modify func(s *Signup)
+		want   []string
+	}{
+		{
+			name:   "Test valid signup",
+			modify: func(s *Signup) {},
+		},
+		{
+			name: "Test invalid fields",
+			modify: func(s *Signup) {
+				s.Name = ""
+				s.Email = "alice"
+				s.Website = "example"
+				s.Age = 12
+				s.Level = 4
+				s.Nickname = &nickname
+				s.Accepted = false
+				s.Source = "cli"
+				s.Extra.Code = "12a456"
+			},
+			want: []string{"Name", "Email", "Website", "Age", "Level", "Nickname", "Accepted", "Source", "Extra.Code"},
+		},
+		{
+			name: "Test invalid elements",
+			modify: func(s *Signup) {
+				s.Tags = []string{"go", "Rust"}
+				s.Labels = map[string]string{"env": ""}
+				s.Matrix = [][]int{{1}, {1, 0}}
+			},
+			want: []string{"Tags[1]", "Labels[env]", "Matrix[1][1]"},
+		},
+		{
+			name: "Test invalid nested structs",
+			modify: func(s *Signup) {
+				s.Address.Street = ""
+				s.Address.Zip = "123"
+				s.Previous = []Address{{Street: "Elm St", Lines: []string{"1", "2", "3"}}}
+				s.Contacts = map[string]*Contact{"home": {Phone: "12a"}, "work": nil}
+			},
+			want: []string{"Address.Street", "Address.Zip", "Previous[0].Lines", "Contacts[home].Phone"},
+		},
+		{
+			name: "Test missing address",
+			modify: func(s *Signup) {
+				s.Address = nil
+			},
+			want: []string{"Address"},
+		},
+		{
+			name: "Test zero structs",
+			modify: func(s *Signup) {
+				s.Born = time.Time{}
+				s.Origin = Point{}
+			},
+			want: []string{"Born", "Origin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signup := newSignup()
+			tt.modify(signup)
+			err := signup.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var errs validatex.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() error = %v, want a validatex.Errors", err)
+			}
+			got := make([]string, 0, len(errs))
+			for _, fe := range errs {
+				got = append(got, fe.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() error = %v, want the paths %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNode_Validate(t *testing.T) {
+	node := &Node{
+		ID:       "123e4567-e89b-12d3-a456-426614174000",
+		Children: []*Node{{ID: "123e4567-e89b-12d3-a456-426614174001"}, nil, {ID: "1"}},
+	}
+	if err := node.Validate(); err == nil || err.Error() != "Children[2].ID: must be a valid UUID" {
+		t.Errorf("Validate() error = %v, want Children[2].ID: must be a valid UUID", err)
+	}
+}
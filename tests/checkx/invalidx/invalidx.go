@@ -0,0 +1,41 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package invalidx
+
+// Form has rules which don't fit its fields.
+//
+// @Validate
+type Form struct {
+	Name   string `validate:"required,shiny"`
+	Active bool   `validate:"min=1"`
+	Count  int    `validate:"max=many"`
+	Period struct {
+		Days []int
+	} `validate:"required"`
+	Level int `validate:"dive,min=1"`
+}
+
+// Checked already validates itself.
+//
+// @Validate
+type Checked struct {
+	Name string `validate:"required"`
+}
+
+func (c *Checked) Validate() error {
+	return nil
+}
@@ -0,0 +1,188 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo validate. DO NOT EDIT.
+
+package checkx
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	validatex "github.com/photowey/parsergo/pkg/validatex"
+)
+
+// Validate checks the `validate` rules of the fields of the Signup.
+func (s *Signup) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	var errs validatex.Errors
+	switch {
+	case s.Name == "":
+		errs.Add("Name", "required", "is required")
+	case utf8.RuneCountInString(s.Name) < 2:
+		errs.Add("Name", "min=2", "must be at least 2 characters long")
+	case utf8.RuneCountInString(s.Name) > 32:
+		errs.Add("Name", "max=32", "must be at most 32 characters long")
+	}
+	switch {
+	case s.Email == "":
+		errs.Add("Email", "required", "is required")
+	case !validatex.IsEmail(s.Email):
+		errs.Add("Email", "email", "must be a valid email")
+	}
+	if s.Website != "" {
+		if !validatex.IsURL(s.Website) {
+			errs.Add("Website", "url", "must be a valid URL")
+		}
+	}
+	switch {
+	case s.Age < 18:
+		errs.Add("Age", "gte=18", "must be at least 18")
+	case s.Age > 130:
+		errs.Add("Age", "lte=130", "must be at most 130")
+	}
+	if s.Level != 1 && s.Level != 2 && s.Level != 3 {
+		errs.Add("Level", "oneof=1 2 3", "must be one of 1, 2, 3")
+	}
+	if s.Role != "admin" && s.Role != "user" {
+		errs.Add("Role", "oneof=admin user", "must be one of admin, user")
+	}
+	if s.Nickname != nil {
+		if utf8.RuneCountInString(*s.Nickname) < 3 {
+			errs.Add("Nickname", "min=3", "must be at least 3 characters long")
+		}
+	}
+	switch {
+	case len(s.Tags) == 0:
+		errs.Add("Tags", "required", "is required")
+	case len(s.Tags) > 3:
+		errs.Add("Tags", "max=3", "must contain at most 3 items")
+	}
+	for i := range s.Tags {
+		switch {
+		case s.Tags[i] != strings.ToLower(s.Tags[i]):
+			errs.Add(validatex.Index("Tags", i), "lowercase", "must be lowercase")
+		case utf8.RuneCountInString(s.Tags[i]) < 1:
+			errs.Add(validatex.Index("Tags", i), "min=1", "must be at least 1 characters long")
+		}
+	}
+	for key1, value1 := range s.Labels {
+		if value1 == "" {
+			errs.Add(validatex.Key("Labels", key1), "required", "is required")
+		}
+	}
+	if s.Address == nil {
+		errs.Add("Address", "required", "is required")
+	} else {
+		if err := s.Address.Validate(); err != nil {
+			errs.Nest("Address", err)
+		}
+	}
+	for i := range s.Previous {
+		if err := s.Previous[i].Validate(); err != nil {
+			errs.Nest(validatex.Index("Previous", i), err)
+		}
+	}
+	for key1, value1 := range s.Contacts {
+		if value1 != nil {
+			if err := value1.Validate(); err != nil {
+				errs.Nest(validatex.Key("Contacts", key1), err)
+			}
+		}
+	}
+	for i := range s.Matrix {
+		for j := range s.Matrix[i] {
+			if s.Matrix[i][j] <= 0 {
+				errs.Add(validatex.Index(validatex.Index("Matrix", i), j), "gt=0", "must be greater than 0")
+			}
+		}
+	}
+	if !s.Accepted {
+		errs.Add("Accepted", "eq=true", "must be true")
+	}
+	if !strings.HasPrefix(s.Source, "web") && !strings.HasPrefix(s.Source, "app") {
+		errs.Add("Source", "startswith=web|startswith=app", "must start with \"web\" or must start with \"app\"")
+	}
+	if s.Born.IsZero() {
+		errs.Add("Born", "required", "is required")
+	}
+	if s.Origin == (Point{}) {
+		errs.Add("Origin", "required", "is required")
+	}
+	switch {
+	case utf8.RuneCountInString(s.Extra.Code) != 6:
+		errs.Add("Extra.Code", "len=6", "must be 6 characters long")
+	case !validatex.IsNumeric(s.Extra.Code):
+		errs.Add("Extra.Code", "numeric", "must be numeric")
+	}
+
+	return errs.Err()
+}
+
+// Validate checks the `validate` rules of the fields of the Node.
+func (n *Node) Validate() error {
+	if n == nil {
+		return nil
+	}
+
+	var errs validatex.Errors
+	if !validatex.IsUUID(n.ID) {
+		errs.Add("ID", "uuid", "must be a valid UUID")
+	}
+	if len(n.Children) > 8 {
+		errs.Add("Children", "max=8", "must contain at most 8 items")
+	}
+	for i := range n.Children {
+		if n.Children[i] != nil {
+			if err := n.Children[i].Validate(); err != nil {
+				errs.Nest(validatex.Index("Children", i), err)
+			}
+		}
+	}
+
+	return errs.Err()
+}
+
+// Validate checks the `validate` rules of the fields of the Address.
+func (a *Address) Validate() error {
+	if a == nil {
+		return nil
+	}
+
+	var errs validatex.Errors
+	if a.Street == "" {
+		errs.Add("Street", "required", "is required")
+	}
+	if len(a.Lines) > 2 {
+		errs.Add("Lines", "max=2", "must contain at most 2 items")
+	}
+	if err := a.Zip.Validate(); err != nil {
+		errs.Nest("Zip", err)
+	}
+
+	return errs.Err()
+}
+
+// Validate checks the `validate` rules of the fields of the Contact.
+func (c *Contact) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	var errs validatex.Errors
+	switch {
+	case c.Phone == "":
+		errs.Add("Phone", "required", "is required")
+	case !validatex.IsNumeric(c.Phone):
+		errs.Add("Phone", "numeric", "must be numeric")
+	}
+	if c.IP != "" {
+		if !validatex.IsIP(c.IP) {
+			errs.Add("IP", "ip", "must be a valid IP address")
+		}
+	}
+
+	return errs.Err()
+}
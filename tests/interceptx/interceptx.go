@@ -0,0 +1,89 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interceptx
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type User struct {
+	ID   int64
+	Name string
+}
+
+// UserRepository stores the users.
+type UserRepository interface {
+	FindUser(ctx context.Context, id int64) (*User, error)
+	SaveUser(ctx context.Context, user *User) error
+	Count() int
+	Names(ids ...int64) []string
+}
+
+// userRepository is an in-memory UserRepository, failing the first lookups.
+//
+// @Repository(interface=UserRepository)
+type userRepository struct {
+	users map[int64]*User
+	fails int
+}
+
+// FindUser returns a user by id.
+//
+// @Retry(3)
+// @Timed
+func (r *userRepository) FindUser(ctx context.Context, id int64) (*User, error) {
+	if r.fails > 0 {
+		r.fails--
+		return nil, errors.New("unavailable")
+	}
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+// SaveUser stores a user.
+//
+// @Transactional
+// @Cached(ttl=5m)
+func (r *userRepository) SaveUser(ctx context.Context, user *User) error {
+	r.users[user.ID] = user
+
+	return nil
+}
+
+// Count returns the number of users.
+//
+// @Timed
+func (r *userRepository) Count() int {
+	return len(r.users)
+}
+
+func (r *userRepository) Names(ids ...int64) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			names = append(names, user.Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interceptx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/photowey/parsergo/pkg/proxyx"
+)
+
+func TestUserRepositoryProxy(t *testing.T) {
+	calls := make([]string, 0)
+	record := func(name string) proxyx.Interceptor {
+		return proxyx.InterceptorFunc(func(inv *proxyx.Invocation, next func() error) error {
+			calls = append(calls, name+" "+inv.Method)
+			return next()
+		})
+	}
+	timed := 0
+	repository := NewUserRepositoryProxy(&userRepository{users: map[int64]*User{}, fails: 2}, proxyx.Interceptors{
+		"Transactional": record("tx"),
+		"Cached": proxyx.InterceptorFunc(func(inv *proxyx.Invocation, next func() error) error {
+			calls = append(calls, "cache "+inv.Annotation("Cached").Args["ttl"])
+			return next()
+		}),
+		"Retry": proxyx.Retry(),
+		"Timed": proxyx.Timed(func(inv *proxyx.Invocation, elapsed time.Duration, err error) {
+			timed++
+		}),
+	})
+
+	if err := repository.SaveUser(context.Background(), &User{ID: 1, Name: "alice"}); err != nil {
+		t.Fatalf("SaveUser() error = %v", err)
+	}
+	if got := strings.Join(calls, ", "); got != "tx SaveUser, cache 5m" {
+		t.Errorf("SaveUser() interceptors = %s, want tx SaveUser, cache 5m", got)
+	}
+
+	user, err := repository.FindUser(context.Background(), 1)
+	if err != nil || user.Name != "alice" {
+		t.Errorf("FindUser() = %v, %v, want alice after the retries", user, err)
+	}
+	if _, err := repository.FindUser(context.Background(), 2); err != ErrNotFound {
+		t.Errorf("FindUser() error = %v, want %v", err, ErrNotFound)
+	}
+	if got := repository.Count(); got != 1 {
+		t.Errorf("Count() = %d, want 1", got)
+	}
+	if timed != 7 {
+		t.Errorf("timed calls = %d, want 7", timed)
+	}
+	if got := repository.Names(1, 2); len(got) != 1 || got[0] != "alice" {
+		t.Errorf("Names() = %v, want [alice]", got)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package invalidx
+
+// Api is implemented by the services.
+type Api interface {
+	Get() error
+}
+
+// Service has invalid interceptions.
+//
+// @Service(interface=Api)
+type Service struct{}
+
+// @Retry(x)
+func (s *Service) Get() error {
+	return nil
+}
+
+// @Cached(ttl=soon)
+// @Timed
+func (s *Service) Put() error {
+	return nil
+}
+
+// Bare declares no interface.
+//
+// @Component
+type Bare struct{}
+
+// @Timed
+func (b *Bare) Get() error {
+	return nil
+}
@@ -0,0 +1,101 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo proxy. DO NOT EDIT.
+
+package interceptx
+
+import (
+	"context"
+
+	proxyx "github.com/photowey/parsergo/pkg/proxyx"
+)
+
+// UserRepositoryProxy implements UserRepository, running the interceptors of the method annotations around userRepository.
+type UserRepositoryProxy struct {
+	target       UserRepository
+	interceptors proxyx.Interceptors
+}
+
+var _ UserRepository = (*UserRepositoryProxy)(nil)
+
+func NewUserRepositoryProxy(target UserRepository, interceptors proxyx.Interceptors) *UserRepositoryProxy {
+	return &UserRepositoryProxy{
+		target:       target,
+		interceptors: interceptors,
+	}
+}
+
+var userRepositoryProxyAnnotations = map[string][]*proxyx.Annotation{
+	"Count": {
+		{Name: "Timed"},
+	},
+	"FindUser": {
+		{Name: "Retry", Value: "3"},
+		{Name: "Timed"},
+	},
+	"SaveUser": {
+		{Name: "Transactional"},
+		{Name: "Cached", Args: map[string]string{"ttl": "5m"}},
+	},
+}
+
+func (p *UserRepositoryProxy) Count() int {
+	var r0 int
+	inv := &proxyx.Invocation{
+		Context:     context.Background(),
+		Type:        "userRepository",
+		Method:      "Count",
+		Annotations: userRepositoryProxyAnnotations["Count"],
+		Args:        []interface{}{},
+		Results:     []interface{}{&r0},
+	}
+	_ = proxyx.Invoke(inv, p.interceptors, func() error {
+		r0 = p.target.Count()
+		return nil
+	})
+
+	return r0
+}
+
+func (p *UserRepositoryProxy) FindUser(ctx context.Context, id int64) (*User, error) {
+	var (
+		r0 *User
+		r1 error
+	)
+	inv := &proxyx.Invocation{
+		Context:     ctx,
+		Type:        "userRepository",
+		Method:      "FindUser",
+		Annotations: userRepositoryProxyAnnotations["FindUser"],
+		Args:        []interface{}{ctx, id},
+		Results:     []interface{}{&r0},
+	}
+	r1 = proxyx.Invoke(inv, p.interceptors, func() error {
+		r0, r1 = p.target.FindUser(ctx, id)
+		return r1
+	})
+
+	return r0, r1
+}
+
+func (p *UserRepositoryProxy) Names(ids ...int64) []string {
+	return p.target.Names(ids...)
+}
+
+func (p *UserRepositoryProxy) SaveUser(ctx context.Context, user *User) error {
+	var r0 error
+	inv := &proxyx.Invocation{
+		Context:     ctx,
+		Type:        "userRepository",
+		Method:      "SaveUser",
+		Annotations: userRepositoryProxyAnnotations["SaveUser"],
+		Args:        []interface{}{ctx, user},
+		Results:     []interface{}{},
+	}
+	r0 = proxyx.Invoke(inv, p.interceptors, func() error {
+		r0 = p.target.SaveUser(ctx, user)
+		return r0
+	})
+
+	return r0
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"time"
+)
+
+type UserID int64
+
+type Status string
+
+type User struct {
+	ID        UserID
+	Name      string
+	Email     *string
+	Status    Status
+	Age       int32
+	Score     *float64
+	Address   Address
+	Previous  []*Address
+	Orders    []Order
+	Tags      []string
+	Groups    [][]Status
+	CreatedAt time.Time
+	password  string
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Order struct {
+	ID    int64
+	Total float64
+	Items []*Item
+}
+
+type Item struct {
+	SKU      string
+	Quantity int
+}
+
+func (u *User) Password() string {
+	return u.password
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package invalidx
+
+// Source has unmapped fields.
+//
+// @MapTo(Target)
+// @MapTo(Missing)
+type Source struct {
+	Name   string
+	Extra  string
+	Count  string
+	Ratio  float64
+	Size   int64
+	Signal complex128
+}
+
+type Target struct {
+	Name    string
+	Count   int
+	Ratio   int
+	Size    int32
+	Signal  float64
+	Comment string
+}
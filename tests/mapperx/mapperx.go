@@ -0,0 +1,75 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapperx
+
+import (
+	"time"
+
+	"github.com/photowey/parsergo/tests/mapperx/domain"
+)
+
+// UserDTO is the API representation of a user.
+//
+// @MapTo(domain.User, ignore={CreatedAt})
+type UserDTO struct {
+	ID       int64
+	FullName string `map:"Name"`
+	Email    string
+	Status   domain.Status
+	Age      *int16
+	Score    float64
+	Address  *AddressDTO
+	Previous []AddressDTO
+	Orders   []*OrderDTO
+	Tags     []string
+	Groups   [][]string
+	Version  int `map:"-"`
+}
+
+// AddressDTO is mapped both ways.
+//
+// @MapTo(domain.Address, name=ToAddress)
+type AddressDTO struct {
+	Street string
+	City   string
+}
+
+type OrderDTO struct {
+	ID    int64
+	Total float64
+	Items []ItemDTO
+}
+
+type ItemDTO struct {
+	SKU      string
+	Quantity int
+}
+
+// Event maps to a struct of its own package.
+//
+// @MapTo(AuditEvent)
+type Event struct {
+	At   time.Time
+	Kind string
+}
+
+type AuditEvent struct {
+	At   time.Time
+	Kind EventKind
+}
+
+type EventKind string
@@ -0,0 +1,71 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapperx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/photowey/parsergo/tests/mapperx/domain"
+)
+
+func TestUserDTOToUser(t *testing.T) {
+	age := int16(42)
+	src := &UserDTO{
+		ID:       7,
+		FullName: "Alice",
+		Email:    "alice@example.com",
+		Status:   "active",
+		Age:      &age,
+		Score:    0.5,
+		Address:  &AddressDTO{Street: "Main St", City: "Springfield"},
+		Previous: []AddressDTO{{City: "Shelbyville"}},
+		Orders:   []*OrderDTO{{ID: 1, Total: 9.5, Items: []ItemDTO{{SKU: "A", Quantity: 2}}}, nil},
+		Tags:     []string{"admin"},
+		Groups:   [][]string{{"a", "b"}, nil},
+		Version:  3,
+	}
+	email, score := "alice@example.com", 0.5
+	want := &domain.User{
+		ID:       7,
+		Name:     "Alice",
+		Email:    &email,
+		Status:   "active",
+		Age:      42,
+		Score:    &score,
+		Address:  domain.Address{Street: "Main St", City: "Springfield"},
+		Previous: []*domain.Address{{City: "Shelbyville"}},
+		Orders:   []domain.Order{{ID: 1, Total: 9.5, Items: []*domain.Item{{SKU: "A", Quantity: 2}}}, {}},
+		Tags:     []string{"admin"},
+		Groups:   [][]domain.Status{{"a", "b"}, nil},
+	}
+
+	if got := UserDTOToUser(src); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserDTOToUser() = %+v, want %+v", got, want)
+	}
+	if got := UserDTOToUser(nil); got != nil {
+		t.Errorf("UserDTOToUser(nil) = %v, want nil", got)
+	}
+}
+
+func TestEventToAuditEvent(t *testing.T) {
+	at := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := EventToAuditEvent(&Event{At: at, Kind: "login"}); got.At != at || got.Kind != "login" {
+		t.Errorf("EventToAuditEvent() = %+v", got)
+	}
+}
@@ -0,0 +1,117 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo mapper. DO NOT EDIT.
+
+package mapperx
+
+import (
+	"github.com/photowey/parsergo/tests/mapperx/domain"
+)
+
+// UserDTOToUser maps UserDTO to domain.User.
+func UserDTOToUser(src *UserDTO) *domain.User {
+	if src == nil {
+		return nil
+	}
+
+	dst := &domain.User{}
+	dst.ID = domain.UserID(src.ID)
+	dst.Name = src.FullName
+	v1 := new(string)
+	*v1 = src.Email
+	dst.Email = v1
+	dst.Status = src.Status
+	if src.Age != nil {
+		dst.Age = int32(*src.Age)
+	}
+	v2 := new(float64)
+	*v2 = src.Score
+	dst.Score = v2
+	if src.Address != nil {
+		dst.Address = *ToAddress(src.Address)
+	}
+	if src.Previous != nil {
+		dst.Previous = make([]*domain.Address, len(src.Previous))
+		for i := range src.Previous {
+			dst.Previous[i] = ToAddress(&src.Previous[i])
+		}
+	}
+	if src.Orders != nil {
+		dst.Orders = make([]domain.Order, len(src.Orders))
+		for i := range src.Orders {
+			if src.Orders[i] != nil {
+				dst.Orders[i] = *orderDTOToOrder(src.Orders[i])
+			}
+		}
+	}
+	dst.Tags = src.Tags
+	if src.Groups != nil {
+		dst.Groups = make([][]domain.Status, len(src.Groups))
+		for i := range src.Groups {
+			if src.Groups[i] != nil {
+				dst.Groups[i] = make([]domain.Status, len(src.Groups[i]))
+				for j := range src.Groups[i] {
+					dst.Groups[i][j] = domain.Status(src.Groups[i][j])
+				}
+			}
+		}
+	}
+
+	return dst
+}
+
+// ToAddress maps AddressDTO to domain.Address.
+func ToAddress(src *AddressDTO) *domain.Address {
+	if src == nil {
+		return nil
+	}
+
+	dst := &domain.Address{}
+	dst.Street = src.Street
+	dst.City = src.City
+
+	return dst
+}
+
+// EventToAuditEvent maps Event to AuditEvent.
+func EventToAuditEvent(src *Event) *AuditEvent {
+	if src == nil {
+		return nil
+	}
+
+	dst := &AuditEvent{}
+	dst.At = src.At
+	dst.Kind = EventKind(src.Kind)
+
+	return dst
+}
+
+func orderDTOToOrder(src *OrderDTO) *domain.Order {
+	if src == nil {
+		return nil
+	}
+
+	dst := &domain.Order{}
+	dst.ID = src.ID
+	dst.Total = src.Total
+	if src.Items != nil {
+		dst.Items = make([]*domain.Item, len(src.Items))
+		for i := range src.Items {
+			dst.Items[i] = itemDTOToItem(&src.Items[i])
+		}
+	}
+
+	return dst
+}
+
+func itemDTOToItem(src *ItemDTO) *domain.Item {
+	if src == nil {
+		return nil
+	}
+
+	dst := &domain.Item{}
+	dst.SKU = src.SKU
+	dst.Quantity = src.Quantity
+
+	return dst
+}
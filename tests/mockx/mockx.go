@@ -0,0 +1,52 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mockx
+
+import (
+	"context"
+	"io"
+)
+
+// Store is a generic key-value store.
+//
+// @Mock
+type Store[K comparable, V any] interface {
+	// Get returns the value of a key.
+	Get(ctx context.Context, key K) (V, error)
+	// Put appends the values of a key.
+	Put(ctx context.Context, key K, values ...V) error
+	io.Closer
+}
+
+// Logger logs.
+//
+// @Mock("FakeLogger")
+type Logger interface {
+	Logf(format string, args ...interface{})
+	Named
+}
+
+type Named interface {
+	Name() (name string)
+}
+
+// SpyLogger is a hand-written double, left alone by the generator.
+//
+// @Mock
+type SpyLogger struct {
+	Lines []string
+}
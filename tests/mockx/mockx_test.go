@@ -0,0 +1,58 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mockx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMockStore(t *testing.T) {
+	store := &MockStore[string, int]{}
+	store.GetReturns.R0 = 42
+	if got, err := store.Get(context.Background(), "answer"); got != 42 || err != nil {
+		t.Errorf("Get() = %v, %v, want 42, nil", got, err)
+	}
+
+	store.PutFunc = func(ctx context.Context, key string, values ...int) error {
+		return errors.New(key)
+	}
+	if err := store.Put(context.Background(), "full", 1, 2); err == nil || err.Error() != "full" {
+		t.Errorf("Put() error = %v, want full", err)
+	}
+	if len(store.GetCalls) != 1 || store.GetCalls[0].Key != "answer" {
+		t.Errorf("GetCalls = %v", store.GetCalls)
+	}
+	if len(store.PutCalls) != 1 || len(store.PutCalls[0].Values) != 2 {
+		t.Errorf("PutCalls = %v", store.PutCalls)
+	}
+}
+
+func TestFakeLogger(t *testing.T) {
+	var logger Logger = &FakeLogger{}
+	logger.Logf("%s: %d", "answer", 42)
+
+	fake := logger.(*FakeLogger)
+	fake.NameReturns.Name = "fake"
+	if got := logger.Name(); got != "fake" {
+		t.Errorf("Name() = %v, want fake", got)
+	}
+	if len(fake.LogfCalls) != 1 || fake.LogfCalls[0].Format != "%s: %d" || len(fake.LogfCalls[0].Args) != 2 {
+		t.Errorf("LogfCalls = %v", fake.LogfCalls)
+	}
+}
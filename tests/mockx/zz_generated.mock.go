@@ -0,0 +1,140 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo mock. DO NOT EDIT.
+
+package mockx
+
+import (
+	"context"
+	"sync"
+)
+
+// MockStore is a mock of Store.
+// A method records its call, then calls its Func field if set, otherwise returns its Returns field.
+type MockStore[K comparable, V any] struct {
+	mu sync.Mutex
+
+	GetFunc    func(ctx context.Context, key K) (V, error)
+	GetReturns struct {
+		R0 V
+		R1 error
+	}
+	GetCalls []struct {
+		Ctx context.Context
+		Key K
+	}
+	PutFunc    func(ctx context.Context, key K, values ...V) error
+	PutReturns struct {
+		R0 error
+	}
+	PutCalls []struct {
+		Ctx    context.Context
+		Key    K
+		Values []V
+	}
+	CloseFunc    func() error
+	CloseReturns struct {
+		R0 error
+	}
+	CloseCalls []struct{}
+}
+
+func _[K comparable, V any]() {
+	var _ Store[K, V] = (*MockStore[K, V])(nil)
+}
+
+func (m *MockStore[K, V]) Get(ctx context.Context, key K) (V, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, struct {
+		Ctx context.Context
+		Key K
+	}{Ctx: ctx, Key: key})
+	fn := m.GetFunc
+	returns := m.GetReturns
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, key)
+	}
+
+	return returns.R0, returns.R1
+}
+
+func (m *MockStore[K, V]) Put(ctx context.Context, key K, values ...V) error {
+	m.mu.Lock()
+	m.PutCalls = append(m.PutCalls, struct {
+		Ctx    context.Context
+		Key    K
+		Values []V
+	}{Ctx: ctx, Key: key, Values: values})
+	fn := m.PutFunc
+	returns := m.PutReturns
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, key, values...)
+	}
+
+	return returns.R0
+}
+
+func (m *MockStore[K, V]) Close() error {
+	m.mu.Lock()
+	m.CloseCalls = append(m.CloseCalls, struct{}{})
+	fn := m.CloseFunc
+	returns := m.CloseReturns
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	return returns.R0
+}
+
+// FakeLogger is a mock of Logger.
+// A method records its call, then calls its Func field if set, otherwise returns its Returns field.
+type FakeLogger struct {
+	mu sync.Mutex
+
+	LogfFunc  func(format string, args ...interface{})
+	LogfCalls []struct {
+		Format string
+		Args   []interface{}
+	}
+	NameFunc    func() string
+	NameReturns struct {
+		Name string
+	}
+	NameCalls []struct{}
+}
+
+var _ Logger = (*FakeLogger)(nil)
+
+func (m *FakeLogger) Logf(format string, args ...interface{}) {
+	m.mu.Lock()
+	m.LogfCalls = append(m.LogfCalls, struct {
+		Format string
+		Args   []interface{}
+	}{Format: format, Args: args})
+	fn := m.LogfFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		fn(format, args...)
+	}
+}
+
+func (m *FakeLogger) Name() string {
+	m.mu.Lock()
+	m.NameCalls = append(m.NameCalls, struct{}{})
+	fn := m.NameFunc
+	returns := m.NameReturns
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	return returns.Name
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package invalidx
+
+import (
+	"sync"
+)
+
+// Handler can't be compared nor deep copied.
+//
+// @Getter
+// @ToString(exclude={missing})
+// @Equals
+// @DeepCopy
+type Handler struct {
+	Name   string
+	Handle func() error
+	Next   interface{}
+}
+
+func (h *Handler) GetName() string {
+	return h.Name
+}
+
+// Guarded can't be deep copied.
+//
+// @DeepCopy
+type Guarded struct {
+	mu sync.Mutex
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectx
+
+import (
+	"sync"
+	"time"
+)
+
+type Role string
+
+// Account is a user account.
+//
+// @Getter
+// @Setter
+// @ToString(exclude={password})
+// @Equals(exclude={password})
+// @DeepCopy
+type Account struct {
+	ID        int64
+	Name      string
+	Role      Role
+	password  string
+	Email     *string
+	Tags      []string
+	Labels    map[string]string
+	Address   *Address
+	Previous  []Address
+	Groups    map[string][]*Address
+	Matrix    [][]int
+	Limits    [2]int
+	CreatedAt time.Time
+}
+
+// Address is a postal address.
+//
+// @ToString
+// @Equals
+// @DeepCopy
+type Address struct {
+	Street string
+	Lines  []string
+}
+
+// Session holds a lock, left out of its methods.
+//
+// @Getter
+// @ToString
+// @Equals
+type Session struct {
+	Token string
+	mu    sync.Mutex
+}
+
+// Token has a single accessor.
+type Token struct {
+	// @Getter
+	value string
+	// @Setter
+	Expires time.Time
+}
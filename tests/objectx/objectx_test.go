@@ -0,0 +1,111 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newAccount() *Account {
+	email := "alice@example.com"
+	account := &Account{
+		ID:        1,
+		Name:      "alice",
+		Role:      "admin",
+		Email:     &email,
+		Tags:      []string{"a"},
+		Labels:    map[string]string{"env": "dev"},
+		Address:   &Address{Street: "Main St", Lines: []string{"1"}},
+		Previous:  []Address{{Street: "Elm St"}},
+		Groups:    map[string][]*Address{"home": {{Street: "Oak St"}, nil}},
+		Matrix:    [][]int{{1, 2}, nil},
+		Limits:    [2]int{1, 2},
+		CreatedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	account.SetPassword("secret")
+
+	return account
+}
+
+func TestAccount_DeepCopy(t *testing.T) {
+	account := newAccount()
+	copied := account.DeepCopy()
+	if !copied.Equal(account) {
+		t.Fatalf("DeepCopy() = %v, want %v", copied, account)
+	}
+
+	*copied.Email = "bob@example.com"
+	copied.Tags[0] = "b"
+	copied.Labels["env"] = "prod"
+	copied.Address.Lines[0] = "2"
+	copied.Previous[0].Street = "Pine St"
+	copied.Groups["home"][0].Street = "Ash St"
+	copied.Matrix[0][0] = 9
+	if got := newAccount(); !got.Equal(account) {
+		t.Errorf("DeepCopy() shares memory, the original is now %v", account)
+	}
+	for _, modified := range []func(a *Account){
+		func(a *Account) { a.Tags = append(a.Tags, "b") },
+		func(a *Account) { a.Groups["home"][1] = &Address{} },
+		func(a *Account) { a.CreatedAt = a.CreatedAt.Add(time.Nanosecond) },
+		func(a *Account) { a.Email = nil },
+	} {
+		other := newAccount()
+		modified(other)
+		if other.Equal(account) || account.Equal(other) {
+			t.Errorf("Equal() = true, want false for %v", other)
+		}
+	}
+	other := newAccount()
+	other.CreatedAt = other.CreatedAt.In(time.FixedZone("UTC+1", 3600))
+	if !other.Equal(account) {
+		t.Errorf("Equal() = false, want true for the same instant in another zone")
+	}
+}
+
+func TestAccount_String(t *testing.T) {
+	account := &Account{ID: 1, Name: "alice", Address: &Address{Street: "Main St"}}
+	account.SetPassword("secret")
+	want := `Account{ID: 1, Name: "alice", Role: "", Email: <nil>, Tags: [], Labels: map[], Address: Address{Street: "Main St", Lines: []}, Previous: [], Groups: map[], Matrix: [], Limits: [0 0], CreatedAt: 0001-01-01 00:00:00 +0000 UTC}`
+	if got := account.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	email := "alice@example.com"
+	account.Email = &email
+	if got := account.String(); !strings.Contains(got, `Email: "alice@example.com"`) {
+		t.Errorf("String() = %s, want the email", got)
+	}
+	if got := (&Session{Token: "t"}).String(); got != `Session{Token: "t"}` {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	account := &Account{}
+	account.SetName("alice")
+	if account.GetName() != "alice" || account.Password() != "" {
+		t.Errorf("GetName() = %s, Password() = %s", account.GetName(), account.Password())
+	}
+
+	token := &Token{value: "t"}
+	token.SetExpires(time.Unix(0, 0))
+	if token.Value() != "t" || !token.Expires.Equal(time.Unix(0, 0)) {
+		t.Errorf("Value() = %s, Expires = %v", token.Value(), token.Expires)
+	}
+}
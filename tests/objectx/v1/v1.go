@@ -0,0 +1,50 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// +kubebuilder:object:generate=true
+
+// Package v1 contains the API types, deep copied as a whole.
+package v1
+
+type Spec struct {
+	Replicas *int32
+	Selector map[string]string
+	Template Template
+	Ports    []Port
+}
+
+type Template struct {
+	Labels     map[string]string
+	Containers []*Container
+}
+
+type Port struct {
+	Name string
+	Port int32
+}
+
+type Container struct {
+	Image string
+	Args  []string
+	Env   map[string]*string
+}
+
+// Status is copied as a value.
+//
+// +kubebuilder:object:generate=false
+type Status struct {
+	Ready bool
+}
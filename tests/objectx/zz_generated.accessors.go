@@ -0,0 +1,125 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo accessors. DO NOT EDIT.
+
+package objectx
+
+import (
+	"time"
+)
+
+func (a *Account) GetID() int64 {
+	return a.ID
+}
+
+func (a *Account) SetID(id int64) {
+	a.ID = id
+}
+
+func (a *Account) GetName() string {
+	return a.Name
+}
+
+func (a *Account) SetName(name string) {
+	a.Name = name
+}
+
+func (a *Account) GetRole() Role {
+	return a.Role
+}
+
+func (a *Account) SetRole(role Role) {
+	a.Role = role
+}
+
+func (a *Account) Password() string {
+	return a.password
+}
+
+func (a *Account) SetPassword(password string) {
+	a.password = password
+}
+
+func (a *Account) GetEmail() *string {
+	return a.Email
+}
+
+func (a *Account) SetEmail(email *string) {
+	a.Email = email
+}
+
+func (a *Account) GetTags() []string {
+	return a.Tags
+}
+
+func (a *Account) SetTags(tags []string) {
+	a.Tags = tags
+}
+
+func (a *Account) GetLabels() map[string]string {
+	return a.Labels
+}
+
+func (a *Account) SetLabels(labels map[string]string) {
+	a.Labels = labels
+}
+
+func (a *Account) GetAddress() *Address {
+	return a.Address
+}
+
+func (a *Account) SetAddress(address *Address) {
+	a.Address = address
+}
+
+func (a *Account) GetPrevious() []Address {
+	return a.Previous
+}
+
+func (a *Account) SetPrevious(previous []Address) {
+	a.Previous = previous
+}
+
+func (a *Account) GetGroups() map[string][]*Address {
+	return a.Groups
+}
+
+func (a *Account) SetGroups(groups map[string][]*Address) {
+	a.Groups = groups
+}
+
+func (a *Account) GetMatrix() [][]int {
+	return a.Matrix
+}
+
+func (a *Account) SetMatrix(matrix [][]int) {
+	a.Matrix = matrix
+}
+
+func (a *Account) GetLimits() [2]int {
+	return a.Limits
+}
+
+func (a *Account) SetLimits(limits [2]int) {
+	a.Limits = limits
+}
+
+func (a *Account) GetCreatedAt() time.Time {
+	return a.CreatedAt
+}
+
+func (a *Account) SetCreatedAt(createdAt time.Time) {
+	a.CreatedAt = createdAt
+}
+
+func (s *Session) GetToken() string {
+	return s.Token
+}
+
+func (t *Token) Value() string {
+	return t.value
+}
+
+func (t *Token) SetExpires(expires time.Time) {
+	t.Expires = expires
+}
@@ -0,0 +1,101 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo deepcopy. DO NOT EDIT.
+
+package objectx
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Account) DeepCopyInto(out *Account) {
+	*out = *in
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(Address)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Previous != nil {
+		in, out := &in.Previous, &out.Previous
+		*out = make([]Address, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make(map[string][]*Address, len(*in))
+		for key, val := range *in {
+			outVal := val
+			if val != nil {
+				in, out := &val, &outVal
+				*out = make([]*Address, len(*in))
+				copy(*out, *in)
+				for i := range *in {
+					if (*in)[i] != nil {
+						in, out := &(*in)[i], &(*out)[i]
+						*out = new(Address)
+						(*in).DeepCopyInto(*out)
+					}
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make([][]int, len(*in))
+		copy(*out, *in)
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]int, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Account.
+func (in *Account) DeepCopy() *Account {
+	if in == nil {
+		return nil
+	}
+	out := new(Account)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Address) DeepCopyInto(out *Address) {
+	*out = *in
+	if in.Lines != nil {
+		in, out := &in.Lines, &out.Lines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Address.
+func (in *Address) DeepCopy() *Address {
+	if in == nil {
+		return nil
+	}
+	out := new(Address)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,132 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo equals. DO NOT EDIT.
+
+package objectx
+
+// Equal reports whether the Account equals other, field by field.
+func (a *Account) Equal(other *Account) bool {
+	if a == nil || other == nil {
+		return a == other
+	}
+	if a.ID != other.ID {
+		return false
+	}
+	if a.Name != other.Name {
+		return false
+	}
+	if a.Role != other.Role {
+		return false
+	}
+	if (a.Email == nil) != (other.Email == nil) {
+		return false
+	}
+	if a.Email != nil {
+		if *a.Email != *other.Email {
+			return false
+		}
+	}
+	if len(a.Tags) != len(other.Tags) {
+		return false
+	}
+	for i := range a.Tags {
+		if a.Tags[i] != other.Tags[i] {
+			return false
+		}
+	}
+	if len(a.Labels) != len(other.Labels) {
+		return false
+	}
+	for key1, a1 := range a.Labels {
+		b1, ok := other.Labels[key1]
+		if !ok {
+			return false
+		}
+		if a1 != b1 {
+			return false
+		}
+	}
+	if !a.Address.Equal(other.Address) {
+		return false
+	}
+	if len(a.Previous) != len(other.Previous) {
+		return false
+	}
+	for i := range a.Previous {
+		if !a.Previous[i].Equal(&other.Previous[i]) {
+			return false
+		}
+	}
+	if len(a.Groups) != len(other.Groups) {
+		return false
+	}
+	for key1, a1 := range a.Groups {
+		b1, ok := other.Groups[key1]
+		if !ok {
+			return false
+		}
+		if len(a1) != len(b1) {
+			return false
+		}
+		for j := range a1 {
+			if !a1[j].Equal(b1[j]) {
+				return false
+			}
+		}
+	}
+	if len(a.Matrix) != len(other.Matrix) {
+		return false
+	}
+	for i := range a.Matrix {
+		if len(a.Matrix[i]) != len(other.Matrix[i]) {
+			return false
+		}
+		for j := range a.Matrix[i] {
+			if a.Matrix[i][j] != other.Matrix[i][j] {
+				return false
+			}
+		}
+	}
+	for i := range a.Limits {
+		if a.Limits[i] != other.Limits[i] {
+			return false
+		}
+	}
+	if !a.CreatedAt.Equal(other.CreatedAt) {
+		return false
+	}
+
+	return true
+}
+
+// Equal reports whether the Address equals other, field by field.
+func (a *Address) Equal(other *Address) bool {
+	if a == nil || other == nil {
+		return a == other
+	}
+	if a.Street != other.Street {
+		return false
+	}
+	if len(a.Lines) != len(other.Lines) {
+		return false
+	}
+	for i := range a.Lines {
+		if a.Lines[i] != other.Lines[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal reports whether the Session equals other, field by field.
+func (s *Session) Equal(other *Session) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	if s.Token != other.Token {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,25 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo tostring. DO NOT EDIT.
+
+package objectx
+
+import (
+	"fmt"
+)
+
+func (a Account) String() string {
+	email := "<nil>"
+	if a.Email != nil {
+		email = fmt.Sprintf("%q", *a.Email)
+	}
+	return fmt.Sprintf("Account{ID: %v, Name: %q, Role: %q, Email: %s, Tags: %v, Labels: %v, Address: %v, Previous: %v, Groups: %v, Matrix: %v, Limits: %v, CreatedAt: %v}", a.ID, a.Name, a.Role, email, a.Tags, a.Labels, a.Address, a.Previous, a.Groups, a.Matrix, a.Limits, a.CreatedAt)
+}
+
+func (a Address) String() string {
+	return fmt.Sprintf("Address{Street: %q, Lines: %v}", a.Street, a.Lines)
+}
+
+func (s *Session) String() string {
+	return fmt.Sprintf("Session{Token: %q}", s.Token)
+}
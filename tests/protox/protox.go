@@ -0,0 +1,80 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package protox holds the users service.
+//
+// @ProtoPackage("users.v1", go_package="github.com/photowey/parsergo/tests/protox/usersv1")
+package protox
+
+import (
+	"context"
+	"time"
+)
+
+// HelloService greets.
+//
+// @Proto
+type HelloService interface {
+	// SayHello greets someone by name.
+	SayHello(name string) string
+}
+
+// UserService manages the users.
+//
+// @Proto
+type UserService interface {
+	GetUser(ctx context.Context, req *GetUserRequest) (*User, error)
+	ListUsers(ctx context.Context, page, size int32) (users []*User, total int64, err error)
+	DeleteUser(ctx context.Context, id int64) error
+}
+
+type GetUserRequest struct {
+	ID int64
+}
+
+// User is a registered user.
+//
+// @Proto
+type User struct {
+	// @ProtoField(1)
+	ID int64
+	// Name is the display name.
+	Name      string
+	Email     *string
+	Tags      []string
+	Attrs     map[string]string
+	Role      Role
+	Address   *Address
+	Avatar    []byte
+	CreatedAt time.Time
+	TTL       time.Duration
+	Scores    map[int32]float64
+	secret    string
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+// Role is what a user may do.
+type Role int
+
+const (
+	// RoleMember is the default role.
+	RoleMember Role = iota + 1
+	RoleAdmin
+)
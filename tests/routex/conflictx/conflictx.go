@@ -0,0 +1,52 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conflictx
+
+// ItemController conflicts with itself.
+//
+// @Controller("/items")
+type ItemController struct{}
+
+// @Get("/{id}")
+func (c *ItemController) Get(id string) error {
+	return nil
+}
+
+// @Get("/{name}")
+func (c *ItemController) ByName(name string) error {
+	return nil
+}
+
+// @Post("/{id}")
+func (c *ItemController) Missing() error {
+	return nil
+}
+
+// UserController has routes matching some paths in common, neither being more specific.
+//
+// @Controller("/users")
+type UserController struct{}
+
+// @Get("/{id}/posts")
+func (c *UserController) Posts(id string) error {
+	return nil
+}
+
+// @Get("/me/{section}")
+func (c *UserController) Section(section string) error {
+	return nil
+}
@@ -0,0 +1,30 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package geox
+
+// Address is a postal address.
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city" validate:"required"`
+	// Point locates the address, if known.
+	Point *Point `json:"point,omitempty"`
+}
+
+type Point struct {
+	Lat float64 `json:"lat" validate:"gte=-90,lte=90"`
+	Lng float64 `json:"lng" validate:"gte=-180,lte=180"`
+}
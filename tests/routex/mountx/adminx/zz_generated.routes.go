@@ -0,0 +1,41 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo routes. DO NOT EDIT.
+
+package adminx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// RegisterItemControllerRoutes registers the routes of ItemController on mux.
+func RegisterItemControllerRoutes(mux *http.ServeMux, c *ItemController) {
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var arg0 string
+		if raw := r.PathValue("id"); raw != "" {
+			arg0 = raw
+		}
+		result, err := c.Get(arg0)
+		if err != nil {
+			routesWriteError(w, err, http.StatusInternalServerError)
+			return
+		}
+		routesWriteJSON(w, http.StatusOK, result)
+	})
+}
+
+func routesWriteError(w http.ResponseWriter, err error, status int) {
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		status = coder.StatusCode()
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func routesWriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
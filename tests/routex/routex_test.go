@@ -0,0 +1,66 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegisterUserControllerRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterUserControllerRoutes(mux, &UserController{
+		Users: map[int]*User{1: {ID: 1, Name: "photowey"}},
+	})
+
+	tests := []struct {
+		method     string
+		target     string
+		body       string
+		header     map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{method: http.MethodGet, target: "/users/1", wantStatus: http.StatusOK, wantBody: `{"id":1,"name":"photowey"}`},
+		{method: http.MethodGet, target: "/users/2", wantStatus: http.StatusNotFound, wantBody: "user 2 not found"},
+		{method: http.MethodGet, target: "/users/x", wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/users?page=0&tag=a", wantStatus: http.StatusOK, wantBody: `[{"id":1,"name":"photowey"}]`},
+		{method: http.MethodPost, target: "/users", body: `{"id":3,"name":"go"}`, wantStatus: http.StatusCreated, wantBody: `{"id":3,"name":"go"}`},
+		{method: http.MethodGet, target: "/users/1/name", wantStatus: http.StatusOK, wantBody: "photowey"},
+		{method: http.MethodDelete, target: "/users/1", wantStatus: http.StatusNotFound},
+		{method: http.MethodDelete, target: "/users/1", header: map[string]string{"X-Token": "t"}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package routex serves the users over HTTP.
+//
+// @OpenAPI(title="Users", version="1.2.0")
+package routex
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/photowey/parsergo/tests/routex/geox"
+)
+
+// User is a registered user.
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name" validate:"required,min=1,max=64"`
+	// Email is where the user is notified.
+	Email     string        `json:"email,omitempty" validate:"omitempty,email"`
+	Role      Role          `json:"role,omitempty"`
+	Tags      []string      `json:"tags,omitempty" validate:"max=5,dive,min=1"`
+	Address   *geox.Address `json:"address,omitempty"`
+	CreatedAt *time.Time    `json:"createdAt,omitempty"`
+	password  string
+}
+
+// Role is what a user may do.
+type Role string
+
+const (
+	// RoleAdmin manages the users.
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member" // the default
+)
+
+type ListQuery struct {
+	Page  int      `query:"page" validate:"min=0"`
+	Tags  []string `query:"tag"`
+	Token string   `header:"X-Token"`
+}
+
+type NotFound struct {
+	ID int
+}
+
+func (e *NotFound) Error() string {
+	return fmt.Sprintf("user %d not found", e.ID)
+}
+
+func (e *NotFound) StatusCode() int {
+	return http.StatusNotFound
+}
+
+// UserController serves the users.
+//
+// @Controller("/users")
+type UserController struct {
+	Users map[int]*User
+}
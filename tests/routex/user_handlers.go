@@ -0,0 +1,80 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routex
+
+import (
+	"context"
+	"net/http"
+)
+
+// Get returns a user.
+//
+// @Get("/{id}")
+func (c *UserController) Get(ctx context.Context, id int) (*User, error) {
+	if user, ok := c.Users[id]; ok {
+		return user, nil
+	}
+
+	return nil, &NotFound{ID: id}
+}
+
+// List returns a page of users.
+//
+// @Get
+func (c *UserController) List(query ListQuery) []*User {
+	users := make([]*User, 0)
+	for id := query.Page * 10; id < query.Page*10+10; id++ {
+		if user, ok := c.Users[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
+// Create adds a user.
+//
+// @Post
+// @Status(201)
+func (c *UserController) Create(user *User) (*User, error) {
+	c.Users[user.ID] = user
+
+	return user, nil
+}
+
+// Delete removes a user.
+//
+// @Delete("/{id}")
+// @Header(token, "X-Token")
+func (c *UserController) Delete(id int, token string) error {
+	if token == "" {
+		return &NotFound{ID: id}
+	}
+	delete(c.Users, id)
+
+	return nil
+}
+
+// Raw writes the name of a user itself.
+//
+// @Get("/{id}/name")
+// @Path(key, "id")
+func (c *UserController) Raw(w http.ResponseWriter, r *http.Request, key uint8) {
+	if user, ok := c.Users[int(key)]; ok {
+		_, _ = w.Write([]byte(user.Name))
+	}
+}
@@ -0,0 +1,123 @@
+//go:build !ignore_autogenerated
+
+// Code generated by parsergo routes. DO NOT EDIT.
+
+package routex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// RegisterUserControllerRoutes registers the routes of UserController on mux.
+func RegisterUserControllerRoutes(mux *http.ServeMux, c *UserController) {
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		arg0 := r.Context()
+		var arg1 int
+		if raw := r.PathValue("id"); raw != "" {
+			if arg1, err = routesParseInt[int](raw, 0); err != nil {
+				routesWriteError(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		result, err := c.Get(arg0, arg1)
+		if err != nil {
+			routesWriteError(w, err, http.StatusInternalServerError)
+			return
+		}
+		routesWriteJSON(w, http.StatusOK, result)
+	})
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		var arg0 ListQuery
+		if raw := r.URL.Query().Get("page"); raw != "" {
+			if arg0.Page, err = routesParseInt[int](raw, 0); err != nil {
+				routesWriteError(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		for _, raw := range r.URL.Query()["tag"] {
+			var item string
+			item = raw
+			arg0.Tags = append(arg0.Tags, item)
+		}
+		if raw := r.Header.Get("X-Token"); raw != "" {
+			arg0.Token = raw
+		}
+		result := c.List(arg0)
+		routesWriteJSON(w, http.StatusOK, result)
+	})
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		arg0 := new(User)
+		if err = json.NewDecoder(r.Body).Decode(arg0); err != nil {
+			routesWriteError(w, err, http.StatusBadRequest)
+			return
+		}
+		result, err := c.Create(arg0)
+		if err != nil {
+			routesWriteError(w, err, http.StatusInternalServerError)
+			return
+		}
+		routesWriteJSON(w, http.StatusCreated, result)
+	})
+	mux.HandleFunc("DELETE /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		var arg0 int
+		if raw := r.PathValue("id"); raw != "" {
+			if arg0, err = routesParseInt[int](raw, 0); err != nil {
+				routesWriteError(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		var arg1 string
+		if raw := r.Header.Get("X-Token"); raw != "" {
+			arg1 = raw
+		}
+		if err := c.Delete(arg0, arg1); err != nil {
+			routesWriteError(w, err, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /users/{id}/name", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		arg0 := w
+		arg1 := r
+		var arg2 uint8
+		if raw := r.PathValue("id"); raw != "" {
+			if arg2, err = routesParseUint[uint8](raw, 8); err != nil {
+				routesWriteError(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		c.Raw(arg0, arg1, arg2)
+	})
+}
+
+func routesWriteError(w http.ResponseWriter, err error, status int) {
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		status = coder.StatusCode()
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func routesWriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func routesParseInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](raw string, bits int) (T, error) {
+	v, err := strconv.ParseInt(raw, 10, bits)
+	return T(v), err
+}
+
+func routesParseUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](raw string, bits int) (T, error) {
+	v, err := strconv.ParseUint(raw, 10, bits)
+	return T(v), err
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schemax
+
+import (
+	"time"
+)
+
+// ServerConfig configures a server.
+//
+// @Schema(id="https://github.com/photowey/parsergo/tests/schemax/server_config")
+type ServerConfig struct {
+	Name string `json:"name" validate:"required,alphanum"`
+	// Port is the port to listen on.
+	//
+	// @Min(1)
+	// @Max(65535)
+	Port    int               `json:"port,omitempty"`
+	Level   Level             `json:"level"`
+	Timeout time.Duration     `json:"timeout,omitempty" validate:"gt=0"`
+	TLS     *TLS              `json:"tls,omitempty"`
+	Routes  []Route           `json:"routes" validate:"min=1"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	// Fallback is tried when the server is down.
+	Fallback *ServerConfig `json:"fallback,omitempty"`
+	Options
+}
+
+type TLS struct {
+	CertFile string  `json:"certFile"`
+	KeyFile  *string `json:"keyFile"`
+}
+
+type Route struct {
+	Path    string   `json:"path" validate:"required"`
+	Methods []string `json:"methods,omitempty" validate:"dive,oneof=GET POST"`
+}
+
+type Options struct {
+	Debug bool `json:"debug,omitempty"`
+	Retry uint8
+}
+
+// Level is the log level.
+type Level int
+
+const (
+	LevelDebug Level = iota - 1 // verbose
+	LevelInfo
+	LevelError
+)
+
+// @Schema("retry.json")
+type Retry struct {
+	Attempts int `json:"attempts" validate:"min=1,max=5"`
+}
+
+// Endpoint is reached by its URL.
+//
+// @Schema("endpoint.json")
+type Endpoint struct {
+	Base
+	Meta
+	// Name shadows the name of Base.
+	Name string `json:"name"`
+	URL  string `json:"url" validate:"required,url"`
+}
+
+// Base and Meta are embedded at the same depth.
+type Base struct {
+	Name    string `json:"name"`
+	Kind    string `json:"Type,omitempty"`
+	Version int
+}
+
+type Meta struct {
+	// Type loses to the tagged Kind of Base.
+	Type string
+	// Version conflicts with the one of Base, neither is marshaled.
+	Version int
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright © 2022 photowey (photowey@gmail.com)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package typex
+
+import (
+	. "time"
+)
+
+// Schedule refers to the dot-imported time types.
+// @Component
+type Schedule struct {
+	Every Duration
+	At    Month
+	Next  *Schedule
+}
+
+// Page is generic.
+// @Component
+type Page[T any] struct {
+	Items []T
+	Total int
+}
+
+func (p *Page[E]) First() E {
+	return p.Items[0]
+}
+
+func (p *Page[E]) Append(items ...E) *Page[E] {
+	p.Items = append(p.Items, items...)
+
+	return p
+}